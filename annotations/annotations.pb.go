@@ -22,14 +22,15 @@ var _ = math.Inf
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
 type Service struct {
-	Package              string   `protobuf:"bytes,1,opt,name=package,proto3" json:"package,omitempty"`
-	Name                 string   `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Host                 string   `protobuf:"bytes,3,opt,name=host,proto3" json:"host,omitempty"`
-	Transport            string   `protobuf:"bytes,4,opt,name=transport,proto3" json:"transport,omitempty"`
-	Codec                string   `protobuf:"bytes,5,opt,name=codec,proto3" json:"codec,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Package              string            `protobuf:"bytes,1,opt,name=package,proto3" json:"package,omitempty"`
+	Name                 string            `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Host                 string            `protobuf:"bytes,3,opt,name=host,proto3" json:"host,omitempty"`
+	Transport            string            `protobuf:"bytes,4,opt,name=transport,proto3" json:"transport,omitempty"`
+	Codec                string            `protobuf:"bytes,5,opt,name=codec,proto3" json:"codec,omitempty"`
+	Options              map[string]string `protobuf:"bytes,6,rep,name=options,proto3" json:"options,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
 }
 
 func (m *Service) Reset()         { *m = Service{} }
@@ -92,6 +93,13 @@ func (m *Service) GetCodec() string {
 	return ""
 }
 
+func (m *Service) GetOptions() map[string]string {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
 type HTTP struct {
 	Endpoint             string   `protobuf:"bytes,1,opt,name=endpoint,proto3" json:"endpoint,omitempty"`
 	Method               string   `protobuf:"bytes,2,opt,name=method,proto3" json:"method,omitempty"`
@@ -159,6 +167,7 @@ var E_Http = &proto.ExtensionDesc{
 
 func init() {
 	proto.RegisterType((*Service)(nil), "maestro.Service")
+	proto.RegisterMapType((map[string]string)(nil), "maestro.Service.OptionsEntry")
 	proto.RegisterType((*HTTP)(nil), "maestro.HTTP")
 	proto.RegisterExtension(E_Service)
 	proto.RegisterExtension(E_Http)
@@ -169,24 +178,27 @@ func init() {
 }
 
 var fileDescriptor_21dfaf6fd39fa3b7 = []byte{
-	// 295 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x64, 0x51, 0x4f, 0x4b, 0xfc, 0x30,
-	0x10, 0x65, 0x7f, 0xbf, 0xee, 0xd6, 0x8d, 0x08, 0x12, 0x44, 0xc2, 0xe2, 0x9f, 0x65, 0x11, 0xf1,
-	0x94, 0x82, 0xde, 0x7a, 0xd4, 0x8b, 0x97, 0x45, 0xa9, 0x7b, 0xf2, 0x96, 0xa6, 0xb1, 0x8d, 0xda,
-	0x4c, 0x48, 0x66, 0xc5, 0x9b, 0x37, 0xbf, 0xa4, 0x7e, 0x18, 0x69, 0x9a, 0xae, 0x0b, 0xde, 0xe6,
-	0xbd, 0x99, 0xbc, 0x79, 0x6f, 0x42, 0x8e, 0x85, 0x31, 0x80, 0x02, 0x35, 0x18, 0x9f, 0x6d, 0xd5,
-	0xdc, 0x3a, 0x40, 0xa0, 0x69, 0x2b, 0x94, 0x47, 0x07, 0xb3, 0x79, 0x0d, 0x50, 0xbf, 0xaa, 0x2c,
-	0xd0, 0xe5, 0xfa, 0x29, 0xab, 0x94, 0x97, 0x4e, 0x5b, 0x04, 0xd7, 0x8f, 0x2e, 0x3e, 0x48, 0xfa,
-	0xa0, 0xdc, 0x9b, 0x96, 0x8a, 0x32, 0x92, 0x5a, 0x21, 0x5f, 0x44, 0xad, 0xd8, 0x68, 0x3e, 0xba,
-	0x98, 0x16, 0x03, 0xa4, 0x94, 0x24, 0x46, 0xb4, 0x8a, 0xfd, 0x0b, 0x74, 0xa8, 0x3b, 0xae, 0x01,
-	0x8f, 0xec, 0x7f, 0xcf, 0x75, 0x35, 0x3d, 0x22, 0x53, 0x74, 0xc2, 0x78, 0x0b, 0x0e, 0x59, 0x12,
-	0x1a, 0xbf, 0x04, 0x3d, 0x20, 0x63, 0x09, 0x95, 0x92, 0x6c, 0x1c, 0x3a, 0x3d, 0x58, 0xe4, 0x24,
-	0xb9, 0x5d, 0xad, 0xee, 0xe9, 0x8c, 0xec, 0x28, 0x53, 0x59, 0xd0, 0x06, 0xe3, 0xfa, 0x0d, 0xa6,
-	0x87, 0x64, 0xd2, 0x2a, 0x6c, 0xa0, 0x8a, 0x0e, 0x22, 0xca, 0x97, 0x24, 0xf5, 0xd1, 0xfc, 0x29,
-	0xef, 0xa3, 0xf2, 0x21, 0x2a, 0x8f, 0xb1, 0xee, 0x6c, 0xb8, 0x0c, 0xfb, 0xfe, 0xec, 0x9c, 0xee,
-	0x5e, 0xee, 0xf3, 0x78, 0x9c, 0x61, 0xa0, 0x18, 0x34, 0xf2, 0x1b, 0x92, 0x34, 0x88, 0x96, 0x9e,
-	0xfc, 0xd1, 0x5a, 0x86, 0x7d, 0x83, 0xd4, 0x57, 0x94, 0xda, 0xdb, 0x48, 0x75, 0x09, 0x8a, 0xf0,
-	0xf8, 0xfa, 0xfc, 0xf1, 0xac, 0xd6, 0xd8, 0xac, 0x4b, 0x2e, 0xa1, 0xcd, 0x9e, 0xd5, 0xbb, 0x16,
-	0x59, 0x1c, 0xdb, 0xfe, 0xa9, 0x72, 0x12, 0xc4, 0xaf, 0x7e, 0x02, 0x00, 0x00, 0xff, 0xff, 0x77,
-	0x5f, 0x18, 0xde, 0xcb, 0x01, 0x00, 0x00,
+	// 348 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x64, 0x52, 0xc1, 0x4a, 0xeb, 0x40,
+	0x14, 0x25, 0x6d, 0xda, 0xbc, 0x4e, 0xdf, 0x83, 0x32, 0x3c, 0x64, 0x28, 0x56, 0x4b, 0x11, 0xe9,
+	0x2a, 0x81, 0xba, 0x50, 0xb2, 0x54, 0x04, 0x37, 0x45, 0x89, 0x5d, 0xb9, 0x9b, 0x26, 0x63, 0x12,
+	0xdb, 0xcc, 0x0d, 0x93, 0xdb, 0x62, 0x7f, 0xc0, 0x9f, 0xd4, 0xcf, 0xf0, 0x03, 0x24, 0x93, 0x99,
+	0x5a, 0x74, 0x77, 0xcf, 0xb9, 0xf7, 0x9e, 0x39, 0xf7, 0x30, 0x64, 0xc4, 0xa5, 0x04, 0xe4, 0x98,
+	0x83, 0xac, 0x82, 0x83, 0xda, 0x2f, 0x15, 0x20, 0x50, 0xaf, 0xe0, 0xa2, 0x42, 0x05, 0xc3, 0x71,
+	0x0a, 0x90, 0xae, 0x45, 0xa0, 0xe9, 0xe5, 0xe6, 0x39, 0x48, 0x44, 0x15, 0xab, 0xbc, 0x44, 0x50,
+	0xcd, 0xe8, 0xe4, 0xd3, 0x21, 0xde, 0xa3, 0x50, 0xdb, 0x3c, 0x16, 0x94, 0x11, 0xaf, 0xe4, 0xf1,
+	0x8a, 0xa7, 0x82, 0x39, 0x63, 0x67, 0xda, 0x8b, 0x2c, 0xa4, 0x94, 0xb8, 0x92, 0x17, 0x82, 0xb5,
+	0x34, 0xad, 0xeb, 0x9a, 0xcb, 0xa0, 0x42, 0xd6, 0x6e, 0xb8, 0xba, 0xa6, 0xc7, 0xa4, 0x87, 0x8a,
+	0xcb, 0xaa, 0x04, 0x85, 0xcc, 0xd5, 0x8d, 0x6f, 0x82, 0xfe, 0x27, 0x9d, 0x18, 0x12, 0x11, 0xb3,
+	0x8e, 0xee, 0x34, 0x80, 0x5e, 0x12, 0x0f, 0x4a, 0xed, 0x9e, 0x75, 0xc7, 0xed, 0x69, 0x7f, 0x36,
+	0xf2, 0x8d, 0x7d, 0xdf, 0x18, 0xf3, 0xef, 0x9b, 0xfe, 0xad, 0x44, 0xb5, 0x8b, 0xec, 0xf4, 0x30,
+	0x24, 0x7f, 0x0f, 0x1b, 0x74, 0x40, 0xda, 0x2b, 0xb1, 0x33, 0xd6, 0xeb, 0xb2, 0x7e, 0x70, 0xcb,
+	0xd7, 0x1b, 0xeb, 0xbb, 0x01, 0x61, 0xeb, 0xca, 0x99, 0x84, 0xc4, 0xbd, 0x5b, 0x2c, 0x1e, 0xe8,
+	0x90, 0xfc, 0x11, 0x32, 0x29, 0x21, 0x97, 0x68, 0x16, 0xf7, 0x98, 0x1e, 0x91, 0x6e, 0x21, 0x30,
+	0x83, 0xc4, 0xac, 0x1b, 0x14, 0xce, 0x89, 0x57, 0x99, 0xc4, 0x4e, 0xfd, 0x26, 0x60, 0xdf, 0x06,
+	0x6c, 0x2d, 0x1b, 0x63, 0xec, 0xe3, 0xad, 0x8e, 0xa7, 0x3f, 0x1b, 0xfc, 0xbc, 0x29, 0xb2, 0x1a,
+	0xe1, 0x0d, 0x71, 0x33, 0xc4, 0x92, 0x9e, 0xfc, 0xd2, 0x9a, 0xeb, 0xf7, 0xac, 0xd4, 0xbb, 0x91,
+	0xfa, 0xb7, 0x97, 0xaa, 0x2f, 0x88, 0xf4, 0xf2, 0xf5, 0xf9, 0xd3, 0x59, 0x9a, 0x63, 0xb6, 0x59,
+	0xfa, 0x31, 0x14, 0xc1, 0x8b, 0x78, 0xcd, 0x79, 0x60, 0xc6, 0x0e, 0xff, 0xc7, 0xb2, 0xab, 0xc5,
+	0x2f, 0xbe, 0x06, 0x00, 0x7b, 0xbc, 0x05, 0x77, 0x41, 0x02, 0x00, 0x00,
 }
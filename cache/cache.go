@@ -0,0 +1,22 @@
+package cache
+
+import (
+	"time"
+
+	"github.com/jexia/maestro/refs"
+)
+
+// Cache represents a store of decoded resource responses
+type Cache interface {
+	// Get returns the values stored for the given key.
+	// False is returned when the key has not been found or has expired.
+	Get(key string) ([]*refs.Snapshot, bool)
+	// Set stores the given values for the given key until the given TTL has passed
+	Set(key string, values []*refs.Snapshot, ttl time.Duration)
+}
+
+// Constructor constructs new caches
+type Constructor interface {
+	// New constructs a new cache holding at most the given amount of entries
+	New(size int) Cache
+}
@@ -0,0 +1,110 @@
+package lru
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/jexia/maestro/cache"
+	"github.com/jexia/maestro/refs"
+)
+
+// NewConstructor constructs a new in-memory LRU cache constructor
+func NewConstructor() cache.Constructor {
+	return &Constructor{}
+}
+
+// Constructor constructs new in-memory LRU caches
+type Constructor struct{}
+
+// New constructs a new in-memory LRU cache holding at most the given amount of entries
+func (constructor *Constructor) New(size int) cache.Cache {
+	return New(size)
+}
+
+// New constructs a new in-memory LRU cache holding at most the given amount of entries.
+// The least recently used entry is evicted once the cache is full.
+func New(size int) *Cache {
+	return &Cache{
+		now:     time.Now,
+		size:    size,
+		entries: make(map[string]*list.Element, size),
+		order:   list.New(),
+	}
+}
+
+// Cache represents a in-memory LRU cache
+type Cache struct {
+	now     func() time.Time
+	size    int
+	mutex   sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List
+}
+
+// Entry represents a single cached value
+type Entry struct {
+	Key     string
+	Values  []*refs.Snapshot
+	Expires time.Time
+}
+
+// Get returns the values stored for the given key.
+// Expired entries are removed from the cache.
+func (cache *Cache) Get(key string) ([]*refs.Snapshot, bool) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	element, has := cache.entries[key]
+	if !has {
+		return nil, false
+	}
+
+	entry := element.Value.(*Entry)
+	if !cache.now().Before(entry.Expires) {
+		cache.order.Remove(element)
+		delete(cache.entries, key)
+		return nil, false
+	}
+
+	cache.order.MoveToFront(element)
+	return entry.Values, true
+}
+
+// Set stores the given values for the given key until the given TTL has passed.
+// The least recently used entry is evicted when the cache is full.
+func (cache *Cache) Set(key string, values []*refs.Snapshot, ttl time.Duration) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	expires := cache.now().Add(ttl)
+
+	element, has := cache.entries[key]
+	if has {
+		entry := element.Value.(*Entry)
+		entry.Values = values
+		entry.Expires = expires
+		cache.order.MoveToFront(element)
+		return
+	}
+
+	cache.entries[key] = cache.order.PushFront(&Entry{
+		Key:     key,
+		Values:  values,
+		Expires: expires,
+	})
+
+	for cache.size > 0 && cache.order.Len() > cache.size {
+		oldest := cache.order.Back()
+		cache.order.Remove(oldest)
+		delete(cache.entries, oldest.Value.(*Entry).Key)
+	}
+}
+
+// Len returns the amount of entries stored inside the cache
+func (cache *Cache) Len() int {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	return cache.order.Len()
+}
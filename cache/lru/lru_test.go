@@ -0,0 +1,100 @@
+package lru
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jexia/maestro/refs"
+)
+
+func TestGetSet(t *testing.T) {
+	cache := New(10)
+	expected := []*refs.Snapshot{{Resource: "product", Path: "name", Value: "shoe"}}
+
+	cache.Set("key", expected, time.Minute)
+
+	values, has := cache.Get("key")
+	if !has {
+		t.Fatal("cache entry not found")
+	}
+
+	if len(values) != 1 || values[0] != expected[0] {
+		t.Fatalf("unexpected values %+v", values)
+	}
+
+	_, has = cache.Get("unknown")
+	if has {
+		t.Fatal("unexpected cache entry")
+	}
+}
+
+func TestExpire(t *testing.T) {
+	now := time.Now()
+	cache := New(10)
+	cache.now = func() time.Time { return now }
+
+	cache.Set("key", nil, time.Second)
+
+	now = now.Add(time.Second)
+
+	_, has := cache.Get("key")
+	if has {
+		t.Fatal("unexpected expired cache entry")
+	}
+
+	if cache.Len() != 0 {
+		t.Fatalf("unexpected cache length %d, expected expired entry to be removed", cache.Len())
+	}
+}
+
+func TestEvict(t *testing.T) {
+	cache := New(2)
+
+	cache.Set("first", nil, time.Minute)
+	cache.Set("second", nil, time.Minute)
+
+	// mark first as most recently used
+	cache.Get("first")
+
+	cache.Set("third", nil, time.Minute)
+
+	if cache.Len() != 2 {
+		t.Fatalf("unexpected cache length %d, expected 2", cache.Len())
+	}
+
+	_, has := cache.Get("second")
+	if has {
+		t.Fatal("expected the least recently used entry to be evicted")
+	}
+
+	for _, key := range []string{"first", "third"} {
+		_, has := cache.Get(key)
+		if !has {
+			t.Fatalf("cache entry %s not found", key)
+		}
+	}
+}
+
+func TestOverride(t *testing.T) {
+	cache := New(2)
+	expected := []*refs.Snapshot{{Resource: "product", Path: "name", Value: "shoe"}}
+
+	cache.Set("key", nil, time.Minute)
+	cache.Set("key", expected, time.Minute)
+
+	if cache.Len() != 1 {
+		t.Fatalf("unexpected cache length %d, expected 1", cache.Len())
+	}
+
+	values, _ := cache.Get("key")
+	if len(values) != 1 {
+		t.Fatalf("unexpected values %+v", values)
+	}
+}
+
+func TestConstructor(t *testing.T) {
+	cache := NewConstructor().New(1)
+	if cache == nil {
+		t.Fatal("unexpected nil cache")
+	}
+}
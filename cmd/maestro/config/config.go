@@ -3,6 +3,11 @@ package config
 import (
 	"os"
 
+	"github.com/jexia/maestro/secrets"
+	"github.com/jexia/maestro/secrets/env"
+	"github.com/jexia/maestro/secrets/file"
+	"github.com/jexia/maestro/secrets/vault"
+	"github.com/jexia/maestro/specs/trace"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v2"
 )
@@ -14,6 +19,9 @@ func New() *Maestro {
 		GraphQL:      GraphQL{},
 		Protobuffers: []string{},
 		Flows:        []string{},
+		Tracing:      Tracing{},
+		Metrics:      Metrics{},
+		Secrets:      Secrets{},
 	}
 }
 
@@ -42,6 +50,28 @@ func Read(cmd *cobra.Command, target *Maestro) error {
 	return nil
 }
 
+// SecretProvider constructs the configured secret provider.
+// Nil is returned if no secret provider has been configured.
+func SecretProvider(target Secrets) (secrets.SecretProvider, error) {
+	switch target.Provider {
+	case "":
+		return nil, nil
+	case "env":
+		return env.New(target.Prefix), nil
+	case "file":
+		return file.New(target.Dir), nil
+	case "vault":
+		token := target.Vault.Token
+		if token == "" {
+			token = os.Getenv("VAULT_TOKEN")
+		}
+
+		return vault.New(target.Vault.Address, token, target.Vault.Mount), nil
+	}
+
+	return nil, trace.New(trace.WithMessage("unknown secret provider '%s', expected env, file or vault", target.Provider))
+}
+
 // Maestro configurations
 type Maestro struct {
 	LogLevel     string   `yaml:"level"`
@@ -49,6 +79,11 @@ type Maestro struct {
 	GraphQL      GraphQL  `yaml:"graphql"`
 	Protobuffers []string `yaml:"protobuffers"`
 	Flows        []string `yaml:"flows"`
+	Journal      string   `yaml:"journal"`
+	DeadLetter   string   `yaml:"dead_letter"`
+	Tracing      Tracing  `yaml:"tracing"`
+	Metrics      Metrics  `yaml:"metrics"`
+	Secrets      Secrets  `yaml:"secrets"`
 }
 
 // HTTP configurations
@@ -60,3 +95,29 @@ type HTTP struct {
 type GraphQL struct {
 	Address string `yaml:"address"`
 }
+
+// Tracing configurations
+type Tracing struct {
+	File string `yaml:"file"`
+	OTLP string `yaml:"otlp"`
+}
+
+// Metrics configurations
+type Metrics struct {
+	Address string `yaml:"address"`
+}
+
+// Secrets configurations
+type Secrets struct {
+	Provider string `yaml:"provider"`
+	Prefix   string `yaml:"prefix"`
+	Dir      string `yaml:"dir"`
+	Vault    Vault  `yaml:"vault"`
+}
+
+// Vault configurations
+type Vault struct {
+	Address string `yaml:"address"`
+	Token   string `yaml:"token"`
+	Mount   string `yaml:"mount"`
+}
@@ -0,0 +1,109 @@
+package graph
+
+import (
+	"context"
+
+	"github.com/jexia/maestro"
+	"github.com/jexia/maestro/cmd/maestro/config"
+	"github.com/jexia/maestro/codec/json"
+	"github.com/jexia/maestro/codec/proto"
+	"github.com/jexia/maestro/constructor"
+	"github.com/jexia/maestro/definitions/hcl"
+	"github.com/jexia/maestro/functions"
+	"github.com/jexia/maestro/graph"
+	"github.com/jexia/maestro/logger"
+	"github.com/jexia/maestro/schema/protoc"
+	"github.com/jexia/maestro/specs/trace"
+	"github.com/jexia/maestro/transport/http"
+	"github.com/spf13/cobra"
+)
+
+// Available graph formats
+const (
+	FormatDOT     = "dot"
+	FormatMermaid = "mermaid"
+)
+
+var global = config.New()
+var format string
+
+// Cmd represents the maestro graph command
+var Cmd = &cobra.Command{
+	Use:          "graph [flow...]",
+	Short:        "Export the resolved resource graph of the flow definitions as Graphviz DOT or Mermaid",
+	RunE:         run,
+	SilenceUsage: true,
+}
+
+func init() {
+	Cmd.PersistentFlags().StringP("config", "c", "", "Config file path")
+	Cmd.PersistentFlags().StringSliceVar(&global.Protobuffers, "proto", []string{}, "If set are all proto definitions found inside the given path passed as schema definitions, all proto definitions are also passed as imports")
+	Cmd.PersistentFlags().StringSliceVar(&global.Flows, "flow", []string{}, "If set are all flow definitions inside the given path passed as flow definitions")
+	Cmd.PersistentFlags().StringVar(&global.Secrets.Provider, "secrets", "", "If set are secret references resolved through the given secret provider (env, file or vault)")
+	Cmd.PersistentFlags().StringVar(&global.Secrets.Prefix, "secrets-prefix", "", "Prefix of the environment variables read by the env secret provider")
+	Cmd.PersistentFlags().StringVar(&global.Secrets.Dir, "secrets-dir", "", "Directory containing the secret files read by the file secret provider")
+	Cmd.PersistentFlags().StringVar(&global.Secrets.Vault.Address, "vault-address", "", "Address of the Vault compatible HTTP API read by the vault secret provider")
+	Cmd.PersistentFlags().StringVar(&global.Secrets.Vault.Mount, "vault-mount", "", "Mount path of the key/value secrets engine read by the vault secret provider")
+	Cmd.PersistentFlags().StringVar(&global.LogLevel, "level", "error", "Logging level")
+	Cmd.PersistentFlags().StringVar(&format, "format", FormatDOT, "Graph output format (dot or mermaid)")
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	err := config.Read(cmd, global)
+	if err != nil {
+		return err
+	}
+
+	if format != FormatDOT && format != FormatMermaid {
+		return trace.New(trace.WithMessage("unknown graph format '%s', expected '%s' or '%s'", format, FormatDOT, FormatMermaid))
+	}
+
+	options := []constructor.Option{
+		maestro.WithLogLevel(logger.Global, global.LogLevel),
+		maestro.WithFunctions(functions.Default),
+		maestro.WithCodec(json.NewConstructor()),
+		maestro.WithCodec(proto.NewConstructor()),
+		maestro.WithCaller(http.NewCaller()),
+	}
+
+	for _, flow := range global.Flows {
+		options = append(options, maestro.WithDefinitions(hcl.DefinitionResolver(flow)))
+	}
+
+	for _, path := range global.Protobuffers {
+		resolver, err := protoc.Collect(global.Protobuffers, path)
+		if err != nil {
+			return err
+		}
+
+		options = append(options, maestro.WithSchema(resolver))
+	}
+
+	provider, err := config.SecretProvider(global.Secrets)
+	if err != nil {
+		return err
+	}
+
+	if provider != nil {
+		options = append(options, maestro.WithSecrets(provider))
+	}
+
+	ctx := context.Background()
+	ctx = logger.WithValue(ctx)
+
+	manifest, err := constructor.Specs(ctx, constructor.NewOptions(ctx, options...))
+	if err != nil {
+		return err
+	}
+
+	result, err := graph.New(ctx, manifest, args...)
+	if err != nil {
+		return err
+	}
+
+	if format == FormatMermaid {
+		return result.Mermaid(cmd.OutOrStdout())
+	}
+
+	return result.DOT(cmd.OutOrStdout())
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"os"
 
+	"github.com/jexia/maestro/cmd/maestro/graph"
 	"github.com/jexia/maestro/cmd/maestro/run"
 	"github.com/jexia/maestro/cmd/maestro/validate"
 	"github.com/spf13/cobra"
@@ -18,6 +19,7 @@ Requests could be manipulated, passed and branched to different services to be r
 }
 
 func init() {
+	cmd.AddCommand(graph.Cmd)
 	cmd.AddCommand(run.Cmd)
 	cmd.AddCommand(validate.Cmd)
 }
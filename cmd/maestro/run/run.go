@@ -10,10 +10,16 @@ import (
 	"github.com/jexia/maestro/codec/json"
 	"github.com/jexia/maestro/codec/proto"
 	"github.com/jexia/maestro/constructor"
+	deadletter "github.com/jexia/maestro/deadletter/file"
 	"github.com/jexia/maestro/definitions/hcl"
+	"github.com/jexia/maestro/functions"
+	"github.com/jexia/maestro/journal/file"
 	"github.com/jexia/maestro/logger"
+	"github.com/jexia/maestro/metrics"
 	"github.com/jexia/maestro/schema/protoc"
 	"github.com/jexia/maestro/specs"
+	traces "github.com/jexia/maestro/tracing/file"
+	"github.com/jexia/maestro/tracing/otlp"
 	"github.com/jexia/maestro/transport/graphql"
 	"github.com/jexia/maestro/transport/http"
 	"github.com/jexia/maestro/transport/micro"
@@ -36,6 +42,16 @@ func init() {
 	Cmd.PersistentFlags().StringVar(&global.GraphQL.Address, "graphql", "", "If set starts the GraphQL listener on the given TCP address")
 	Cmd.PersistentFlags().StringSliceVar(&global.Protobuffers, "proto", []string{}, "If set are all proto definitions found inside the given path passed as schema definitions, all proto definitions are also passed as imports")
 	Cmd.PersistentFlags().StringSliceVar(&global.Flows, "flow", []string{}, "If set are all flow definitions inside the given path passed as flow definitions")
+	Cmd.PersistentFlags().StringVar(&global.Journal, "journal", "", "If set are flow executions recorded inside the given journal file and pending rollbacks resumed on start")
+	Cmd.PersistentFlags().StringVar(&global.DeadLetter, "dead-letter", "", "If set are rollbacks which could not be compensated appended to the given dead letter file")
+	Cmd.PersistentFlags().StringVar(&global.Tracing.File, "trace-file", "", "If set are the spans of all flow executions appended to the given trace file")
+	Cmd.PersistentFlags().StringVar(&global.Tracing.OTLP, "trace-otlp", "", "If set are the spans of all flow executions exported to the given OTLP/HTTP endpoint")
+	Cmd.PersistentFlags().StringVar(&global.Metrics.Address, "metrics", "", "If set are the Prometheus metrics exposed on the /metrics path of the given TCP address")
+	Cmd.PersistentFlags().StringVar(&global.Secrets.Provider, "secrets", "", "If set are secret references resolved through the given secret provider (env, file or vault)")
+	Cmd.PersistentFlags().StringVar(&global.Secrets.Prefix, "secrets-prefix", "", "Prefix of the environment variables read by the env secret provider")
+	Cmd.PersistentFlags().StringVar(&global.Secrets.Dir, "secrets-dir", "", "Directory containing the secret files read by the file secret provider")
+	Cmd.PersistentFlags().StringVar(&global.Secrets.Vault.Address, "vault-address", "", "Address of the Vault compatible HTTP API read by the vault secret provider")
+	Cmd.PersistentFlags().StringVar(&global.Secrets.Vault.Mount, "vault-mount", "", "Mount path of the key/value secrets engine read by the vault secret provider")
 	Cmd.PersistentFlags().StringVar(&global.LogLevel, "level", "info", "Logging level")
 }
 
@@ -47,6 +63,7 @@ func run(cmd *cobra.Command, args []string) error {
 
 	options := []constructor.Option{
 		maestro.WithLogLevel(logger.Global, global.LogLevel),
+		maestro.WithFunctions(functions.Default),
 		maestro.WithCodec(json.NewConstructor()),
 		maestro.WithCodec(proto.NewConstructor()),
 		maestro.WithCaller(micro.New("micro-grpc", grpc.NewService())),
@@ -74,6 +91,50 @@ func run(cmd *cobra.Command, args []string) error {
 		options = append(options, maestro.WithListener(graphql.NewListener(global.GraphQL.Address, specs.Options{})))
 	}
 
+	provider, err := config.SecretProvider(global.Secrets)
+	if err != nil {
+		return err
+	}
+
+	if provider != nil {
+		options = append(options, maestro.WithSecrets(provider))
+	}
+
+	if global.Journal != "" {
+		journal, err := file.Open(global.Journal)
+		if err != nil {
+			return err
+		}
+
+		options = append(options, maestro.WithJournal(journal))
+	}
+
+	if global.DeadLetter != "" {
+		sink, err := deadletter.Open(global.DeadLetter)
+		if err != nil {
+			return err
+		}
+
+		options = append(options, maestro.WithDeadLetter(sink))
+	}
+
+	if global.Tracing.File != "" {
+		exporter, err := traces.Open(global.Tracing.File)
+		if err != nil {
+			return err
+		}
+
+		options = append(options, maestro.WithTracing(exporter))
+	}
+
+	if global.Tracing.OTLP != "" {
+		options = append(options, maestro.WithTracing(otlp.New(global.Tracing.OTLP, "maestro")))
+	}
+
+	if global.Metrics.Address != "" {
+		options = append(options, maestro.WithMetrics(metrics.New(global.Metrics.Address)))
+	}
+
 	client, err := maestro.New(options...)
 	if err != nil {
 		return err
@@ -9,6 +9,7 @@ import (
 	"github.com/jexia/maestro/codec/proto"
 	"github.com/jexia/maestro/constructor"
 	"github.com/jexia/maestro/definitions/hcl"
+	"github.com/jexia/maestro/functions"
 	"github.com/jexia/maestro/logger"
 	"github.com/jexia/maestro/schema/protoc"
 	"github.com/jexia/maestro/transport/http"
@@ -29,6 +30,11 @@ func init() {
 	Cmd.PersistentFlags().StringP("config", "c", "", "Config file path")
 	Cmd.PersistentFlags().StringSliceVar(&global.Protobuffers, "proto", []string{}, "If set are all proto definitions found inside the given path passed as schema definitions, all proto definitions are also passed as imports")
 	Cmd.PersistentFlags().StringSliceVar(&global.Flows, "flow", []string{}, "If set are all flow definitions inside the given path passed as flow definitions")
+	Cmd.PersistentFlags().StringVar(&global.Secrets.Provider, "secrets", "", "If set are secret references resolved through the given secret provider (env, file or vault)")
+	Cmd.PersistentFlags().StringVar(&global.Secrets.Prefix, "secrets-prefix", "", "Prefix of the environment variables read by the env secret provider")
+	Cmd.PersistentFlags().StringVar(&global.Secrets.Dir, "secrets-dir", "", "Directory containing the secret files read by the file secret provider")
+	Cmd.PersistentFlags().StringVar(&global.Secrets.Vault.Address, "vault-address", "", "Address of the Vault compatible HTTP API read by the vault secret provider")
+	Cmd.PersistentFlags().StringVar(&global.Secrets.Vault.Mount, "vault-mount", "", "Mount path of the key/value secrets engine read by the vault secret provider")
 	Cmd.PersistentFlags().StringVar(&global.LogLevel, "level", "error", "Logging level")
 }
 
@@ -40,6 +46,7 @@ func run(cmd *cobra.Command, args []string) error {
 
 	options := []constructor.Option{
 		maestro.WithLogLevel(logger.Global, global.LogLevel),
+		maestro.WithFunctions(functions.Default),
 		maestro.WithCodec(json.NewConstructor()),
 		maestro.WithCodec(proto.NewConstructor()),
 		maestro.WithCaller(http.NewCaller()),
@@ -58,6 +65,15 @@ func run(cmd *cobra.Command, args []string) error {
 		options = append(options, maestro.WithSchema(resolver))
 	}
 
+	provider, err := config.SecretProvider(global.Secrets)
+	if err != nil {
+		return err
+	}
+
+	if provider != nil {
+		options = append(options, maestro.WithSecrets(provider))
+	}
+
 	ctx := context.Background()
 	_, err = constructor.Specs(ctx, constructor.NewOptions(ctx, options...))
 	if err != nil {
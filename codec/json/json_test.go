@@ -398,6 +398,91 @@ func TestMarshal(t *testing.T) {
 	}
 }
 
+func TestMarshalInterpolation(t *testing.T) {
+	manifest, err := NewMock()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	flow := FindFlow(manifest, "interpolation")
+	specs := FindNode(flow, "first").Call.GetRequest()
+
+	constructor := &Constructor{}
+	manager, err := constructor.New("input", specs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	refs := refs.NewStore(2)
+	refs.StoreValue("input", "message", "hello world")
+	refs.StoreValue("input", "nested.value", "nested value")
+
+	reader, err := manager.Marshal(refs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bb, err := ioutil.ReadAll(reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"message":"message: hello world (nested value)"}`
+	if string(bb) != expected {
+		t.Errorf("unexpected response %s, expected %s", string(bb), expected)
+	}
+}
+
+func TestMarshalIndex(t *testing.T) {
+	manifest, err := NewMock()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	flow := FindFlow(manifest, "index")
+	specs := FindNode(flow, "first").Call.GetRequest()
+
+	constructor := &Constructor{}
+	manager, err := constructor.New("input", specs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	refs := refs.NewStore(1)
+	refs.StoreValues("input", "", map[string]interface{}{
+		"repeating": []map[string]interface{}{
+			{"value": "first"},
+			{"value": "last"},
+		},
+	})
+
+	reader, err := manager.Marshal(refs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result := map[string]interface{}{}
+	err = json.NewDecoder(reader).Decode(&result)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]interface{}{
+		"message": "last",
+		"nested": map[string]interface{}{
+			"value": "first",
+		},
+		"repeating": []interface{}{
+			map[string]interface{}{"value": "first"},
+			map[string]interface{}{"value": "last"},
+		},
+	}
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("unexpected response %+v, expected %+v", result, expected)
+	}
+}
+
 func TestSimple(t *testing.T) {
 	_, err := NewMock()
 	if err != nil {
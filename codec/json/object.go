@@ -51,14 +51,7 @@ func (object *Object) MarshalJSONObject(encoder *gojay.Encoder) {
 			continue
 		}
 
-		val := prop.Default
-
-		if prop.Reference != nil {
-			ref := object.refs.Load(prop.Reference.Resource, prop.Reference.Path)
-			if ref != nil {
-				val = ref.Value
-			}
-		}
+		val := object.refs.Value(prop)
 
 		if val == nil {
 			continue
@@ -144,14 +137,7 @@ func (array *Array) MarshalJSONArray(enc *gojay.Encoder) {
 			continue
 		}
 
-		val := array.specs.Default
-
-		if array.specs.Reference != nil {
-			ref := store.Load(array.specs.Reference.Resource, array.specs.Reference.Path)
-			if ref != nil {
-				val = ref.Value
-			}
-		}
+		val := store.Value(array.specs)
 
 		if val == nil {
 			continue
@@ -30,4 +30,29 @@ flow "repeated" {
 			}
 		}
 	}
-}
\ No newline at end of file
+}
+flow "interpolation" {
+	input "complete" {}
+
+	resource "first" {
+		request "mock" "simple" {
+			message = "message: {{ input:message }} ({{ input:nested.value }})"
+		}
+	}
+}
+
+flow "index" {
+	input "complete" {}
+
+	resource "first" {
+		request "mock" "complete" {
+			message = "{{ input:repeating[-1].value }}"
+
+			message "nested" {
+				value = "{{ input:repeating[0].value }}"
+			}
+
+			repeating = "{{ input:repeating[*] }}"
+		}
+	}
+}
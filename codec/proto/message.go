@@ -120,14 +120,7 @@ func (manager *Manager) Encode(proto *dynamic.Message, desc *desc.MessageDescrip
 			continue
 		}
 
-		val := prop.Default
-
-		if prop.Reference != nil {
-			ref := store.Load(prop.Reference.Resource, prop.Reference.Path)
-			if ref != nil {
-				val = ref.Value
-			}
-		}
+		val := store.Value(prop)
 
 		if prop.Type == types.TypeMessage {
 			dynamic := dynamic.NewMessage(field.GetMessageType())
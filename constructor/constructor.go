@@ -3,14 +3,21 @@ package constructor
 import (
 	"context"
 
+	"github.com/jexia/maestro/cache"
 	"github.com/jexia/maestro/codec"
 	"github.com/jexia/maestro/flow"
+	"github.com/jexia/maestro/logger"
 	"github.com/jexia/maestro/metadata"
+	"github.com/jexia/maestro/metrics"
 	"github.com/jexia/maestro/schema"
+	"github.com/jexia/maestro/secrets"
 	"github.com/jexia/maestro/specs"
 	"github.com/jexia/maestro/specs/strict"
 	"github.com/jexia/maestro/specs/trace"
 	"github.com/jexia/maestro/transport"
+	"github.com/jexia/maestro/transport/breaker"
+	"github.com/jexia/maestro/transport/bulkhead"
+	"github.com/sirupsen/logrus"
 )
 
 // Specs construct a specs manifest from the given options
@@ -41,7 +48,22 @@ func Specs(ctx context.Context, options Options) (*specs.Manifest, error) {
 		}
 	}
 
-	err := specs.CheckManifestDuplicates(ctx, result)
+	resolver := secrets.NewResolver(ctx, options.Secrets)
+	if options.Secrets != nil {
+		logger.AddHook(ctx, resolver)
+	}
+
+	err := resolver.Manifest(result)
+	if err != nil {
+		return nil, err
+	}
+
+	err = resolver.Schema(options.Schema, options.Functions)
+	if err != nil {
+		return nil, err
+	}
+
+	err = specs.CheckManifestDuplicates(ctx, result)
 	if err != nil {
 		return nil, err
 	}
@@ -59,9 +81,11 @@ func Specs(ctx context.Context, options Options) (*specs.Manifest, error) {
 	return result, nil
 }
 
-// FlowManager constructs the flow managers from the given specs manifest
-func FlowManager(ctx context.Context, manifest *specs.Manifest, options Options) ([]*transport.Endpoint, error) {
+// FlowManager constructs the flow managers from the given specs manifest.
+// All constructed flow managers, including the managers of called and recovered flows, are returned.
+func FlowManager(ctx context.Context, manifest *specs.Manifest, options Options) ([]*transport.Endpoint, Managers, error) {
 	endpoints := make([]*transport.Endpoint, len(manifest.Endpoints))
+	managers := make(Managers, len(manifest.Flows)+len(manifest.Proxy))
 
 	for index, endpoint := range manifest.Endpoints {
 		current := manifest.GetFlow(endpoint.Flow)
@@ -69,54 +93,150 @@ func FlowManager(ctx context.Context, manifest *specs.Manifest, options Options)
 			continue
 		}
 
-		nodes := make([]*flow.Node, len(current.GetNodes()))
-
 		result := &transport.Endpoint{
 			Listener: endpoint.Listener,
 			Options:  endpoint.Options,
 			Request:  current.GetInput(),
 			Response: current.GetOutput(),
+			OnError:  current.GetOnError(),
 		}
 
-		for index, node := range current.GetNodes() {
-			caller, err := Call(ctx, manifest, node, node.Call, options, current)
-			if err != nil {
-				return nil, err
-			}
-
-			rollback, err := Call(ctx, manifest, node, node.Rollback, options, current)
-			if err != nil {
-				return nil, err
-			}
-
-			nodes[index] = flow.NewNode(ctx, node, caller, rollback)
+		manager, err := Manager(ctx, manifest, current, managers, options)
+		if err != nil {
+			return nil, nil, err
 		}
 
 		forward, err := Forward(manifest, current.GetForward(), options)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
-		result.Flow = flow.NewManager(ctx, current.GetName(), nodes)
+		result.Flow = manager
 		result.Forward = forward
 
+		if options.Metrics != nil {
+			result.Flow = metrics.NewFlow(manager, options.Metrics, endpoint.Listener, metrics.Endpoint(result))
+		}
+
 		endpoints[index] = result
 	}
 
-	err := Listeners(endpoints, options)
+	err := Recover(ctx, manifest, managers, options)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+
+	err = Listeners(endpoints, options)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	return endpoints, nil
+	return endpoints, managers, nil
+}
+
+// Recover resumes the rollbacks of all pending executions found inside the configured journal.
+// Executions of flows which are no longer defined are ignored.
+func Recover(ctx context.Context, manifest *specs.Manifest, managers Managers, options Options) error {
+	if options.Journal == nil {
+		return nil
+	}
+
+	pending, err := options.Journal.Pending()
+	if err != nil {
+		return err
+	}
+
+	for _, execution := range pending {
+		current := manifest.GetFlow(execution.Flow)
+		if current == nil {
+			logger.FromCtx(ctx, logger.Core).WithFields(logrus.Fields{
+				"flow":      execution.Flow,
+				"execution": execution.ID,
+			}).Warn("Pending execution of undefined flow")
+
+			continue
+		}
+
+		manager, err := Manager(ctx, manifest, current, managers, options)
+		if err != nil {
+			return err
+		}
+
+		manager.Recover(execution)
+	}
+
+	return nil
+}
+
+// Managers represents a collection of constructed flow managers
+type Managers map[string]*flow.Manager
+
+// Manager constructs a new flow manager for the given flow.
+// Constructed managers are stored inside the given managers collection and reused when called by other flows.
+func Manager(ctx context.Context, manifest *specs.Manifest, current specs.FlowManager, managers Managers, options Options) (*flow.Manager, error) {
+	if managers[current.GetName()] != nil {
+		return managers[current.GetName()], nil
+	}
+
+	nodes := make([]*flow.Node, len(current.GetNodes()))
+
+	for index, node := range current.GetNodes() {
+		caller, err := Call(ctx, manifest, node, node.Call, managers, options, current)
+		if err != nil {
+			return nil, err
+		}
+
+		rollback, err := Call(ctx, manifest, node, node.Rollback, managers, options, current)
+		if err != nil {
+			return nil, err
+		}
+
+		// Flow calls without a defined rollback revert the called flow
+		if sub, is := caller.(*flow.FlowCaller); is && rollback == nil {
+			rollback = sub.Rollback()
+		}
+
+		nodes[index] = flow.NewNode(ctx, node, caller, rollback)
+	}
+
+	manager := flow.NewManager(ctx, current.GetName(), nodes,
+		flow.WithTimeout(current.GetTimeout()),
+		flow.WithJournal(options.Journal),
+		flow.WithDeadLetter(options.DeadLetter),
+		flow.WithTracer(options.Tracer),
+		flow.WithBulkhead(Bulkhead(current)),
+		flow.WithHooks(options.Hooks...),
+	)
+	managers[current.GetName()] = manager
+
+	return manager, nil
+}
+
+// Bulkhead constructs the bulkhead limiting the concurrent executions of the given flow.
+// Nil is returned when the flow does not define any concurrency limits.
+func Bulkhead(current specs.FlowManager) *bulkhead.Bulkhead {
+	limits := current.GetBulkhead()
+	if limits == nil {
+		return nil
+	}
+
+	return bulkhead.New(current.GetName(), &bulkhead.Options{
+		MaxConcurrency: limits.MaxConcurrency,
+		MaxQueue:       limits.MaxQueue,
+		QueueTimeout:   limits.QueueTimeout,
+	})
 }
 
 // Call constructs a flow caller for the given node call.
-func Call(ctx context.Context, manifest *specs.Manifest, node *specs.Node, call *specs.Call, options Options, manager specs.FlowManager) (flow.Call, error) {
+func Call(ctx context.Context, manifest *specs.Manifest, node *specs.Node, call *specs.Call, managers Managers, options Options, manager specs.FlowManager) (flow.Call, error) {
 	if call == nil {
 		return nil, nil
 	}
 
+	if call.GetService() == specs.FlowService {
+		return FlowCall(ctx, manifest, node, call, managers, options)
+	}
+
 	service := options.Schema.GetService(call.Service)
 	if service == nil {
 		return nil, trace.New(trace.WithMessage("the service for %s was not found", call.GetMethod()))
@@ -139,17 +259,49 @@ func Call(ctx context.Context, manifest *specs.Manifest, node *specs.Node, call
 		return nil, err
 	}
 
+	circuit, err := options.Breakers.Get(ctx, service.GetFullyQualifiedName(), service.GetOptions())
+	if err != nil {
+		return nil, err
+	}
+
+	if circuit != nil {
+		transport = breaker.NewCall(transport, circuit)
+	}
+
+	limit, err := options.Bulkheads.Get(service.GetFullyQualifiedName(), service.GetOptions())
+	if err != nil {
+		return nil, err
+	}
+
+	if limit != nil {
+		transport = bulkhead.NewCall(transport, limit)
+	}
+
+	if options.Metrics != nil {
+		transport = metrics.NewCall(transport, options.Metrics, service.GetFullyQualifiedName())
+	}
+
 	request, err := Request(node, codec, call.GetRequest())
 	if err != nil {
 		return nil, err
 	}
 
-	response, err := Request(node, codec, call.GetResponse())
+	response, err := Response(node, codec, call)
+	if err != nil {
+		return nil, err
+	}
+
+	var store cache.Cache
+	if node.Cache != nil && call == node.Call && options.Cache != nil {
+		store = options.Cache.New(node.Cache.MaxEntries)
+	}
+
+	group, err := options.Groups.Get(service.GetFullyQualifiedName(), call.Method, service.GetOptions(), MethodOptions(service, call.Method))
 	if err != nil {
 		return nil, err
 	}
 
-	caller := flow.NewCall(ctx, node, transport, call.Method, request, response)
+	caller := flow.NewCall(ctx, node, transport, call.Method, request, response, store, group)
 	err = strict.DefineCaller(ctx, node, manifest, transport, manager)
 	if err != nil {
 		return nil, err
@@ -158,6 +310,32 @@ func Call(ctx context.Context, manifest *specs.Manifest, node *specs.Node, call
 	return caller, nil
 }
 
+// MethodOptions returns the options of the given service method.
+// Nil is returned when the method is not defined inside the given service.
+func MethodOptions(service schema.Service, name string) schema.Options {
+	method := service.GetMethod(name)
+	if method == nil {
+		return nil
+	}
+
+	return method.GetOptions()
+}
+
+// FlowCall constructs a flow caller which calls the flow defined inside the given call in-process
+func FlowCall(ctx context.Context, manifest *specs.Manifest, node *specs.Node, call *specs.Call, managers Managers, options Options) (flow.Call, error) {
+	target := manifest.Flows.Get(call.GetMethod())
+	if target == nil {
+		return nil, trace.New(trace.WithMessage("the flow %s was not found", call.GetMethod()))
+	}
+
+	manager, err := Manager(ctx, manifest, target, managers, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return flow.NewFlowCall(ctx, node, manager, call.GetRequest(), target.GetOutput()), nil
+}
+
 // Request constructs a new request from the given parameter map and codec
 func Request(node *specs.Node, codec codec.Constructor, params *specs.ParameterMap) (*flow.Request, error) {
 	message, err := codec.New(node.GetName(), params)
@@ -169,6 +347,28 @@ func Request(node *specs.Node, codec codec.Constructor, params *specs.ParameterM
 	return flow.NewRequest(message, metadata), nil
 }
 
+// Response constructs a new response for the given call.
+// Error bodies are decoded into the call error parameter map when a error schema has been defined.
+func Response(node *specs.Node, codec codec.Constructor, call *specs.Call) (*flow.Request, error) {
+	message, err := codec.New(node.GetName(), call.GetResponse())
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := metadata.NewManager(node.GetName(), call.GetResponse())
+
+	if call.GetError() == nil {
+		return flow.NewResponse(message, metadata, nil), nil
+	}
+
+	errs, err := codec.New(specs.JoinPath(node.GetName(), specs.ResourceError), call.GetError())
+	if err != nil {
+		return nil, err
+	}
+
+	return flow.NewResponse(message, metadata, errs), nil
+}
+
 // Forward constructs a flow caller for the given call.
 func Forward(manifest *specs.Manifest, call *specs.Call, options Options) (schema.Service, error) {
 	if call == nil {
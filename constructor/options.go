@@ -3,11 +3,22 @@ package constructor
 import (
 	"context"
 
+	"github.com/jexia/maestro/cache"
+	"github.com/jexia/maestro/cache/lru"
 	"github.com/jexia/maestro/codec"
+	"github.com/jexia/maestro/deadletter"
+	"github.com/jexia/maestro/flow"
+	"github.com/jexia/maestro/journal"
 	"github.com/jexia/maestro/logger"
+	"github.com/jexia/maestro/metrics"
 	"github.com/jexia/maestro/schema"
+	"github.com/jexia/maestro/secrets"
 	"github.com/jexia/maestro/specs"
+	"github.com/jexia/maestro/tracing"
 	"github.com/jexia/maestro/transport"
+	"github.com/jexia/maestro/transport/breaker"
+	"github.com/jexia/maestro/transport/bulkhead"
+	"github.com/jexia/maestro/transport/coalesce"
 )
 
 // Option represents a constructor func which sets a given option
@@ -23,6 +34,16 @@ type Options struct {
 	Schemas     []schema.Resolver
 	Schema      *schema.Store
 	Functions   specs.CustomDefinedFunctions
+	Journal     journal.Journal
+	DeadLetter  deadletter.DeadLetter
+	Breakers    breaker.Breakers
+	Tracer      *tracing.Tracer
+	Cache       cache.Constructor
+	Groups      coalesce.Groups
+	Bulkheads   bulkhead.Bulkheads
+	Hooks       []flow.Hooks
+	Metrics     *metrics.Metrics
+	Secrets     secrets.SecretProvider
 }
 
 // NewOptions constructs a options object from the given option constructors
@@ -32,6 +53,10 @@ func NewOptions(ctx context.Context, options ...Option) Options {
 		Definitions: make([]specs.Resolver, 0),
 		Codec:       make(map[string]codec.Constructor),
 		Schema:      schema.NewStore(ctx),
+		Breakers:    make(breaker.Breakers),
+		Cache:       lru.NewConstructor(),
+		Groups:      make(coalesce.Groups),
+		Bulkheads:   make(bulkhead.Bulkheads),
 	}
 
 	for _, option := range options {
@@ -78,10 +103,72 @@ func WithSchema(resolver schema.Resolver) Option {
 	}
 }
 
-// WithFunctions defines the custom defined functions to be used
+// WithFunctions appends the given custom defined functions to the functions to be used.
+// Previously defined functions with the same name are overridden.
 func WithFunctions(functions specs.CustomDefinedFunctions) Option {
 	return func(options *Options) {
-		options.Functions = functions
+		if options.Functions == nil {
+			options.Functions = make(specs.CustomDefinedFunctions, len(functions))
+		}
+
+		for name, fn := range functions {
+			options.Functions[name] = fn
+		}
+	}
+}
+
+// WithJournal sets the journal used to record flow executions.
+// Pending rollbacks found inside the journal are resumed once the flow managers are constructed.
+func WithJournal(journal journal.Journal) Option {
+	return func(options *Options) {
+		options.Journal = journal
+	}
+}
+
+// WithDeadLetter sets the dead letter sink receiving the rollbacks which could not be compensated
+func WithDeadLetter(sink deadletter.DeadLetter) Option {
+	return func(options *Options) {
+		options.DeadLetter = sink
+	}
+}
+
+// WithTracing sets the exporter receiving the spans of traced flow executions
+func WithTracing(exporter tracing.Exporter) Option {
+	return func(options *Options) {
+		exporter.Context(options.Ctx)
+		options.Tracer = tracing.NewTracer(exporter)
+	}
+}
+
+// WithCache sets the cache constructor used to construct the caches of resources defining a cache policy.
+// By default are responses cached inside a in-memory LRU cache.
+func WithCache(constructor cache.Constructor) Option {
+	return func(options *Options) {
+		options.Cache = constructor
+	}
+}
+
+// WithFlowHooks appends the given hooks called around the flow, node and rollback executions of all flows.
+// Hooks are called in the order they have been given.
+func WithFlowHooks(hooks ...flow.Hooks) Option {
+	return func(options *Options) {
+		options.Hooks = append(options.Hooks, hooks...)
+	}
+}
+
+// WithMetrics sets the collector of the flow, node, rollback, transport call and listener request metrics
+func WithMetrics(collector *metrics.Metrics) Option {
+	return func(options *Options) {
+		options.Metrics = collector
+		options.Hooks = append(options.Hooks, collector)
+	}
+}
+
+// WithSecrets sets the secret provider used to resolve the secret references on startup.
+// Resolved secret values are redacted from all log entries.
+func WithSecrets(provider secrets.SecretProvider) Option {
+	return func(options *Options) {
+		options.Secrets = provider
 	}
 }
 
@@ -0,0 +1,79 @@
+package deadletter
+
+import (
+	"context"
+	"time"
+
+	"github.com/jexia/maestro/refs"
+)
+
+// Letter represents a failed rollback which could not be compensated.
+// The reference values could be used to replay the rollback manually.
+type Letter struct {
+	Flow      string           `json:"flow"`
+	Execution string           `json:"execution,omitempty"`
+	Node      string           `json:"node"`
+	Error     string           `json:"error"`
+	Time      time.Time        `json:"time"`
+	Values    []*refs.Snapshot `json:"values"`
+}
+
+// DeadLetter represents a sink receiving failed rollbacks
+type DeadLetter interface {
+	// Send durably stores the given letter
+	Send(letter *Letter) error
+	// Close closes the dead letter sink
+	Close() error
+}
+
+// NewSender constructs a new sender for the given flow execution
+func NewSender(sink DeadLetter, flow string, execution string) *Sender {
+	return &Sender{
+		sink:      sink,
+		Flow:      flow,
+		Execution: execution,
+	}
+}
+
+// Sender sends the failed rollbacks of a single flow execution to a dead letter sink.
+// All methods are no-ops when called on a nil sender.
+type Sender struct {
+	sink      DeadLetter
+	Flow      string
+	Execution string
+}
+
+// Send sends a new letter for the given node, error and reference stores to the dead letter sink
+func (sender *Sender) Send(node string, err error, stores ...*refs.Store) error {
+	if sender == nil {
+		return nil
+	}
+
+	values := make([]*refs.Snapshot, 0)
+	for _, store := range stores {
+		values = append(values, store.Snapshot()...)
+	}
+
+	return sender.sink.Send(&Letter{
+		Flow:      sender.Flow,
+		Execution: sender.Execution,
+		Node:      node,
+		Error:     err.Error(),
+		Time:      time.Now(),
+		Values:    values,
+	})
+}
+
+type senderKey struct{}
+
+// WithSender returns a copy of the given context containing the given sender
+func WithSender(ctx context.Context, sender *Sender) context.Context {
+	return context.WithValue(ctx, senderKey{}, sender)
+}
+
+// FromCtx returns the sender stored inside the given context.
+// Nil is returned when no sender has been stored.
+func FromCtx(ctx context.Context) *Sender {
+	sender, _ := ctx.Value(senderKey{}).(*Sender)
+	return sender
+}
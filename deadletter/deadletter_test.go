@@ -0,0 +1,78 @@
+package deadletter
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jexia/maestro/refs"
+)
+
+type memory struct {
+	letters []*Letter
+}
+
+func (sink *memory) Send(letter *Letter) error {
+	sink.letters = append(sink.letters, letter)
+	return nil
+}
+
+func (sink *memory) Close() error {
+	return nil
+}
+
+func TestNilSender(t *testing.T) {
+	var sender *Sender
+
+	err := sender.Send("node", errors.New("unexpected err"))
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSender(t *testing.T) {
+	sink := &memory{}
+	sender := NewSender(sink, "checkout", "execution")
+
+	first := refs.NewStore(1)
+	first.StoreValue("input", "id", "first")
+
+	second := refs.NewStore(1)
+	second.StoreValue("charge", "id", "second")
+
+	err := sender.Send("charge", errors.New("unexpected err"), first, second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(sink.letters) != 1 {
+		t.Fatalf("unexpected letters %d, expected 1", len(sink.letters))
+	}
+
+	letter := sink.letters[0]
+	if letter.Flow != "checkout" || letter.Execution != "execution" || letter.Node != "charge" {
+		t.Fatalf("unexpected letter %+v", letter)
+	}
+
+	if letter.Error != "unexpected err" {
+		t.Fatalf("unexpected error %s, expected %s", letter.Error, "unexpected err")
+	}
+
+	if len(letter.Values) != 2 {
+		t.Fatalf("unexpected values %d, expected 2", len(letter.Values))
+	}
+}
+
+func TestSenderFromCtx(t *testing.T) {
+	ctx := context.Background()
+	if FromCtx(ctx) != nil {
+		t.Fatal("unexpected sender inside empty context")
+	}
+
+	sender := NewSender(&memory{}, "checkout", "")
+	ctx = WithSender(ctx, sender)
+
+	if FromCtx(ctx) != sender {
+		t.Fatal("unexpected sender returned from context")
+	}
+}
@@ -0,0 +1,57 @@
+package file
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+
+	"github.com/jexia/maestro/deadletter"
+)
+
+// Open opens or creates the dead letter file at the given path.
+// Letters are appended to the file as JSON lines.
+func Open(path string) (*Writer, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Writer{
+		file: file,
+	}
+
+	return result, nil
+}
+
+// Writer represents a dead letter sink writing letters to a local file
+type Writer struct {
+	file  *os.File
+	mutex sync.Mutex
+}
+
+// Send encodes and appends the given letter to the dead letter file.
+// The file is synced before returning to ensure the letter is durably stored.
+func (writer *Writer) Send(letter *deadletter.Letter) error {
+	bb, err := json.Marshal(letter)
+	if err != nil {
+		return err
+	}
+
+	writer.mutex.Lock()
+	defer writer.mutex.Unlock()
+
+	_, err = writer.file.Write(append(bb, '\n'))
+	if err != nil {
+		return err
+	}
+
+	return writer.file.Sync()
+}
+
+// Close closes the dead letter file
+func (writer *Writer) Close() error {
+	writer.mutex.Lock()
+	defer writer.mutex.Unlock()
+
+	return writer.file.Close()
+}
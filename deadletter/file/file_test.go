@@ -0,0 +1,76 @@
+package file
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jexia/maestro/deadletter"
+	"github.com/jexia/maestro/refs"
+)
+
+func TestSend(t *testing.T) {
+	dir, err := ioutil.TempDir("", "deadletter")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "rollbacks.jsonl")
+	writer, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	nodes := []string{"first", "second"}
+	for _, node := range nodes {
+		err := writer.Send(&deadletter.Letter{
+			Flow:   "checkout",
+			Node:   node,
+			Error:  "unexpected err",
+			Values: []*refs.Snapshot{{Resource: "input", Path: "id", Value: "1"}},
+		})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	writer.Close()
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	index := 0
+
+	for scanner.Scan() {
+		letter := deadletter.Letter{}
+		err := json.Unmarshal(scanner.Bytes(), &letter)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if letter.Node != nodes[index] {
+			t.Fatalf("unexpected node %s, expected %s", letter.Node, nodes[index])
+		}
+
+		if len(letter.Values) != 1 || letter.Values[0].Value != "1" {
+			t.Fatalf("unexpected values %+v", letter.Values)
+		}
+
+		index++
+	}
+
+	if index != len(nodes) {
+		t.Fatalf("unexpected letters %d, expected %d", index, len(nodes))
+	}
+}
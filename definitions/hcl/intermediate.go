@@ -16,9 +16,30 @@ type Manifest struct {
 type Flow struct {
 	Name      string             `hcl:"name,label"`
 	DependsOn []string           `hcl:"depends_on,optional"`
+	Timeout   string             `hcl:"timeout,optional"`
+	Bulkhead  *Bulkhead          `hcl:"bulkhead,block"`
 	Input     *InputParameterMap `hcl:"input,block"`
 	Resources []Node             `hcl:"resource,block"`
 	Output    *ParameterMap      `hcl:"output,block"`
+	OnError   *OnError           `hcl:"on_error,block"`
+}
+
+// OnError intermediate specification
+type OnError struct {
+	Schema     string                 `hcl:"schema,label"`
+	Status     map[string]int         `hcl:"status_codes,optional"`
+	Options    *Options               `hcl:"options,block"`
+	Header     *Header                `hcl:"header,block"`
+	Nested     []NestedParameterMap   `hcl:"message,block"`
+	Repeated   []RepeatedParameterMap `hcl:"repeated,block"`
+	Properties hcl.Body               `hcl:",remain"`
+}
+
+// Bulkhead intermediate specification
+type Bulkhead struct {
+	MaxConcurrency int    `hcl:"max_concurrency"`
+	MaxQueue       int    `hcl:"max_queue,optional"`
+	QueueTimeout   string `hcl:"queue_timeout,optional"`
 }
 
 // ParameterMap is the initial map of parameter names (keys) and their (templated) values (values)
@@ -88,8 +109,32 @@ type Node struct {
 	Name      string   `hcl:"name,label"`
 	DependsOn []string `hcl:"depends_on,optional"`
 	Type      string   `hcl:"type,optional"`
+	OnError   string   `hcl:"on_error,optional"`
+	Timeout   string   `hcl:"timeout,optional"`
+	Condition string   `hcl:"if,optional"`
+	Foreach   string   `hcl:"foreach,optional"`
+	Parallel  int      `hcl:"parallel,optional"`
 	Request   *Call    `hcl:"request,block"`
 	Rollback  *Call    `hcl:"rollback,block"`
+	Retry     *Retry   `hcl:"retry,block"`
+	Cache     *Cache   `hcl:"cache,block"`
+}
+
+// Cache intermediate specification
+type Cache struct {
+	TTL        string   `hcl:"ttl"`
+	Key        []string `hcl:"key,optional"`
+	MaxEntries int      `hcl:"max_entries,optional"`
+}
+
+// Retry intermediate specification
+type Retry struct {
+	Attempts int      `hcl:"max_attempts,optional"`
+	Backoff  string   `hcl:"backoff,optional"`
+	Delay    string   `hcl:"delay,optional"`
+	MaxDelay string   `hcl:"max_delay,optional"`
+	Jitter   bool     `hcl:"jitter,optional"`
+	Errors   []string `hcl:"on,optional"`
 }
 
 // Call intermediate specification
@@ -98,6 +143,7 @@ type Call struct {
 	Method     string                 `hcl:"method,label"`
 	Options    *Options               `hcl:"options,block"`
 	Header     *Header                `hcl:"header,block"`
+	Retry      *Retry                 `hcl:"retry,block"`
 	Nested     []NestedParameterMap   `hcl:"message,block"`
 	Repeated   []RepeatedParameterMap `hcl:"repeated,block"`
 	Properties hcl.Body               `hcl:",remain"`
@@ -126,6 +172,8 @@ type Method struct {
 type Proxy struct {
 	Name      string       `hcl:"name,label"`
 	DependsOn []string     `hcl:"depends_on,optional"`
+	Timeout   string       `hcl:"timeout,optional"`
+	Bulkhead  *Bulkhead    `hcl:"bulkhead,block"`
 	Resources []Node       `hcl:"resource,block"`
 	Forward   ProxyForward `hcl:"forward,block"`
 }
@@ -2,10 +2,12 @@ package hcl
 
 import (
 	"context"
+	"time"
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/jexia/maestro/logger"
 	"github.com/jexia/maestro/specs"
+	"github.com/jexia/maestro/specs/trace"
 	"github.com/jexia/maestro/specs/types"
 	"github.com/zclconf/go-cty/cty"
 )
@@ -72,12 +74,30 @@ func ParseIntermediateFlow(ctx context.Context, flow Flow, functions specs.Custo
 		return nil, err
 	}
 
+	timeout, err := ParseDuration(flow.Timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	bulkhead, err := ParseIntermediateBulkhead(ctx, flow.Name, flow.Bulkhead)
+	if err != nil {
+		return nil, err
+	}
+
+	onError, err := ParseIntermediateOnError(ctx, flow.Name, flow.OnError, functions)
+	if err != nil {
+		return nil, err
+	}
+
 	result := specs.Flow{
 		Name:      flow.Name,
 		DependsOn: make(map[string]*specs.Flow, len(flow.DependsOn)),
+		Timeout:   timeout,
+		Bulkhead:  bulkhead,
 		Input:     input,
 		Nodes:     make([]*specs.Node, len(flow.Resources)),
 		Output:    output,
+		OnError:   onError,
 	}
 
 	for _, dependency := range flow.DependsOn {
@@ -165,9 +185,21 @@ func ParseIntermediateProxy(ctx context.Context, proxy Proxy, functions specs.Cu
 		return nil, err
 	}
 
+	timeout, err := ParseDuration(proxy.Timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	bulkhead, err := ParseIntermediateBulkhead(ctx, proxy.Name, proxy.Bulkhead)
+	if err != nil {
+		return nil, err
+	}
+
 	result := specs.Proxy{
 		Name:      proxy.Name,
 		DependsOn: make(map[string]*specs.Flow, len(proxy.DependsOn)),
+		Timeout:   timeout,
+		Bulkhead:  bulkhead,
 		Nodes:     make([]*specs.Node, len(proxy.Resources)),
 		Forward:   forward,
 	}
@@ -407,6 +439,18 @@ func ParseIntermediateSpecOptions(options hcl.Body) specs.Options {
 
 // ParseIntermediateNode parses the given intermediate call to a spec call
 func ParseIntermediateNode(ctx context.Context, node Node, functions specs.CustomDefinedFunctions) (*specs.Node, error) {
+	if node.Type != "" && node.Type != specs.NodeTypeAsync {
+		return nil, trace.New(trace.WithMessage("unknown type '%s' in resource '%s', expected '%s'", node.Type, node.Name, specs.NodeTypeAsync))
+	}
+
+	if node.Type == specs.NodeTypeAsync && node.Rollback != nil {
+		return nil, trace.New(trace.WithMessage("unexpected rollback inside async resource '%s', async resources could not be reverted", node.Name))
+	}
+
+	if node.OnError != "" && node.OnError != specs.OnErrorContinue {
+		return nil, trace.New(trace.WithMessage("unknown on_error '%s' in resource '%s', expected '%s'", node.OnError, node.Name, specs.OnErrorContinue))
+	}
+
 	call, err := ParseIntermediateCall(ctx, node.Request, functions)
 	if err != nil {
 		return nil, err
@@ -417,12 +461,56 @@ func ParseIntermediateNode(ctx context.Context, node Node, functions specs.Custo
 		return nil, err
 	}
 
+	retry, err := ParseIntermediateRetry(ctx, node.Name, node.Retry)
+	if err != nil {
+		return nil, err
+	}
+
+	if node.Request != nil && node.Request.Retry != nil {
+		return nil, trace.New(trace.WithMessage("unexpected retry block inside request in resource '%s', define the retry policy inside the resource", node.Name))
+	}
+
+	var rollbackRetry *specs.Retry
+	if node.Rollback != nil {
+		rollbackRetry, err = ParseIntermediateRetry(ctx, node.Name, node.Rollback.Retry)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	cache, err := ParseIntermediateCache(ctx, node.Name, functions, node.Cache)
+	if err != nil {
+		return nil, err
+	}
+
+	timeout, err := ParseDuration(node.Timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	condition, err := ParseIntermediateCondition(ctx, node.Name, functions, node.Condition)
+	if err != nil {
+		return nil, err
+	}
+
+	foreach, err := ParseIntermediateForeach(ctx, node, functions)
+	if err != nil {
+		return nil, err
+	}
+
 	result := specs.Node{
-		DependsOn: make(map[string]*specs.Node, len(node.DependsOn)),
-		Name:      node.Name,
-		Type:      node.Type,
-		Call:      call,
-		Rollback:  rollback,
+		DependsOn:     make(map[string]*specs.Node, len(node.DependsOn)),
+		Name:          node.Name,
+		Type:          node.Type,
+		OnError:       node.OnError,
+		Timeout:       timeout,
+		Condition:     condition,
+		Foreach:       foreach,
+		Call:          call,
+		Rollback:      rollback,
+		Retry:         retry,
+		RollbackRetry: rollbackRetry,
+		Cache:         cache,
 	}
 
 	for _, dependency := range node.DependsOn {
@@ -432,6 +520,244 @@ func ParseIntermediateNode(ctx context.Context, node Node, functions specs.Custo
 	return &result, nil
 }
 
+// ParseIntermediateCondition parses the given intermediate condition to a spec condition
+func ParseIntermediateCondition(ctx context.Context, node string, functions specs.CustomDefinedFunctions, condition string) (*specs.Condition, error) {
+	if condition == "" {
+		return nil, nil
+	}
+
+	logger.FromCtx(ctx, logger.Core).WithField("node", node).Debug("Parsing intermediate condition to specs")
+
+	return specs.ParseCondition(ctx, "if", functions, condition)
+}
+
+// ParseIntermediateForeach parses the given intermediate node foreach to a spec foreach
+func ParseIntermediateForeach(ctx context.Context, node Node, functions specs.CustomDefinedFunctions) (*specs.Foreach, error) {
+	if node.Foreach == "" {
+		if node.Parallel != 0 {
+			return nil, trace.New(trace.WithMessage("parallel defined without foreach in resource '%s'", node.Name))
+		}
+
+		return nil, nil
+	}
+
+	logger.FromCtx(ctx, logger.Core).WithField("node", node.Name).Debug("Parsing intermediate foreach to specs")
+
+	if !specs.IsTemplate(node.Foreach) {
+		return nil, trace.New(trace.WithMessage("invalid foreach '%s' in resource '%s', expected a template", node.Foreach, node.Name))
+	}
+
+	property, err := specs.ParseTemplate(ctx, "foreach", functions, node.Foreach)
+	if err != nil {
+		return nil, err
+	}
+
+	if property.Reference == nil {
+		return nil, trace.New(trace.WithMessage("invalid foreach '%s' in resource '%s', expected a reference", node.Foreach, node.Name))
+	}
+
+	result := specs.Foreach{
+		Property: property,
+		Parallel: 10,
+	}
+
+	if node.Parallel != 0 {
+		if node.Parallel < 1 {
+			return nil, trace.New(trace.WithMessage("invalid parallel '%d' in resource '%s', expected at least one concurrent call", node.Parallel, node.Name))
+		}
+
+		result.Parallel = node.Parallel
+	}
+
+	return &result, nil
+}
+
+// ParseIntermediateRetry parses the given intermediate retry policy to a spec retry policy
+func ParseIntermediateRetry(ctx context.Context, node string, retry *Retry) (*specs.Retry, error) {
+	if retry == nil {
+		return nil, nil
+	}
+
+	logger.FromCtx(ctx, logger.Core).WithField("node", node).Debug("Parsing intermediate retry policy to specs")
+
+	result := specs.Retry{
+		Attempts: 3,
+		Backoff:  specs.BackoffExponential,
+		Delay:    100 * time.Millisecond,
+		Jitter:   retry.Jitter,
+		Errors:   []string{specs.RetryAll},
+	}
+
+	if retry.Attempts != 0 {
+		if retry.Attempts < 1 {
+			return nil, trace.New(trace.WithMessage("invalid max attempts '%d' in resource '%s', expected at least one attempt", retry.Attempts, node))
+		}
+
+		result.Attempts = retry.Attempts
+	}
+
+	if retry.Backoff != "" {
+		switch retry.Backoff {
+		case specs.BackoffConstant, specs.BackoffLinear, specs.BackoffExponential:
+		default:
+			return nil, trace.New(trace.WithMessage("unknown backoff strategy '%s' in resource '%s'", retry.Backoff, node))
+		}
+
+		result.Backoff = retry.Backoff
+	}
+
+	if retry.Delay != "" {
+		duration, err := ParseDuration(retry.Delay)
+		if err != nil {
+			return nil, err
+		}
+
+		result.Delay = duration
+	}
+
+	maximum, err := ParseDuration(retry.MaxDelay)
+	if err != nil {
+		return nil, err
+	}
+
+	result.MaxDelay = maximum
+
+	if len(retry.Errors) > 0 {
+		for _, class := range retry.Errors {
+			switch class {
+			case specs.RetryAll, specs.RetryTimeout, specs.RetryConnection, specs.RetryUpstream:
+			default:
+				return nil, trace.New(trace.WithMessage("unknown retryable error class '%s' in resource '%s'", class, node))
+			}
+		}
+
+		result.Errors = retry.Errors
+	}
+
+	return &result, nil
+}
+
+// ParseIntermediateBulkhead parses the given intermediate bulkhead to spec concurrency limits
+func ParseIntermediateBulkhead(ctx context.Context, flow string, bulkhead *Bulkhead) (*specs.Bulkhead, error) {
+	if bulkhead == nil {
+		return nil, nil
+	}
+
+	logger.FromCtx(ctx, logger.Core).WithField("flow", flow).Debug("Parsing intermediate bulkhead to specs")
+
+	if bulkhead.MaxConcurrency < 1 {
+		return nil, trace.New(trace.WithMessage("invalid max concurrency '%d' in flow '%s', expected at least one concurrent execution", bulkhead.MaxConcurrency, flow))
+	}
+
+	if bulkhead.MaxQueue < 0 {
+		return nil, trace.New(trace.WithMessage("invalid max queue '%d' in flow '%s', expected a positive number", bulkhead.MaxQueue, flow))
+	}
+
+	timeout, err := ParseDuration(bulkhead.QueueTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	if timeout > 0 && bulkhead.MaxQueue == 0 {
+		return nil, trace.New(trace.WithMessage("invalid queue timeout '%s' in flow '%s', a max queue is required to queue executions", bulkhead.QueueTimeout, flow))
+	}
+
+	result := specs.Bulkhead{
+		MaxConcurrency: bulkhead.MaxConcurrency,
+		MaxQueue:       bulkhead.MaxQueue,
+		QueueTimeout:   timeout,
+	}
+
+	return &result, nil
+}
+
+// ParseIntermediateOnError parses the given intermediate error response to a spec error response
+func ParseIntermediateOnError(ctx context.Context, flow string, onError *OnError, functions specs.CustomDefinedFunctions) (*specs.OnError, error) {
+	if onError == nil {
+		return nil, nil
+	}
+
+	logger.FromCtx(ctx, logger.Core).WithField("flow", flow).Debug("Parsing intermediate error response to specs")
+
+	for code, status := range onError.Status {
+		if status < 100 || status > 599 {
+			return nil, trace.New(trace.WithMessage("invalid status code '%d' for '%s' in flow '%s', expected a status code between 100 and 599", status, code, flow))
+		}
+	}
+
+	params := &ParameterMap{
+		Schema:     onError.Schema,
+		Options:    onError.Options,
+		Header:     onError.Header,
+		Nested:     onError.Nested,
+		Repeated:   onError.Repeated,
+		Properties: onError.Properties,
+	}
+
+	response, err := ParseIntermediateParameterMap(ctx, params, functions)
+	if err != nil {
+		return nil, err
+	}
+
+	result := specs.OnError{
+		Response: response,
+		Status:   onError.Status,
+	}
+
+	return &result, nil
+}
+
+// ParseIntermediateCache parses the given intermediate cache policy to a spec cache policy
+func ParseIntermediateCache(ctx context.Context, node string, functions specs.CustomDefinedFunctions, cache *Cache) (*specs.Cache, error) {
+	if cache == nil {
+		return nil, nil
+	}
+
+	logger.FromCtx(ctx, logger.Core).WithField("node", node).Debug("Parsing intermediate cache policy to specs")
+
+	ttl, err := ParseDuration(cache.TTL)
+	if err != nil {
+		return nil, err
+	}
+
+	if ttl <= 0 {
+		return nil, trace.New(trace.WithMessage("invalid cache ttl '%s' in resource '%s', expected a positive duration", cache.TTL, node))
+	}
+
+	result := specs.Cache{
+		TTL:        ttl,
+		Key:        make([]*specs.Property, len(cache.Key)),
+		MaxEntries: 1000,
+	}
+
+	if cache.MaxEntries != 0 {
+		if cache.MaxEntries < 1 {
+			return nil, trace.New(trace.WithMessage("invalid max entries '%d' in resource '%s', expected at least one entry", cache.MaxEntries, node))
+		}
+
+		result.MaxEntries = cache.MaxEntries
+	}
+
+	for index, key := range cache.Key {
+		if !specs.IsTemplate(key) {
+			return nil, trace.New(trace.WithMessage("invalid cache key '%s' in resource '%s', expected a template", key, node))
+		}
+
+		property, err := specs.ParseTemplate(ctx, "cache", functions, key)
+		if err != nil {
+			return nil, err
+		}
+
+		if property.Reference == nil {
+			return nil, trace.New(trace.WithMessage("invalid cache key '%s' in resource '%s', expected a reference", key, node))
+		}
+
+		result.Key[index] = property
+	}
+
+	return &result, nil
+}
+
 // ParseIntermediateCall parses the given intermediate call to a spec call
 func ParseIntermediateCall(ctx context.Context, call *Call, functions specs.CustomDefinedFunctions) (*specs.Call, error) {
 	if call == nil {
@@ -524,7 +850,7 @@ func ParseIntermediateProperty(ctx context.Context, path string, functions specs
 		Expr: property.Expr,
 	}
 
-	if value.Type() != cty.String || !specs.IsTemplate(value.AsString()) {
+	if value.Type() != cty.String || !specs.ContainsTemplate(value.AsString()) {
 		specs.SetDefaultValue(ctx, result, value)
 		return result, nil
 	}
@@ -6,8 +6,11 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/hashicorp/hcl/v2"
 	"github.com/jexia/maestro/logger"
+	"github.com/jexia/maestro/specs"
 	"github.com/jexia/maestro/utils"
 )
 
@@ -56,3 +59,272 @@ func TestParseSpecs(t *testing.T) {
 		})
 	}
 }
+
+func TestParseIntermediateRetry(t *testing.T) {
+	ctx := context.Background()
+	ctx = logger.WithValue(ctx)
+
+	tests := map[string]*Retry{
+		"attempts": {Attempts: -1},
+		"backoff":  {Backoff: "unknown"},
+		"delay":    {Delay: "unknown"},
+		"negative": {Delay: "-1s"},
+		"maximum":  {MaxDelay: "unknown"},
+		"errors":   {Errors: []string{"unknown"}},
+	}
+
+	for name, input := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseIntermediateRetry(ctx, "node", input)
+			if err == nil {
+				t.Fatal("expected a error to be returned")
+			}
+		})
+	}
+
+	result, err := ParseIntermediateRetry(ctx, "node", &Retry{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Attempts != 3 {
+		t.Fatalf("unexpected default attempts %d, expected %d", result.Attempts, 3)
+	}
+
+	if result.Backoff != specs.BackoffExponential {
+		t.Fatalf("unexpected default backoff %s, expected %s", result.Backoff, specs.BackoffExponential)
+	}
+}
+
+func TestParseIntermediateForeach(t *testing.T) {
+	ctx := context.Background()
+	ctx = logger.WithValue(ctx)
+
+	tests := map[string]Node{
+		"template":  {Name: "node", Foreach: "input:items"},
+		"parallel":  {Name: "node", Foreach: "{{ input:items }}", Parallel: -1},
+		"undefined": {Name: "node", Parallel: 5},
+	}
+
+	for name, input := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseIntermediateForeach(ctx, input, nil)
+			if err == nil {
+				t.Fatal("expected a error to be returned")
+			}
+		})
+	}
+
+	result, err := ParseIntermediateForeach(ctx, Node{Name: "node", Foreach: "{{ input:items }}"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Parallel != 10 {
+		t.Fatalf("unexpected default parallel %d, expected %d", result.Parallel, 10)
+	}
+
+	if result.Property.Reference.Resource != "input" || result.Property.Reference.Path != "items" {
+		t.Fatalf("unexpected foreach reference %s", result.Property.Reference)
+	}
+}
+
+func TestParseIntermediateNodeType(t *testing.T) {
+	ctx := context.Background()
+	ctx = logger.WithValue(ctx)
+
+	tests := map[string]Node{
+		"unknown":  {Name: "node", Type: "sync"},
+		"rollback": {Name: "node", Type: specs.NodeTypeAsync, Rollback: &Call{Service: "getter", Method: "Remove"}},
+		"on_error": {Name: "node", OnError: "ignore"},
+		"timeout":  {Name: "node", Timeout: "-1s"},
+	}
+
+	for name, input := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseIntermediateNode(ctx, input, nil)
+			if err == nil {
+				t.Fatal("expected a error to be returned")
+			}
+		})
+	}
+
+	result, err := ParseIntermediateNode(ctx, Node{Name: "node", Type: specs.NodeTypeAsync}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !result.IsAsync() {
+		t.Fatal("expected the node to be async")
+	}
+
+	result, err = ParseIntermediateNode(ctx, Node{Name: "node", OnError: specs.OnErrorContinue}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !result.IsOptional() {
+		t.Fatal("expected the node to be optional")
+	}
+}
+
+func TestParseRollbackRetry(t *testing.T) {
+	ctx := context.Background()
+	ctx = logger.WithValue(ctx)
+
+	definition := `
+	flow "echo" {
+		resource "get" {
+			request "getter" "Get" {}
+
+			rollback "getter" "Remove" {
+				retry {
+					max_attempts = 10
+				}
+			}
+		}
+	}`
+
+	manifests, err := UnmarshalHCL(ctx, "rollback.hcl", strings.NewReader(definition))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	manifest, err := ParseSpecs(ctx, manifests, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	node := manifest.Flows[0].Nodes[0]
+	if node.Retry != nil {
+		t.Fatal("unexpected call retry policy")
+	}
+
+	if node.RollbackRetry == nil || node.RollbackRetry.Attempts != 10 {
+		t.Fatalf("unexpected rollback retry policy %+v", node.RollbackRetry)
+	}
+}
+
+func TestParseRequestRetry(t *testing.T) {
+	ctx := context.Background()
+	ctx = logger.WithValue(ctx)
+
+	definition := `
+	flow "echo" {
+		resource "get" {
+			request "getter" "Get" {
+				retry {}
+			}
+		}
+	}`
+
+	manifests, err := UnmarshalHCL(ctx, "request.hcl", strings.NewReader(definition))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = ParseSpecs(ctx, manifests, nil)
+	if err == nil {
+		t.Fatal("expected a error to be returned")
+	}
+}
+
+func TestParseIntermediateCache(t *testing.T) {
+	ctx := context.Background()
+	ctx = logger.WithValue(ctx)
+
+	tests := map[string]*Cache{
+		"ttl":         {TTL: "0s"},
+		"duration":    {TTL: "minute"},
+		"max entries": {TTL: "1m", MaxEntries: -1},
+		"template":    {TTL: "1m", Key: []string{"id"}},
+	}
+
+	for name, input := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseIntermediateCache(ctx, "node", nil, input)
+			if err == nil {
+				t.Fatal("unexpected pass")
+			}
+		})
+	}
+
+	result, err := ParseIntermediateCache(ctx, "node", nil, &Cache{TTL: "1m", Key: []string{"{{ input:id }}"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.TTL != time.Minute {
+		t.Fatalf("unexpected ttl %s, expected %s", result.TTL, time.Minute)
+	}
+
+	if result.MaxEntries != 1000 {
+		t.Fatalf("unexpected default max entries %d, expected %d", result.MaxEntries, 1000)
+	}
+
+	if len(result.Key) != 1 || result.Key[0].Reference.Resource != "input" || result.Key[0].Reference.Path != "id" {
+		t.Fatalf("unexpected cache key %+v", result.Key)
+	}
+}
+
+func TestParseIntermediateBulkhead(t *testing.T) {
+	ctx := context.Background()
+	ctx = logger.WithValue(ctx)
+
+	tests := map[string]*Bulkhead{
+		"concurrency": {MaxConcurrency: 0},
+		"queue":       {MaxConcurrency: 1, MaxQueue: -1},
+		"timeout":     {MaxConcurrency: 1, QueueTimeout: "second"},
+		"no queue":    {MaxConcurrency: 1, QueueTimeout: "1s"},
+	}
+
+	for name, input := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseIntermediateBulkhead(ctx, "flow", input)
+			if err == nil {
+				t.Fatal("unexpected pass")
+			}
+		})
+	}
+
+	result, err := ParseIntermediateBulkhead(ctx, "flow", &Bulkhead{MaxConcurrency: 10, MaxQueue: 5, QueueTimeout: "1s"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.MaxConcurrency != 10 || result.MaxQueue != 5 || result.QueueTimeout != time.Second {
+		t.Fatalf("unexpected bulkhead %+v", result)
+	}
+}
+
+func TestParseIntermediateOnError(t *testing.T) {
+	ctx := context.Background()
+	ctx = logger.WithValue(ctx)
+
+	tests := map[string]map[string]int{
+		"informational": {"timeout": 99},
+		"unknown":       {"timeout": 600},
+	}
+
+	for name, input := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseIntermediateOnError(ctx, "flow", &OnError{Status: input, Properties: hcl.EmptyBody()}, nil)
+			if err == nil {
+				t.Fatal("unexpected pass")
+			}
+		})
+	}
+
+	result, err := ParseIntermediateOnError(ctx, "flow", &OnError{Schema: "error", Status: map[string]int{"timeout": 504}, Properties: hcl.EmptyBody()}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Response == nil || result.Response.Schema != "error" {
+		t.Fatalf("unexpected error response %+v", result.Response)
+	}
+
+	if result.GetStatus("timeout") != 504 {
+		t.Fatalf("unexpected status %d, expected %d", result.GetStatus("timeout"), 504)
+	}
+}
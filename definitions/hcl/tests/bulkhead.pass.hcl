@@ -0,0 +1,21 @@
+flow "echo" {
+    bulkhead {
+        max_concurrency = 100
+        max_queue = 50
+        queue_timeout = "500ms"
+    }
+
+    resource "get" {
+        request "getter" "Get" {
+        }
+    }
+}
+
+proxy "forward" {
+    bulkhead {
+        max_concurrency = 10
+    }
+
+    forward "getter" {
+    }
+}
@@ -0,0 +1,12 @@
+flow "echo" {
+    resource "get" {
+        cache {
+            ttl = "1m"
+            key = ["{{ input:id }}", "{{ input.header:Authorization }}"]
+            max_entries = 500
+        }
+
+        request "getter" "Get" {
+        }
+    }
+}
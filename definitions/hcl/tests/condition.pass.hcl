@@ -0,0 +1,8 @@
+flow "echo" {
+    resource "check" {
+        if = "{{ input:amount > 1000 }}"
+
+        request "fraud" "Check" {
+        }
+    }
+}
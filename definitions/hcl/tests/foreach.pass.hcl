@@ -0,0 +1,9 @@
+flow "echo" {
+    resource "charge" {
+        foreach = "{{ input:items }}"
+        parallel = 5
+
+        request "payments" "Charge" {
+        }
+    }
+}
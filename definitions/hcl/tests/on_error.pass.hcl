@@ -0,0 +1,24 @@
+flow "echo" {
+    resource "get" {
+        request "getter" "Get" {
+        }
+    }
+
+    output "output" {
+        message = "{{ get:message }}"
+    }
+
+    on_error "error" {
+        status_codes = {
+            timeout = 504
+            overloaded = 503
+        }
+
+        header {
+            X-Node = "{{ error:node }}"
+        }
+
+        code = "{{ error:code }}"
+        message = "{{ error:message }}"
+    }
+}
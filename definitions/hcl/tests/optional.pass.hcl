@@ -0,0 +1,13 @@
+flow "echo" {
+    resource "recommendations" {
+        on_error = "continue"
+
+        request "recommender" "Get" {
+        }
+    }
+
+    resource "get" {
+        request "getter" "Get" {
+        }
+    }
+}
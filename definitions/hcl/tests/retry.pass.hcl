@@ -0,0 +1,15 @@
+flow "echo" {
+    resource "get" {
+        request "getter" "Get" {
+        }
+
+        retry {
+            max_attempts = 5
+            backoff = "exponential"
+            delay = "100ms"
+            max_delay = "2s"
+            jitter = true
+            on = ["timeout", "connection"]
+        }
+    }
+}
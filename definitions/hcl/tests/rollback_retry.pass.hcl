@@ -0,0 +1,14 @@
+flow "echo" {
+    resource "get" {
+        request "getter" "Get" {
+        }
+
+        rollback "getter" "Remove" {
+            retry {
+                max_attempts = 10
+                backoff = "constant"
+                delay = "1s"
+            }
+        }
+    }
+}
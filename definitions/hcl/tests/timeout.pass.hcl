@@ -0,0 +1,22 @@
+endpoint "echo" "http" {
+    endpoint = "/"
+    method = "GET"
+}
+
+flow "echo" {
+    timeout = "5s"
+
+    resource "get" {
+        timeout = "500ms"
+
+        request "getter" "Get" {
+        }
+    }
+}
+
+proxy "upstream" {
+    timeout = "10s"
+
+    forward "getter" {
+    }
+}
@@ -1,5 +1,11 @@
 package hcl
 
+import (
+	"time"
+
+	"github.com/jexia/maestro/specs/trace"
+)
+
 // JoinPath joins the given flow paths
 func JoinPath(values ...string) (result string) {
 	for _, value := range values {
@@ -20,3 +26,22 @@ func JoinPath(values ...string) (result string) {
 
 	return result
 }
+
+// ParseDuration parses the given duration string.
+// A zero duration is returned when the given value is empty, defined durations have to be positive.
+func ParseDuration(value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, err
+	}
+
+	if duration <= 0 {
+		return 0, trace.New(trace.WithMessage("invalid duration '%s', expected a positive duration", value))
+	}
+
+	return duration, nil
+}
@@ -1,6 +1,9 @@
 package hcl
 
-import "testing"
+import (
+	"testing"
+	"time"
+)
 
 func TestJoinPath(t *testing.T) {
 	tests := map[string][]string{
@@ -16,3 +19,29 @@ func TestJoinPath(t *testing.T) {
 		}
 	}
 }
+
+func TestParseDuration(t *testing.T) {
+	tests := map[string]time.Duration{
+		"":      0,
+		"500ms": 500 * time.Millisecond,
+		"10s":   10 * time.Second,
+	}
+
+	for input, expected := range tests {
+		result, err := ParseDuration(input)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if result != expected {
+			t.Errorf("unexpected result: %s expected %s", result, expected)
+		}
+	}
+
+	for _, input := range []string{"unknown", "0s", "-1s"} {
+		_, err := ParseDuration(input)
+		if err == nil {
+			t.Errorf("expected a error to be returned for the invalid duration '%s'", input)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package flow
+
+import (
+	"context"
+
+	"github.com/jexia/maestro/refs"
+	"github.com/jexia/maestro/tracing"
+	"github.com/jexia/maestro/transport"
+	"github.com/sirupsen/logrus"
+)
+
+// Dispatch executes the node call in the background without blocking the flow execution.
+// The call is not cancelled once the flow execution returns.
+// Errors are logged and reported to the node span but do not trigger a rollback.
+func (node *Node) Dispatch(ctx context.Context, refs *refs.Store) {
+	if node.wg != nil {
+		node.wg.Add(1)
+	}
+
+	go func() {
+		if node.wg != nil {
+			defer node.wg.Done()
+		}
+
+		call, span := tracing.StartSpan(transport.Detach(ctx), node.Name, tracing.KindInternal)
+		span.SetAttribute("node", node.Name)
+		span.SetAttribute("async", "true")
+
+		err := node.Run(call, refs)
+
+		span.SetError(err)
+		span.Finish()
+
+		if err != nil {
+			node.logger.WithFields(logrus.Fields{
+				"node": node.Name,
+				"err":  err,
+			}).Error("Async call failed")
+
+			return
+		}
+
+		node.logger.WithField("node", node.Name).Debug("Async call completed")
+	}()
+}
@@ -16,8 +16,13 @@ func ConstructBranches(nodes []*Node) {
 	}
 }
 
-// ConstructDependency constructs a dependency for the given node
+// ConstructDependency constructs a dependency for the given node.
+// References to the node itself (ex: a rollback referencing the node response) are ignored.
 func ConstructDependency(node *Node, target string, nodes []*Node) {
+	if node.Name == target {
+		return
+	}
+
 	for _, parent := range nodes {
 		if parent.Name == target {
 			if !node.Previous.Has(parent.Name) {
@@ -84,3 +84,20 @@ lookup:
 		t.Errorf("unexpected start node %+v", start)
 	}
 }
+
+func TestConstructBranchesSelfReference(t *testing.T) {
+	nodes := NewMockNodes()
+	nodes[1].References["self"] = &specs.PropertyReference{
+		Resource: "second",
+	}
+
+	ConstructBranches(nodes)
+
+	if nodes[1].Previous.Has("second") || nodes[1].Next.Has("second") {
+		t.Fatalf("unexpected self dependency %+v", nodes[1])
+	}
+
+	if len(FetchStarting(nodes)) != 1 {
+		t.Fatalf("unexpected starting nodes %+v", FetchStarting(nodes))
+	}
+}
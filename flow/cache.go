@@ -0,0 +1,71 @@
+package flow
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/jexia/maestro/refs"
+	"github.com/jexia/maestro/specs"
+)
+
+// CacheKey constructs the cache key for the given node out of the values stored inside the given store.
+// The references inside the node call request are used when the cache policy does not define any key properties.
+// Key properties are expected to be references, other key properties are ignored.
+func CacheKey(node *specs.Node, store *refs.Store) (string, error) {
+	references := make([]*specs.PropertyReference, 0, len(node.Cache.Key))
+
+	for _, property := range node.Cache.Key {
+		if property.Reference == nil {
+			continue
+		}
+
+		references = append(references, property.Reference)
+	}
+
+	if len(references) == 0 && node.Call != nil && node.Call.GetRequest() != nil {
+		for _, reference := range refs.ParameterReferences(node.Call.GetRequest()) {
+			references = append(references, reference)
+		}
+
+		sort.Slice(references, func(i, j int) bool {
+			return references[i].String() < references[j].String()
+		})
+	}
+
+	values := make([]interface{}, len(references))
+
+	for index, reference := range references {
+		ref := store.Load(reference.Resource, reference.Path)
+		if ref == nil {
+			continue
+		}
+
+		if ref.Repeated != nil {
+			items := make([][]*refs.Snapshot, len(ref.Repeated))
+			for position, item := range ref.Repeated {
+				if item == nil {
+					continue
+				}
+
+				snapshot := item.Snapshot()
+				sort.Slice(snapshot, func(i, j int) bool {
+					return snapshot[i].Resource+snapshot[i].Path < snapshot[j].Resource+snapshot[j].Path
+				})
+
+				items[position] = snapshot
+			}
+
+			values[index] = items
+			continue
+		}
+
+		values[index] = ref.Value
+	}
+
+	bb, err := json.Marshal(values)
+	if err != nil {
+		return "", err
+	}
+
+	return string(bb), nil
+}
@@ -0,0 +1,132 @@
+package flow
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jexia/maestro/cache/lru"
+	"github.com/jexia/maestro/logger"
+	"github.com/jexia/maestro/refs"
+	"github.com/jexia/maestro/specs"
+)
+
+func TestCacheKey(t *testing.T) {
+	node := &specs.Node{
+		Name: "product",
+		Cache: &specs.Cache{
+			Key: []*specs.Property{
+				specs.ParseReference("id", "input:id"),
+			},
+		},
+	}
+
+	first := refs.NewStore(2)
+	first.StoreValue("input", "id", "1")
+	first.StoreValue("input", "name", "shoe")
+
+	second := refs.NewStore(2)
+	second.StoreValue("input", "id", "1")
+	second.StoreValue("input", "name", "boot")
+
+	third := refs.NewStore(1)
+	third.StoreValue("input", "id", "2")
+
+	keys := make([]string, 3)
+	for index, store := range []*refs.Store{first, second, third} {
+		key, err := CacheKey(node, store)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		keys[index] = key
+	}
+
+	if keys[0] != keys[1] {
+		t.Fatalf("unexpected cache key %s, expected %s", keys[1], keys[0])
+	}
+
+	if keys[0] == keys[2] {
+		t.Fatalf("unexpected equal cache key %s", keys[2])
+	}
+}
+
+func TestCacheKeyRequestReferences(t *testing.T) {
+	node := &specs.Node{
+		Name:  "product",
+		Cache: &specs.Cache{},
+		Call: &specs.Call{
+			Request: &specs.ParameterMap{
+				Property: &specs.Property{
+					Nested: map[string]*specs.Property{
+						"id":   specs.ParseReference("id", "input:id"),
+						"name": specs.ParseReference("name", "input:name"),
+					},
+				},
+			},
+		},
+	}
+
+	first := refs.NewStore(2)
+	first.StoreValue("input", "id", "1")
+	first.StoreValue("input", "name", "shoe")
+
+	second := refs.NewStore(2)
+	second.StoreValue("input", "id", "1")
+	second.StoreValue("input", "name", "boot")
+
+	left, err := CacheKey(node, first)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	right, err := CacheKey(node, second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if left == right {
+		t.Fatalf("unexpected equal cache key %s", left)
+	}
+}
+
+func TestCallerCacheHit(t *testing.T) {
+	ctx := logger.WithValue(context.Background())
+	node := &specs.Node{
+		Name: "product",
+		Cache: &specs.Cache{
+			TTL: time.Minute,
+			Key: []*specs.Property{
+				specs.ParseReference("id", "input:id"),
+			},
+		},
+	}
+
+	store := refs.NewStore(1)
+	store.StoreValue("input", "id", "1")
+
+	key, err := CacheKey(node, store)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cache := lru.New(1)
+	cache.Set(key, []*refs.Snapshot{{Resource: "product", Path: "name", Value: "shoe"}}, time.Minute)
+
+	// The transport is not configured, a cache miss would panic
+	caller := &Caller{
+		ctx:   ctx,
+		node:  node,
+		cache: cache,
+	}
+
+	err = caller.Do(ctx, store)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ref := store.Load("product", "name")
+	if ref == nil || ref.Value != "shoe" {
+		t.Fatalf("unexpected cached reference %+v", ref)
+	}
+}
@@ -1,15 +1,22 @@
 package flow
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"io"
+	"io/ioutil"
+	"strconv"
 
+	"github.com/jexia/maestro/cache"
 	"github.com/jexia/maestro/codec"
 	"github.com/jexia/maestro/logger"
 	"github.com/jexia/maestro/metadata"
 	"github.com/jexia/maestro/refs"
 	"github.com/jexia/maestro/specs"
+	"github.com/jexia/maestro/tracing"
 	"github.com/jexia/maestro/transport"
+	"github.com/jexia/maestro/transport/coalesce"
 	"github.com/sirupsen/logrus"
 )
 
@@ -22,7 +29,9 @@ func NewRequest(codec codec.Manager, metadata *metadata.Manager) *Request {
 }
 
 // NewCall constructs a new flow caller from the given transport caller and
-func NewCall(ctx context.Context, node *specs.Node, transport transport.Call, method string, request *Request, response *Request) Call {
+// the given cache is used to store decoded responses when the node defines a cache policy.
+// Identical concurrent calls are deduplicated through the given group when it is not nil.
+func NewCall(ctx context.Context, node *specs.Node, transport transport.Call, method string, request *Request, response *Request, cache cache.Cache, group *coalesce.Group) Call {
 	return &Caller{
 		ctx:       ctx,
 		node:      node,
@@ -30,6 +39,18 @@ func NewCall(ctx context.Context, node *specs.Node, transport transport.Call, me
 		method:    transport.GetMethod(method),
 		request:   request,
 		response:  response,
+		cache:     cache,
+		group:     group,
+	}
+}
+
+// NewResponse constructs a new response for the given codec and header manager.
+// Error bodies returned by the service are decoded using the given error codec when it is not nil.
+func NewResponse(codec codec.Manager, metadata *metadata.Manager, errs codec.Manager) *Request {
+	return &Request{
+		codec:    codec,
+		metadata: metadata,
+		errs:     errs,
 	}
 }
 
@@ -37,6 +58,7 @@ func NewCall(ctx context.Context, node *specs.Node, transport transport.Call, me
 type Request struct {
 	codec    codec.Manager
 	metadata *metadata.Manager
+	errs     codec.Manager
 }
 
 // Caller represents a flow transport caller
@@ -47,6 +69,8 @@ type Caller struct {
 	transport transport.Call
 	request   *Request
 	response  *Request
+	cache     cache.Cache
+	group     *coalesce.Group
 }
 
 // References returns the references inside the configured transport caller
@@ -58,21 +82,77 @@ func (caller *Caller) References() []*specs.Property {
 	return caller.method.References()
 }
 
-// Do is called by the flow manager to call the configured service
+// Do is called by the flow manager to call the configured service.
+// The transport is not called when the decoded response is available inside the configured cache.
 func (caller *Caller) Do(ctx context.Context, store *refs.Store) error {
+	if caller.cache == nil || caller.node.Cache == nil {
+		return caller.Call(ctx, store)
+	}
+
+	key, err := CacheKey(caller.node, store)
+	if err != nil {
+		return err
+	}
+
+	values, has := caller.cache.Get(key)
+	if has {
+		logger.FromCtx(caller.ctx, logger.Flow).WithField("node", caller.node.GetName()).Debug("Cache hit, skipping service call")
+
+		store.Restore(values)
+		return nil
+	}
+
+	err = caller.Call(ctx, store)
+	if err != nil {
+		return err
+	}
+
+	caller.cache.Set(key, store.Snapshot(caller.node.GetName(), specs.JoinPath(caller.node.GetName(), specs.ResourceHeader), specs.JoinPath(caller.node.GetName(), specs.ResourceStatus)), caller.node.Cache.TTL)
+	return nil
+}
+
+// Call calls the configured service and stores the decoded response inside the given store.
+// Identical concurrent calls are deduplicated when request coalescing has been enabled.
+func (caller *Caller) Call(ctx context.Context, store *refs.Store) error {
 	body, err := caller.request.codec.Marshal(store)
 	if err != nil {
 		return err
 	}
 
+	ctx, span := tracing.StartSpan(ctx, caller.node.GetName(), tracing.KindClient)
+	span.SetAttribute("node", caller.node.GetName())
+	defer span.Finish()
+
+	if caller.method != nil {
+		span.SetAttribute("method", caller.method.GetName())
+	}
+
+	header := caller.request.metadata.Marshal(store)
+
+	if caller.group != nil {
+		shared, err := caller.Coalesce(ctx, header, body, store)
+		span.SetAttribute("coalesced", strconv.FormatBool(shared))
+		span.SetError(err)
+		return err
+	}
+
+	err = caller.Send(ctx, header, body, store)
+	span.SetError(err)
+	return err
+}
+
+// Send sends the given request to the configured service and streams the response into the given store
+func (caller *Caller) Send(ctx context.Context, header metadata.MD, body io.Reader, store *refs.Store) error {
 	reader, writer := io.Pipe()
 	w := transport.NewResponseWriter(writer)
 	r := &transport.Request{
-		Header: caller.request.metadata.Marshal(store),
+		Header: header,
 		Method: caller.method,
 		Body:   body,
 	}
 
+	tracing.Inject(ctx, r.Header)
+
 	result := make(chan error, 1)
 	defer close(result)
 
@@ -81,22 +161,126 @@ func (caller *Caller) Do(ctx context.Context, store *refs.Store) error {
 		result <- caller.transport.SendMsg(ctx, w, r, store)
 	}()
 
-	err = caller.response.codec.Unmarshal(reader, store)
-	if err != nil {
-		return err
+	decoded := caller.response.codec.Unmarshal(reader, store)
+	if decoded != nil {
+		reader.CloseWithError(decoded)
 	}
 
-	err = <-result
+	err := <-result
 	if err != nil {
 		logger.FromCtx(caller.ctx, logger.Flow).WithFields(logrus.Fields{
 			"node": caller.node.GetName(),
 			"err":  err,
 		}).Error("Service error")
 
+		caller.Reject(err, store)
 		return err
 	}
 
+	if decoded != nil {
+		return decoded
+	}
+
 	caller.response.metadata.Unmarshal(w.Header(), store)
+	caller.Status(w.Status(), store)
 
 	return nil
 }
+
+// Status stores the given response status code inside the given store.
+// Status codes are not stored when the transport did not return a status code.
+func (caller *Caller) Status(status int, store *refs.Store) {
+	if status == 0 {
+		return
+	}
+
+	store.StoreValue(specs.JoinPath(caller.node.GetName(), specs.ResourceStatus), "", int32(status))
+}
+
+// Reject stores the status code and decodes the error body of the given upstream error into the given store.
+// Error bodies are only decoded when a error schema has been defined.
+func (caller *Caller) Reject(err error, store *refs.Store) {
+	var upstream *transport.Error
+	if !errors.As(err, &upstream) {
+		return
+	}
+
+	caller.Status(upstream.Status, store)
+
+	if caller.response.errs == nil || len(upstream.Body) == 0 {
+		return
+	}
+
+	err = caller.response.errs.Unmarshal(bytes.NewReader(upstream.Body), store)
+	if err != nil {
+		logger.FromCtx(caller.ctx, logger.Flow).WithFields(logrus.Fields{
+			"node": caller.node.GetName(),
+			"err":  err,
+		}).Warn("Unable to decode error body")
+	}
+}
+
+// Coalesce sends the given request to the configured service unless a identical request is already in-flight.
+// The buffered response is decoded into the store of each coalesced caller.
+// The returned boolean reports whether the response has been received from another caller.
+func (caller *Caller) Coalesce(ctx context.Context, header metadata.MD, body io.Reader, store *refs.Store) (bool, error) {
+	bb := []byte{}
+
+	if body != nil {
+		buffer, err := ioutil.ReadAll(body)
+		if err != nil {
+			return false, err
+		}
+
+		bb = buffer
+	}
+
+	key, err := CoalesceKey(caller.References(), header, bb, store)
+	if err != nil {
+		return false, err
+	}
+
+	response, shared, err := caller.group.Do(ctx, key, func(ctx context.Context) (*coalesce.Response, error) {
+		buffer := bytes.NewBuffer(nil)
+		w := transport.NewResponseWriter(buffer)
+		r := &transport.Request{
+			Header: metadata.Copy(header),
+			Method: caller.method,
+			Body:   bytes.NewReader(bb),
+		}
+
+		tracing.Inject(ctx, r.Header)
+
+		err := caller.transport.SendMsg(ctx, w, r, store)
+		if err != nil {
+			return nil, err
+		}
+
+		return &coalesce.Response{
+			Header: w.Header(),
+			Status: w.Status(),
+			Body:   buffer.Bytes(),
+		}, nil
+	})
+
+	if err != nil {
+		logger.FromCtx(caller.ctx, logger.Flow).WithFields(logrus.Fields{
+			"node":      caller.node.GetName(),
+			"coalesced": shared,
+			"err":       err,
+		}).Error("Service error")
+
+		caller.Reject(err, store)
+		return shared, err
+	}
+
+	err = caller.response.codec.Unmarshal(bytes.NewReader(response.Body), store)
+	if err != nil {
+		return shared, err
+	}
+
+	caller.response.metadata.Unmarshal(response.Header, store)
+	caller.Status(response.Status, store)
+
+	return shared, nil
+}
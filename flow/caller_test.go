@@ -0,0 +1,104 @@
+package flow
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jexia/maestro/logger"
+	"github.com/jexia/maestro/refs"
+	"github.com/jexia/maestro/specs"
+	"github.com/jexia/maestro/transport"
+)
+
+type status struct {
+	status int
+	body   string
+	err    error
+}
+
+func (call *status) SendMsg(ctx context.Context, writer transport.ResponseWriter, request *transport.Request, store *refs.Store) error {
+	if call.err != nil {
+		return call.err
+	}
+
+	writer.WriteHeader(call.status)
+	_, err := writer.Write([]byte(call.body))
+	return err
+}
+
+func (call *status) GetMethods() []transport.Method {
+	return nil
+}
+
+func (call *status) GetMethod(name string) transport.Method {
+	return nil
+}
+
+func (call *status) Close() error {
+	return nil
+}
+
+func TestCallerStatus(t *testing.T) {
+	ctx := logger.WithValue(context.Background())
+	node := &specs.Node{Name: "product"}
+	service := &status{status: 201, body: "created"}
+
+	caller := NewCall(ctx, node, service, "Get", NewRequest(&body{}, nil), NewRequest(&body{resource: node.Name}, nil), nil, nil)
+
+	store := refs.NewStore(2)
+	store.StoreValue("input", "id", "1")
+
+	err := caller.Do(ctx, store)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ref := store.Load(specs.JoinPath(node.Name, specs.ResourceStatus), "")
+	if ref == nil || ref.Value != int32(201) {
+		t.Fatalf("unexpected status reference %+v", ref)
+	}
+
+	ref = store.Load(node.Name, "body")
+	if ref == nil || ref.Value != "created" {
+		t.Fatalf("unexpected response reference %+v", ref)
+	}
+}
+
+func TestCallerUpstreamError(t *testing.T) {
+	ctx := logger.WithValue(context.Background())
+	node := &specs.Node{Name: "product"}
+	resource := specs.JoinPath(node.Name, specs.ResourceError)
+	service := &status{
+		err: &transport.Error{
+			Code:   transport.CodeUpstream,
+			Status: 500,
+			Body:   []byte("unexpected error"),
+		},
+	}
+
+	caller := NewCall(ctx, node, service, "Get", NewRequest(&body{}, nil), NewResponse(&body{resource: node.Name}, nil, &body{resource: resource}), nil, nil)
+
+	store := refs.NewStore(2)
+	store.StoreValue("input", "id", "1")
+
+	err := caller.Do(ctx, store)
+	if !errors.Is(err, service.err) {
+		t.Fatalf("unexpected err %v, expected %s", err, service.err)
+	}
+
+	ref := store.Load(specs.JoinPath(node.Name, specs.ResourceStatus), "")
+	if ref == nil || ref.Value != int32(500) {
+		t.Fatalf("unexpected status reference %+v", ref)
+	}
+
+	ref = store.Load(resource, "body")
+	if ref == nil || ref.Value != "unexpected error" {
+		t.Fatalf("unexpected error reference %+v", ref)
+	}
+
+	ref = store.Load(node.Name, "body")
+	if ref != nil && ref.Value != "" {
+		t.Fatalf("unexpected response reference %+v, expected the error body not to be decoded as response", ref)
+	}
+}
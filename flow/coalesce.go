@@ -0,0 +1,42 @@
+package flow
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/jexia/maestro/metadata"
+	"github.com/jexia/maestro/refs"
+	"github.com/jexia/maestro/specs"
+)
+
+// CoalesceKey constructs the coalescing key of a request out of the given marshalled body, header and
+// the values of the given transport references (ex: http path parameters) stored inside the given store.
+func CoalesceKey(references []*specs.Property, header metadata.MD, body []byte, store *refs.Store) (string, error) {
+	values := make([]interface{}, len(references))
+
+	for index, property := range references {
+		if property.Reference == nil {
+			values[index] = property.Default
+			continue
+		}
+
+		ref := store.Load(property.Reference.Resource, property.Reference.Path)
+		if ref == nil {
+			continue
+		}
+
+		values[index] = ref.Value
+	}
+
+	bb, err := json.Marshal([]interface{}{values, header})
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.New()
+	hash.Write(bb)
+	hash.Write(body)
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
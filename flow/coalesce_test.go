@@ -0,0 +1,172 @@
+package flow
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jexia/maestro/logger"
+	"github.com/jexia/maestro/metadata"
+	"github.com/jexia/maestro/refs"
+	"github.com/jexia/maestro/specs"
+	"github.com/jexia/maestro/transport"
+	"github.com/jexia/maestro/transport/coalesce"
+)
+
+type body struct {
+	resource string
+}
+
+func (codec *body) Property() *specs.Property {
+	return nil
+}
+
+func (codec *body) Marshal(store *refs.Store) (io.Reader, error) {
+	return bytes.NewBufferString(store.Load("input", "id").Value.(string)), nil
+}
+
+func (codec *body) Unmarshal(reader io.Reader, store *refs.Store) error {
+	bb, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+
+	store.StoreValue(codec.resource, "body", string(bb))
+	return nil
+}
+
+type upstream struct {
+	calls   int32
+	release chan struct{}
+}
+
+func (call *upstream) SendMsg(ctx context.Context, writer transport.ResponseWriter, request *transport.Request, store *refs.Store) error {
+	atomic.AddInt32(&call.calls, 1)
+	<-call.release
+
+	bb, err := ioutil.ReadAll(request.Body)
+	if err != nil {
+		return err
+	}
+
+	_, err = writer.Write(bb)
+	return err
+}
+
+func (call *upstream) GetMethods() []transport.Method {
+	return nil
+}
+
+func (call *upstream) GetMethod(name string) transport.Method {
+	return nil
+}
+
+func (call *upstream) Close() error {
+	return nil
+}
+
+func TestCoalesceKey(t *testing.T) {
+	store := refs.NewStore(1)
+	store.StoreValue("input", "id", "1")
+
+	references := []*specs.Property{specs.ParseReference("id", "input:id")}
+
+	first, err := CoalesceKey(references, metadata.MD{"Authorization": "token"}, []byte("body"), store)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	second, err := CoalesceKey(references, metadata.MD{"Authorization": "token"}, []byte("body"), store)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if first != second {
+		t.Fatalf("unexpected coalesce key %s, expected %s", second, first)
+	}
+
+	tests := map[string]func() (string, error){
+		"header": func() (string, error) {
+			return CoalesceKey(references, metadata.MD{"Authorization": "other"}, []byte("body"), store)
+		},
+		"body": func() (string, error) {
+			return CoalesceKey(references, metadata.MD{"Authorization": "token"}, []byte("other"), store)
+		},
+		"reference": func() (string, error) {
+			other := refs.NewStore(1)
+			other.StoreValue("input", "id", "2")
+			return CoalesceKey(references, metadata.MD{"Authorization": "token"}, []byte("body"), other)
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			key, err := test()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if key == first {
+				t.Fatal("unexpected equal coalesce key")
+			}
+		})
+	}
+}
+
+func TestCallerCoalesce(t *testing.T) {
+	ctx := logger.WithValue(context.Background())
+	node := &specs.Node{Name: "product"}
+	group := coalesce.NewGroup()
+	service := &upstream{release: make(chan struct{})}
+
+	caller := NewCall(ctx, node, service, "Get", NewRequest(&body{}, nil), NewRequest(&body{resource: node.Name}, nil), nil, group)
+
+	concurrent := 5
+	stores := make([]*refs.Store, concurrent)
+	wg := sync.WaitGroup{}
+	wg.Add(concurrent)
+
+	for index := range stores {
+		store := refs.NewStore(2)
+		store.StoreValue("input", "id", "1")
+		stores[index] = store
+
+		go func() {
+			defer wg.Done()
+
+			err := caller.Do(ctx, store)
+			if err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+
+	// await till all calls are waiting on the in-flight call before releasing the service
+	for {
+		if atomic.LoadInt32(&service.calls) > 0 {
+			break
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	close(service.release)
+	wg.Wait()
+
+	if service.calls != 1 {
+		t.Fatalf("unexpected service calls %d, expected 1", service.calls)
+	}
+
+	for _, store := range stores {
+		ref := store.Load(node.Name, "body")
+		if ref == nil || ref.Value != "1" {
+			t.Fatalf("unexpected response reference %+v", ref)
+		}
+	}
+}
@@ -0,0 +1,20 @@
+package flow
+
+import (
+	"github.com/jexia/maestro/refs"
+	"github.com/jexia/maestro/specs"
+)
+
+// Evaluate evaluates the given condition against the values inside the given reference store.
+// A condition is met when the (single) operand resolves to true or when the operand comparison succeeds.
+func Evaluate(condition *specs.Condition, store *refs.Store) bool {
+	left := store.Value(condition.Left)
+
+	if condition.Right == nil {
+		result, is := left.(bool)
+		return is && result
+	}
+
+	right := store.Value(condition.Right)
+	return specs.Compare(condition.Operator, left, right)
+}
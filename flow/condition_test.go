@@ -0,0 +1,66 @@
+package flow
+
+import (
+	"testing"
+
+	"github.com/jexia/maestro/refs"
+	"github.com/jexia/maestro/specs"
+	"github.com/jexia/maestro/specs/types"
+)
+
+func TestEvaluate(t *testing.T) {
+	store := refs.NewStore(2)
+	store.StoreValue("input", "amount", int64(1500))
+	store.StoreValue("input", "verified", true)
+
+	type test struct {
+		condition *specs.Condition
+		expected  bool
+	}
+
+	tests := map[string]test{
+		"comparison": {
+			condition: &specs.Condition{
+				Left:     &specs.Property{Reference: &specs.PropertyReference{Resource: "input", Path: "amount"}},
+				Operator: specs.OperatorGreater,
+				Right:    &specs.Property{Type: types.TypeInt64, Default: int64(1000)},
+			},
+			expected: true,
+		},
+		"boolean": {
+			condition: &specs.Condition{
+				Left: &specs.Property{Reference: &specs.PropertyReference{Resource: "input", Path: "verified"}},
+			},
+			expected: true,
+		},
+		"expression": {
+			condition: &specs.Condition{
+				Left: &specs.Property{
+					Expression: &specs.Expression{
+						Operator: specs.OperatorAnd,
+						Operands: []*specs.Expression{
+							{Property: &specs.Property{Reference: &specs.PropertyReference{Resource: "input", Path: "verified"}}},
+							{Property: &specs.Property{Type: types.TypeBool, Default: true}},
+						},
+					},
+				},
+			},
+			expected: true,
+		},
+		"unset": {
+			condition: &specs.Condition{
+				Left: &specs.Property{Reference: &specs.PropertyReference{Resource: "input", Path: "unknown"}},
+			},
+			expected: false,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			result := Evaluate(test.condition, store)
+			if result != test.expected {
+				t.Fatalf("unexpected result %t, expected %t", result, test.expected)
+			}
+		})
+	}
+}
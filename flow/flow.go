@@ -2,11 +2,18 @@ package flow
 
 import (
 	"context"
+	"encoding/gob"
+	"encoding/json"
 	"sync"
+	"time"
 
+	"github.com/jexia/maestro/deadletter"
+	"github.com/jexia/maestro/journal"
 	"github.com/jexia/maestro/logger"
 	"github.com/jexia/maestro/refs"
 	"github.com/jexia/maestro/specs"
+	"github.com/jexia/maestro/tracing"
+	"github.com/jexia/maestro/transport/bulkhead"
 	"github.com/sirupsen/logrus"
 )
 
@@ -16,10 +23,57 @@ type Call interface {
 	Do(context.Context, *refs.Store) error
 }
 
+// ManagerOption represents a option which is applied to a flow manager on construction
+type ManagerOption func(*Manager)
+
+// WithTimeout sets the maximum duration of a single flow execution.
+// All nodes that are still in progress are cancelled once the timeout has been exceeded.
+func WithTimeout(timeout time.Duration) ManagerOption {
+	return func(manager *Manager) {
+		manager.Timeout = timeout
+	}
+}
+
+// WithJournal sets the journal used to record the progress of flow executions.
+// Pending rollbacks could be resumed after a crash using the recorded executions.
+func WithJournal(journal journal.Journal) ManagerOption {
+	return func(manager *Manager) {
+		manager.Journal = journal
+	}
+}
+
+// WithDeadLetter sets the dead letter sink receiving the rollbacks which could not be compensated
+func WithDeadLetter(sink deadletter.DeadLetter) ManagerOption {
+	return func(manager *Manager) {
+		manager.DeadLetter = sink
+	}
+}
+
+// WithTracer sets the tracer used to trace flow executions
+func WithTracer(tracer *tracing.Tracer) ManagerOption {
+	return func(manager *Manager) {
+		manager.Tracer = tracer
+	}
+}
+
+// WithBulkhead sets the bulkhead limiting the amount of concurrent flow executions
+func WithBulkhead(bulkhead *bulkhead.Bulkhead) ManagerOption {
+	return func(manager *Manager) {
+		manager.Bulkhead = bulkhead
+	}
+}
+
+// WithHooks appends the given hooks called around the flow, node and rollback executions
+func WithHooks(hooks ...Hooks) ManagerOption {
+	return func(manager *Manager) {
+		manager.Hooks = append(manager.Hooks, hooks...)
+	}
+}
+
 // NewManager constructs a new manager for the given flow.
 // Branches are constructed for the constructed nodes to optimalise performance.
 // Various variables such as the amount of nodes, references and loose ends are collected to optimalise allocations during runtime.
-func NewManager(ctx context.Context, name string, nodes []*Node) *Manager {
+func NewManager(ctx context.Context, name string, nodes []*Node, options ...ManagerOption) *Manager {
 	ConstructBranches(nodes)
 
 	manager := &Manager{
@@ -29,10 +83,20 @@ func NewManager(ctx context.Context, name string, nodes []*Node) *Manager {
 		Nodes:    len(nodes),
 	}
 
+	for _, option := range options {
+		option(manager)
+	}
+
 	ends := make(map[string]*Node, len(nodes))
 	for _, node := range manager.Starting {
 		node.Walk(ends, func(node *Node) {
 			manager.References += len(node.References)
+			node.flow = manager.Name
+			node.hooks = manager.Hooks
+
+			if node.Async {
+				node.wg = &manager.wg
+			}
 		})
 	}
 
@@ -49,6 +113,12 @@ type Manager struct {
 	References int
 	Nodes      int
 	Ends       int
+	Timeout    time.Duration
+	Journal    journal.Journal
+	DeadLetter deadletter.DeadLetter
+	Tracer     *tracing.Tracer
+	Bulkhead   *bulkhead.Bulkhead
+	Hooks      HookChain
 	wg         sync.WaitGroup
 }
 
@@ -57,17 +127,130 @@ func (manager *Manager) GetName() string {
 	return manager.Name
 }
 
+// Execution represents a completed flow execution which could be reverted afterwards
+type Execution struct {
+	ID       string
+	executed *Tracker
+	store    *refs.Store
+}
+
+// MarshalJSON encodes the given execution as its execution id
+func (execution *Execution) MarshalJSON() ([]byte, error) {
+	return json.Marshal(execution.ID)
+}
+
+// GobEncode encodes the given execution as its execution id.
+// Executions are journaled as part of the completed node values of flow calls.
+func (execution *Execution) GobEncode() ([]byte, error) {
+	return []byte(execution.ID), nil
+}
+
+// GobDecode decodes the given execution id.
+// Decoded executions could not be reverted, their executed nodes and references are not available.
+func (execution *Execution) GobDecode(bb []byte) error {
+	execution.ID = string(bb)
+	return nil
+}
+
+// String returns the execution id
+func (execution *Execution) String() string {
+	return execution.ID
+}
+
+// Restored checks whether the given execution has been restored from its execution id
+func (execution *Execution) Restored() bool {
+	return execution.executed == nil
+}
+
+func init() {
+	gob.Register(&Execution{})
+}
+
 // Call calls all the nodes inside the manager if a error is returned is a rollback of all the already executed steps triggered.
 // Nodes are executed concurrently to one another.
+// All nodes still in progress are cancelled once a node fails or once the configured flow timeout has been exceeded.
+// The flow hooks are called around the flow execution, the flow is aborted once a before hook fails.
 func (manager *Manager) Call(ctx context.Context, refs *refs.Store) error {
+	_, err := manager.Execute(ctx, refs)
+	return err
+}
+
+// Execute calls all the nodes inside the manager and returns the completed execution.
+// The returned execution could be reverted using Undo once a later step of the caller fails.
+func (manager *Manager) Execute(ctx context.Context, refs *refs.Store) (execution *Execution, err error) {
 	manager.wg.Add(1)
 	defer manager.wg.Done()
 
 	logger.FromCtx(manager.ctx, logger.Flow).WithField("flow", manager.Name).Debug("Executing flow")
 
+	if manager.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, manager.Timeout)
+		defer cancel()
+	}
+
+	kind := tracing.KindServer
+	if tracing.SpanFromCtx(ctx) != nil {
+		kind = tracing.KindInternal
+	}
+
+	ctx, span := manager.Tracer.Start(ctx, manager.Name, kind)
+	span.SetAttribute("flow", manager.Name)
+	defer span.Finish()
+
+	start := time.Now()
+	defer func() {
+		manager.Hooks.AfterFlow(ctx, manager.Name, time.Since(start), err, refs)
+	}()
+
+	err = manager.Hooks.BeforeFlow(ctx, manager.Name, refs)
+	if err != nil {
+		logger.FromCtx(manager.ctx, logger.Flow).WithFields(logrus.Fields{
+			"flow": manager.Name,
+			"err":  err,
+		}).Warn("Flow execution aborted by hook")
+
+		span.SetError(err)
+		return nil, err
+	}
+
+	err = manager.Bulkhead.Acquire(ctx)
+	if err != nil {
+		logger.FromCtx(manager.ctx, logger.Flow).WithFields(logrus.Fields{
+			"flow": manager.Name,
+			"err":  err,
+		}).Warn("Flow execution rejected")
+
+		span.SetError(err)
+		return nil, err
+	}
+
+	defer manager.Bulkhead.Release()
+
+	var recorder *journal.Recorder
+	if manager.Journal != nil {
+		recorder = journal.NewRecorder(manager.Journal, manager.Name, journal.NewID())
+		ctx = journal.WithRecorder(ctx, recorder)
+
+		err := recorder.Record(journal.Started, "", refs.Snapshot())
+		if err != nil {
+			span.SetError(err)
+			return nil, err
+		}
+	}
+
+	var sender *deadletter.Sender
+	if manager.DeadLetter != nil {
+		sender = manager.NewSender(recorder)
+		ctx = deadletter.WithSender(ctx, sender)
+	}
+
 	processes := NewProcesses(len(manager.Starting))
 	tracker := NewTracker(manager.Nodes)
 
+	ctx = processes.Context(ctx)
+	defer processes.Cancel()
+
 	for _, node := range manager.Starting {
 		go node.Do(ctx, tracker, processes, refs)
 	}
@@ -82,13 +265,38 @@ func (manager *Manager) Call(ctx context.Context, refs *refs.Store) error {
 			"err":  processes.Err(),
 		}).Error("An error occurred, executing rollback")
 
+		span.SetError(processes.Err())
+
+		revert := context.Background()
+		revert = journal.WithRecorder(revert, recorder)
+		revert = deadletter.WithSender(revert, sender)
+		revert = tracing.WithSpan(revert, span)
+
 		manager.wg.Add(1)
-		go manager.Revert(tracker, refs)
-		return processes.Err()
+		go manager.Revert(revert, tracker, refs)
+		return nil, processes.Err()
+	}
+
+	err = recorder.Record(journal.Completed, "", nil)
+	if err != nil {
+		logger.FromCtx(manager.ctx, logger.Flow).WithFields(logrus.Fields{
+			"flow": manager.Name,
+			"err":  err,
+		}).Error("Unable to record flow completion")
 	}
 
 	logger.FromCtx(manager.ctx, logger.Flow).WithField("flow", manager.Name).Debug("Flow completed")
-	return nil
+
+	execution = &Execution{
+		executed: tracker,
+		store:    refs,
+	}
+
+	if recorder != nil {
+		execution.ID = recorder.Execution
+	}
+
+	return execution, nil
 }
 
 // NewStore constructs a new reference store for the given manager
@@ -96,19 +304,69 @@ func (manager *Manager) NewStore() *refs.Store {
 	return refs.NewStore(manager.References)
 }
 
+// NewSender constructs a new dead letter sender for a single flow execution.
+// The execution id of the given recorder is included inside the dead letters if a recorder is given.
+func (manager *Manager) NewSender(recorder *journal.Recorder) *deadletter.Sender {
+	execution := ""
+	if recorder != nil {
+		execution = recorder.Execution
+	}
+
+	return deadletter.NewSender(manager.DeadLetter, manager.Name, execution)
+}
+
+// Undo reverts the given completed execution.
+// The rollbacks are recorded inside the journal using the id of the given execution.
+func (manager *Manager) Undo(ctx context.Context, execution *Execution) error {
+	logger.FromCtx(manager.ctx, logger.Flow).WithFields(logrus.Fields{
+		"flow":      manager.Name,
+		"execution": execution.ID,
+	}).Debug("Reverting completed flow execution")
+
+	var recorder *journal.Recorder
+	if manager.Journal != nil && execution.ID != "" {
+		recorder = journal.NewRecorder(manager.Journal, manager.Name, execution.ID)
+	}
+
+	var sender *deadletter.Sender
+	if manager.DeadLetter != nil {
+		sender = manager.NewSender(recorder)
+	}
+
+	ctx = journal.WithRecorder(ctx, recorder)
+	ctx = deadletter.WithSender(ctx, sender)
+
+	manager.wg.Add(1)
+	return manager.Revert(ctx, execution.executed, execution.store)
+}
+
 // Revert reverts the executed nodes found inside the given tracker.
-// All nodes that have not been executed will be ignored.
-func (manager *Manager) Revert(executed *Tracker, refs *refs.Store) {
+// All nodes that have not been executed or have been skipped will be ignored.
+// The given context is used to execute the rollbacks and should not be cancelled once the flow call returns.
+// The first rollback error is returned once all rollbacks have been executed.
+func (manager *Manager) Revert(ctx context.Context, executed *Tracker, refs *refs.Store) error {
 	defer manager.wg.Done()
 
-	ctx := context.Background()
+	ctx, span := manager.Tracer.Start(ctx, "rollback", tracing.KindInternal)
+	span.SetAttribute("flow", manager.Name)
+	defer span.Finish()
+
+	recorder := journal.FromCtx(ctx)
+	err := recorder.Record(journal.RollbackStarted, "", nil)
+	if err != nil {
+		logger.FromCtx(manager.ctx, logger.Flow).WithFields(logrus.Fields{
+			"flow": manager.Name,
+			"err":  err,
+		}).Error("Unable to record rollback start")
+	}
+
 	tracker := NewTracker(manager.Nodes)
 	ends := make(map[string]*Node, manager.Ends)
 
-	// Include all nodes to the revert tracker that have not been called
+	// Include all nodes to the revert tracker that have not been called, have been skipped or have been dispatched asynchronously
 	for _, node := range manager.Starting {
 		node.Walk(ends, func(node *Node) {
-			if !executed.Met(node) {
+			if !executed.Met(node) || executed.Skipped(node) || node.Async {
 				tracker.Mark(node)
 			}
 		})
@@ -121,9 +379,70 @@ func (manager *Manager) Revert(executed *Tracker, refs *refs.Store) {
 	}
 
 	processes.Wait()
+
+	if processes.Err() != nil {
+		span.SetError(processes.Err())
+
+		logger.FromCtx(manager.ctx, logger.Flow).WithFields(logrus.Fields{
+			"flow": manager.Name,
+			"err":  processes.Err(),
+		}).Error("Rollback failed")
+
+		return processes.Err()
+	}
+
+	err = recorder.Record(journal.Completed, "", nil)
+	if err != nil {
+		logger.FromCtx(manager.ctx, logger.Flow).WithFields(logrus.Fields{
+			"flow": manager.Name,
+			"err":  err,
+		}).Error("Unable to record rollback completion")
+	}
+
+	return nil
+}
+
+// Recover resumes the rollback of the given pending execution.
+// The reference store is restored from the journaled values.
+// Only the completed nodes which have not been reverted yet are reverted.
+func (manager *Manager) Recover(execution *journal.Execution) {
+	logger.FromCtx(manager.ctx, logger.Flow).WithFields(logrus.Fields{
+		"flow":      manager.Name,
+		"execution": execution.ID,
+	}).Info("Recovering pending flow execution")
+
+	store := manager.NewStore()
+	store.Restore(execution.Values)
+
+	executed := NewTracker(manager.Nodes)
+	ends := make(map[string]*Node, manager.Ends)
+
+	for _, node := range manager.Starting {
+		node.Walk(ends, func(node *Node) {
+			_, completed := execution.Completed[node.Name]
+			_, reverted := execution.Reverted[node.Name]
+
+			if completed && !reverted {
+				executed.Mark(node)
+			}
+		})
+	}
+
+	var recorder *journal.Recorder
+	if manager.Journal != nil {
+		recorder = journal.NewRecorder(manager.Journal, manager.Name, execution.ID)
+	}
+
+	var sender *deadletter.Sender
+	if manager.DeadLetter != nil {
+		sender = manager.NewSender(recorder)
+	}
+
+	manager.wg.Add(1)
+	go manager.Revert(deadletter.WithSender(journal.WithRecorder(context.Background(), recorder), sender), executed, store)
 }
 
-// Wait awaits till all calls and rollbacks are completed
+// Wait awaits till all calls, async calls and rollbacks are completed
 func (manager *Manager) Wait() {
 	logger.FromCtx(manager.ctx, logger.Flow).WithField("flow", manager.Name).Info("Awaiting till all processes are completed")
 	manager.wg.Wait()
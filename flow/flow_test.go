@@ -6,10 +6,17 @@ import (
 	"io"
 	"sync"
 	"testing"
+	"time"
 
+	"github.com/jexia/maestro/deadletter"
+	"github.com/jexia/maestro/journal"
 	"github.com/jexia/maestro/logger"
 	"github.com/jexia/maestro/refs"
 	"github.com/jexia/maestro/specs"
+	"github.com/jexia/maestro/specs/types"
+	"github.com/jexia/maestro/tracing"
+	"github.com/jexia/maestro/transport"
+	"github.com/jexia/maestro/transport/bulkhead"
 )
 
 type MockCodec struct{}
@@ -39,6 +46,151 @@ func (caller *caller) Do(context.Context, *refs.Store) error {
 	return caller.Err
 }
 
+type blocking struct {
+	Counter int
+	mutex   sync.Mutex
+}
+
+func (caller *blocking) References() []*specs.Property {
+	return nil
+}
+
+func (caller *blocking) Do(ctx context.Context, store *refs.Store) error {
+	caller.mutex.Lock()
+	caller.Counter++
+	caller.mutex.Unlock()
+
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+type gated struct {
+	Gate chan struct{}
+	Err  error
+}
+
+func (caller *gated) References() []*specs.Property {
+	return nil
+}
+
+func (caller *gated) Do(ctx context.Context, store *refs.Store) error {
+	<-caller.Gate
+	caller.Err = ctx.Err()
+	return caller.Err
+}
+
+type memory struct {
+	entries []*journal.Entry
+	mutex   sync.Mutex
+}
+
+func (mock *memory) Append(entry *journal.Entry) error {
+	mock.mutex.Lock()
+	defer mock.mutex.Unlock()
+
+	mock.entries = append(mock.entries, entry)
+	return nil
+}
+
+func (mock *memory) Pending() ([]*journal.Execution, error) {
+	mock.mutex.Lock()
+	defer mock.mutex.Unlock()
+
+	return journal.Replay(mock.entries), nil
+}
+
+func (mock *memory) Close() error {
+	return nil
+}
+
+type letters struct {
+	letters []*deadletter.Letter
+	mutex   sync.Mutex
+}
+
+func (sink *letters) Send(letter *deadletter.Letter) error {
+	sink.mutex.Lock()
+	defer sink.mutex.Unlock()
+
+	sink.letters = append(sink.letters, letter)
+	return nil
+}
+
+func (sink *letters) Close() error {
+	return nil
+}
+
+type spans struct {
+	spans []*tracing.Span
+	mutex sync.Mutex
+}
+
+func (exporter *spans) Context(ctx context.Context) {}
+
+func (exporter *spans) Export(span *tracing.Span) error {
+	exporter.mutex.Lock()
+	defer exporter.mutex.Unlock()
+
+	exporter.spans = append(exporter.spans, span)
+	return nil
+}
+
+func (exporter *spans) Close() error {
+	return nil
+}
+
+type hooks struct {
+	NopHooks
+	Err       error
+	events    []string
+	durations []time.Duration
+	mutex     sync.Mutex
+}
+
+func (hooks *hooks) record(event string, duration time.Duration) {
+	hooks.mutex.Lock()
+	defer hooks.mutex.Unlock()
+
+	hooks.events = append(hooks.events, event)
+	hooks.durations = append(hooks.durations, duration)
+}
+
+func (hooks *hooks) Count(event string) int {
+	hooks.mutex.Lock()
+	defer hooks.mutex.Unlock()
+
+	result := 0
+	for _, recorded := range hooks.events {
+		if recorded == event {
+			result++
+		}
+	}
+
+	return result
+}
+
+func (hooks *hooks) BeforeFlow(ctx context.Context, flow string, store *refs.Store) error {
+	hooks.record("before_flow", 0)
+	return hooks.Err
+}
+
+func (hooks *hooks) AfterFlow(ctx context.Context, flow string, duration time.Duration, err error, store *refs.Store) {
+	hooks.record("after_flow", duration)
+}
+
+func (hooks *hooks) BeforeNode(ctx context.Context, flow string, node string, store *refs.Store) error {
+	hooks.record("before_node", 0)
+	return nil
+}
+
+func (hooks *hooks) AfterNode(ctx context.Context, flow string, node string, duration time.Duration, err error, store *refs.Store) {
+	hooks.record("after_node", duration)
+}
+
+func (hooks *hooks) AfterRollback(ctx context.Context, flow string, node string, duration time.Duration, err error, store *refs.Store) {
+	hooks.record("after_rollback", duration)
+}
+
 func NewMockFlowManager(caller Call, revert Call) ([]*Node, *Manager) {
 	ctx := context.Background()
 	ctx = logger.WithValue(ctx)
@@ -94,7 +246,7 @@ func TestFailFlowManager(t *testing.T) {
 	nodes[2].Call = &caller{Err: expected}
 
 	err := manager.Call(context.Background(), nil)
-	if err != expected {
+	if !errors.Is(err, expected) {
 		t.Errorf("unexpected result %s, expected %s", err, expected)
 	}
 
@@ -108,3 +260,540 @@ func TestFailFlowManager(t *testing.T) {
 		t.Errorf("unexpected rollback counter total %d, expected %d", rollback.Counter, reverts)
 	}
 }
+
+func TestStructuredErrorFlowManager(t *testing.T) {
+	expected := errors.New("something went wrong")
+	nodes, manager := NewMockFlowManager(&caller{}, nil)
+
+	nodes[2].Call = &caller{Err: expected}
+
+	err := manager.Call(context.Background(), nil)
+	if !errors.Is(err, expected) {
+		t.Fatalf("unexpected result %v, expected %s", err, expected)
+	}
+
+	result := transport.AsError(err)
+	if result.Node != nodes[2].Name {
+		t.Fatalf("unexpected origin node %s, expected %s", result.Node, nodes[2].Name)
+	}
+}
+
+func TestTimeoutFlowManager(t *testing.T) {
+	rollback := &caller{}
+	call := &caller{}
+
+	nodes, manager := NewMockFlowManager(call, rollback)
+	manager.Timeout = 10 * time.Millisecond
+
+	nodes[1].Call = &blocking{}
+
+	err := manager.Call(context.Background(), nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("unexpected result %v, expected %s", err, context.DeadlineExceeded)
+	}
+
+	manager.Wait()
+
+	if rollback.Counter != 2 {
+		t.Errorf("unexpected rollback counter total %d, expected %d", rollback.Counter, 2)
+	}
+}
+
+func TestFailCancelsSiblingsFlowManager(t *testing.T) {
+	expected := errors.New("something went wrong")
+	sibling := &blocking{}
+
+	nodes, manager := NewMockFlowManager(&caller{}, nil)
+
+	nodes[1].Call = sibling
+	nodes[2].Call = &caller{Err: expected}
+
+	result := make(chan error, 1)
+	go func() {
+		result <- manager.Call(context.Background(), nil)
+	}()
+
+	select {
+	case err := <-result:
+		if !errors.Is(err, expected) {
+			t.Fatalf("unexpected result %v, expected %s", err, expected)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("sibling node has not been cancelled")
+	}
+}
+
+func TestConditionalFlowManager(t *testing.T) {
+	expected := errors.New("something went wrong")
+	skipped := &caller{}
+	rollback := &caller{}
+	call := &caller{}
+
+	nodes, manager := NewMockFlowManager(call, rollback)
+
+	nodes[1].Call = skipped
+	nodes[1].Condition = &specs.Condition{
+		Left: &specs.Property{Type: types.TypeBool, Default: false},
+	}
+
+	nodes[3].Call = &caller{Err: expected}
+
+	err := manager.Call(context.Background(), refs.NewStore(0))
+	if !errors.Is(err, expected) {
+		t.Fatalf("unexpected result %v, expected %s", err, expected)
+	}
+
+	manager.Wait()
+
+	if skipped.Counter != 0 {
+		t.Errorf("unexpected skipped counter total %d, expected %d", skipped.Counter, 0)
+	}
+
+	if call.Counter != 2 {
+		t.Errorf("unexpected counter total %d, expected %d", call.Counter, 2)
+	}
+
+	if rollback.Counter != 2 {
+		t.Errorf("unexpected rollback counter total %d, expected %d", rollback.Counter, 2)
+	}
+}
+
+func TestJournalFlowManager(t *testing.T) {
+	call := &caller{}
+	storage := &memory{}
+
+	nodes, manager := NewMockFlowManager(call, nil)
+	manager.Journal = storage
+
+	err := manager.Call(context.Background(), refs.NewStore(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pending, _ := storage.Pending()
+	if len(pending) != 0 {
+		t.Fatalf("unexpected pending executions %d, expected 0", len(pending))
+	}
+
+	completed := 0
+	for _, entry := range storage.entries {
+		if entry.Event == journal.NodeCompleted {
+			completed++
+		}
+	}
+
+	if completed != len(nodes) {
+		t.Fatalf("unexpected completed nodes %d, expected %d", completed, len(nodes))
+	}
+}
+
+func TestJournalFailFlowManager(t *testing.T) {
+	rollback := &caller{}
+	storage := &memory{}
+
+	nodes, manager := NewMockFlowManager(&caller{}, rollback)
+	manager.Journal = storage
+
+	nodes[2].Call = &caller{Err: errors.New("something went wrong")}
+
+	err := manager.Call(context.Background(), refs.NewStore(0))
+	if err == nil {
+		t.Fatal("expected a error to be thrown")
+	}
+
+	manager.Wait()
+
+	pending, _ := storage.Pending()
+	if len(pending) != 0 {
+		t.Fatalf("unexpected pending executions %d, expected the rollback to be completed", len(pending))
+	}
+}
+
+func TestJournalFailRollbackFlowManager(t *testing.T) {
+	storage := &memory{}
+
+	nodes, manager := NewMockFlowManager(&caller{}, &caller{Err: errors.New("rollback failed")})
+	manager.Journal = storage
+
+	nodes[2].Call = &caller{Err: errors.New("something went wrong")}
+
+	err := manager.Call(context.Background(), refs.NewStore(0))
+	if err == nil {
+		t.Fatal("expected a error to be thrown")
+	}
+
+	manager.Wait()
+
+	pending, _ := storage.Pending()
+	if len(pending) != 1 {
+		t.Fatalf("unexpected pending executions %d, expected 1", len(pending))
+	}
+}
+
+func TestRecoverFlowManager(t *testing.T) {
+	rollback := &caller{}
+	storage := &memory{}
+
+	nodes, manager := NewMockFlowManager(&caller{}, rollback)
+	manager.Journal = storage
+
+	execution := &journal.Execution{
+		ID: journal.NewID(),
+		Completed: map[string]struct{}{
+			nodes[0].Name: {},
+			nodes[1].Name: {},
+		},
+		Reverted: map[string]struct{}{
+			nodes[1].Name: {},
+		},
+	}
+
+	manager.Recover(execution)
+	manager.Wait()
+
+	if rollback.Counter != 1 {
+		t.Fatalf("unexpected rollback counter total %d, expected %d", rollback.Counter, 1)
+	}
+
+	pending, _ := storage.Pending()
+	if len(pending) != 0 {
+		t.Fatalf("unexpected pending executions %d, expected the recovered execution to be completed", len(pending))
+	}
+}
+
+func TestDeadLetterFlowManager(t *testing.T) {
+	expected := errors.New("rollback failed")
+	sink := &letters{}
+	storage := &memory{}
+
+	nodes, manager := NewMockFlowManager(&caller{}, &caller{Err: expected})
+	manager.Name = "checkout"
+	manager.Journal = storage
+	manager.DeadLetter = sink
+
+	nodes[2].Call = &caller{Err: errors.New("something went wrong")}
+
+	store := refs.NewStore(1)
+	store.StoreValue("input", "id", "1")
+
+	err := manager.Call(context.Background(), store)
+	if err == nil {
+		t.Fatal("expected a error to be thrown")
+	}
+
+	manager.Wait()
+
+	if len(sink.letters) != 1 {
+		t.Fatalf("unexpected dead letters %d, expected 1", len(sink.letters))
+	}
+
+	letter := sink.letters[0]
+	if letter.Flow != manager.Name || letter.Error != expected.Error() {
+		t.Fatalf("unexpected dead letter %+v", letter)
+	}
+
+	pending, _ := storage.Pending()
+	if len(pending) != 1 || letter.Execution != pending[0].ID {
+		t.Fatal("dead letter execution does not match the pending execution")
+	}
+
+	if len(letter.Values) != 1 || letter.Values[0].Value != "1" {
+		t.Fatalf("unexpected dead letter values %+v", letter.Values)
+	}
+}
+
+func TestTracingFlowManager(t *testing.T) {
+	exporter := &spans{}
+	nodes, manager := NewMockFlowManager(&caller{}, nil)
+	manager.Name = "checkout"
+	manager.Tracer = tracing.NewTracer(exporter)
+
+	err := manager.Call(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(exporter.spans) != len(nodes)+1 {
+		t.Fatalf("unexpected amount of spans %d, expected %d", len(exporter.spans), len(nodes)+1)
+	}
+
+	root := exporter.spans[len(exporter.spans)-1]
+	if root.Name != manager.Name || root.Kind != tracing.KindServer {
+		t.Fatalf("unexpected root span %s (%s)", root.Name, root.Kind)
+	}
+
+	for _, span := range exporter.spans[:len(nodes)] {
+		if span.Context.TraceID != root.Context.TraceID {
+			t.Fatalf("unexpected trace id %s inside span %s, expected %s", span.Context.TraceID, span.Name, root.Context.TraceID)
+		}
+
+		if span.Parent != root.Context.SpanID {
+			t.Fatalf("unexpected parent %s inside span %s, expected %s", span.Parent, span.Name, root.Context.SpanID)
+		}
+	}
+}
+
+func TestTracingFailFlowManager(t *testing.T) {
+	expected := errors.New("something went wrong")
+	exporter := &spans{}
+	nodes, manager := NewMockFlowManager(&caller{}, &caller{})
+	manager.Name = "checkout"
+	manager.Tracer = tracing.NewTracer(exporter)
+
+	nodes[2].Call = &caller{Err: expected}
+
+	err := manager.Call(context.Background(), nil)
+	if !errors.Is(err, expected) {
+		t.Fatalf("unexpected err %s, expected %s", err, expected)
+	}
+
+	manager.Wait()
+
+	failed := map[string]bool{}
+	for _, span := range exporter.spans {
+		if span.Error != "" {
+			failed[span.Name] = true
+		}
+	}
+
+	if !failed[manager.Name] || !failed[nodes[2].Name] {
+		t.Fatalf("unexpected failed spans %+v", failed)
+	}
+}
+
+func TestBulkheadFlowManager(t *testing.T) {
+	caller := &blocking{}
+	_, manager := NewMockFlowManager(caller, nil)
+	manager.Bulkhead = bulkhead.New("flow", &bulkhead.Options{MaxConcurrency: 1})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	result := make(chan error, 1)
+
+	go func() {
+		result <- manager.Call(ctx, nil)
+	}()
+
+	// await till the first execution is in progress
+	for {
+		caller.mutex.Lock()
+		counter := caller.Counter
+		caller.mutex.Unlock()
+
+		if counter > 0 {
+			break
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+
+	err := manager.Call(context.Background(), nil)
+	if err != bulkhead.ErrQueueFull {
+		t.Fatalf("unexpected err %v, expected %s", err, bulkhead.ErrQueueFull)
+	}
+
+	cancel()
+	<-result
+	manager.Wait()
+
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err = manager.Call(ctx, nil)
+	if err == bulkhead.ErrQueueFull {
+		t.Fatal("expected the bulkhead slot to be released")
+	}
+}
+
+func TestAsyncFlowManager(t *testing.T) {
+	async := &gated{Gate: make(chan struct{})}
+	call := &caller{}
+
+	nodes, manager := NewMockFlowManager(call, nil)
+
+	nodes[1].Call = async
+	nodes[1].Async = true
+	nodes[1].wg = &manager.wg
+
+	err := manager.Call(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if call.Counter != 3 {
+		t.Errorf("unexpected counter total %d, expected %d", call.Counter, 3)
+	}
+
+	close(async.Gate)
+	manager.Wait()
+
+	if async.Err != nil {
+		t.Fatalf("unexpected async call err %s, expected the call not to be cancelled", async.Err)
+	}
+}
+
+func TestAsyncFailFlowManager(t *testing.T) {
+	rollback := &caller{}
+	async := &caller{Err: errors.New("something went wrong")}
+
+	nodes, manager := NewMockFlowManager(&caller{}, rollback)
+
+	nodes[1].Call = async
+	nodes[1].Rollback = nil
+	nodes[1].Async = true
+	nodes[1].wg = &manager.wg
+
+	err := manager.Call(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	manager.Wait()
+
+	if async.Counter != 1 {
+		t.Errorf("unexpected async counter total %d, expected %d", async.Counter, 1)
+	}
+
+	if rollback.Counter != 0 {
+		t.Errorf("unexpected rollback counter total %d, expected %d", rollback.Counter, 0)
+	}
+}
+
+func TestOptionalFlowManager(t *testing.T) {
+	call := &caller{}
+	optional := &caller{Err: errors.New("something went wrong")}
+
+	nodes, manager := NewMockFlowManager(call, nil)
+
+	nodes[2].Call = optional
+	nodes[2].Optional = true
+
+	err := manager.Call(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if call.Counter != 3 {
+		t.Errorf("unexpected counter total %d, expected %d", call.Counter, 3)
+	}
+}
+
+func TestOptionalRollbackFlowManager(t *testing.T) {
+	expected := errors.New("something went wrong")
+	rollback := &caller{}
+
+	nodes, manager := NewMockFlowManager(&caller{}, rollback)
+
+	nodes[1].Call = &caller{Err: expected}
+	nodes[1].Optional = true
+
+	nodes[3].Call = &caller{Err: expected}
+
+	err := manager.Call(context.Background(), nil)
+	if !errors.Is(err, expected) {
+		t.Fatalf("unexpected result %v, expected %s", err, expected)
+	}
+
+	manager.Wait()
+
+	if rollback.Counter != 2 {
+		t.Errorf("unexpected rollback counter total %d, expected %d", rollback.Counter, 2)
+	}
+}
+
+func WithMockHooks(manager *Manager, nodes []*Node, hooks Hooks) {
+	WithHooks(hooks)(manager)
+
+	for _, node := range nodes {
+		node.flow = manager.Name
+		node.hooks = manager.Hooks
+	}
+}
+
+func TestHooksFlowManager(t *testing.T) {
+	hooks := &hooks{}
+	nodes, manager := NewMockFlowManager(&caller{}, nil)
+	WithMockHooks(manager, nodes, hooks)
+
+	err := manager.Call(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]int{
+		"before_flow":    1,
+		"after_flow":     1,
+		"before_node":    len(nodes),
+		"after_node":     len(nodes),
+		"after_rollback": 0,
+	}
+
+	for event, expected := range tests {
+		if hooks.Count(event) != expected {
+			t.Errorf("unexpected %s hook calls %d, expected %d", event, hooks.Count(event), expected)
+		}
+	}
+}
+
+func TestHooksAbortFlowManager(t *testing.T) {
+	expected := errors.New("unauthorized")
+	hooks := &hooks{Err: expected}
+	call := &caller{}
+
+	nodes, manager := NewMockFlowManager(call, nil)
+	WithMockHooks(manager, nodes, hooks)
+
+	err := manager.Call(context.Background(), nil)
+	if !errors.Is(err, expected) {
+		t.Fatalf("unexpected result %v, expected %s", err, expected)
+	}
+
+	if call.Counter != 0 {
+		t.Errorf("unexpected counter total %d, expected %d", call.Counter, 0)
+	}
+
+	if hooks.Count("after_flow") != 1 {
+		t.Errorf("unexpected after flow hook calls %d, expected %d", hooks.Count("after_flow"), 1)
+	}
+}
+
+func TestHooksRollbackFlowManager(t *testing.T) {
+	hooks := &hooks{}
+	nodes, manager := NewMockFlowManager(&caller{}, &caller{})
+	WithMockHooks(manager, nodes, hooks)
+
+	nodes[2].Call = &caller{Err: errors.New("something went wrong")}
+
+	err := manager.Call(context.Background(), nil)
+	if err == nil {
+		t.Fatal("unexpected pass")
+	}
+
+	manager.Wait()
+
+	if hooks.Count("after_rollback") != 2 {
+		t.Errorf("unexpected after rollback hook calls %d, expected %d", hooks.Count("after_rollback"), 2)
+	}
+}
+
+func TestHookChain(t *testing.T) {
+	expected := errors.New("unauthorized")
+	first := &hooks{Err: expected}
+	second := &hooks{}
+
+	chain := HookChain{first, second}
+
+	err := chain.BeforeFlow(context.Background(), "flow", nil)
+	if !errors.Is(err, expected) {
+		t.Fatalf("unexpected result %v, expected %s", err, expected)
+	}
+
+	if second.Count("before_flow") != 0 {
+		t.Fatal("unexpected before flow hook call after a failed hook")
+	}
+
+	chain.AfterFlow(context.Background(), "flow", time.Second, err, nil)
+
+	if first.Count("after_flow") != 1 || second.Count("after_flow") != 1 {
+		t.Fatal("expected all after flow hooks to be called")
+	}
+}
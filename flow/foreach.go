@@ -0,0 +1,126 @@
+package flow
+
+import (
+	"context"
+	"sync"
+
+	"github.com/jexia/maestro/deadletter"
+	"github.com/jexia/maestro/refs"
+	"github.com/jexia/maestro/specs/lookup"
+	"github.com/sirupsen/logrus"
+)
+
+// Iterate executes the node call for each item inside the configured foreach property.
+// Items are called concurrently, the amount of concurrent calls is bounded by the configured parallelism.
+// Items are called one at a time when no parallelism has been configured.
+// The stores of all iterations are stored as a repeated reference under the node name.
+// The successful iterations are reverted once one of the iterations fails.
+func (node *Node) Iterate(ctx context.Context, store *refs.Store) error {
+	var items []*refs.Store
+
+	ref := store.Load(node.Foreach.Property.Reference.Resource, node.Foreach.Property.Reference.Path)
+	if ref != nil {
+		items = ref.Repeated
+	}
+
+	node.logger.WithFields(logrus.Fields{
+		"node":  node.Name,
+		"items": len(items),
+	}).Debug("Executing foreach node")
+
+	processes := NewProcesses(0)
+	ctx = processes.Context(ctx)
+	defer processes.Cancel()
+
+	results := make([]*refs.Store, len(items))
+	parallel := node.Foreach.Parallel
+	if parallel <= 0 {
+		parallel = 1
+	}
+
+	semaphore := make(chan struct{}, parallel)
+	wg := sync.WaitGroup{}
+
+	for index, item := range items {
+		semaphore <- struct{}{}
+
+		if processes.Err() != nil || ctx.Err() != nil {
+			<-semaphore
+			break
+		}
+
+		wg.Add(1)
+		go func(index int, item *refs.Store) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			// The item references are copied into the iteration store to keep the iteration self-contained
+			iteration := refs.NewOverlay(len(node.References), store)
+			iteration.Restore(item.Snapshot())
+
+			err := node.Execute(ctx, iteration)
+			if err != nil {
+				processes.Fatal(err)
+				return
+			}
+
+			results[index] = iteration
+		}(index, item)
+	}
+
+	wg.Wait()
+
+	successful := make([]*refs.Store, 0, len(results))
+	for _, result := range results {
+		if result != nil {
+			successful = append(successful, result)
+		}
+	}
+
+	err := processes.Err()
+	if err == nil && len(successful) != len(items) {
+		err = ctx.Err()
+	}
+
+	if err != nil {
+		if node.Rollback != nil && len(successful) > 0 {
+			node.logger.WithFields(logrus.Fields{
+				"node":       node.Name,
+				"successful": len(successful),
+			}).Error("Foreach iteration failed, reverting successful iterations")
+
+			node.RevertIterations(deadletter.WithSender(context.Background(), deadletter.FromCtx(ctx)), store, successful)
+		}
+
+		return err
+	}
+
+	reference := refs.New(lookup.SelfRef)
+	reference.Repeated = successful
+	store.StoreReference(node.Name, reference)
+
+	return nil
+}
+
+// RevertIterations executes the node rollback for each of the given iteration stores.
+// All iterations are reverted even if one of the rollbacks fails, the first thrown error is returned.
+// Failed iterations are send to the dead letter sink including the values of the given flow store.
+func (node *Node) RevertIterations(ctx context.Context, store *refs.Store, iterations []*refs.Store) (result error) {
+	for _, iteration := range iterations {
+		err := node.Compensate(ctx, iteration)
+		if err != nil {
+			node.logger.WithFields(logrus.Fields{
+				"node": node.Name,
+				"err":  err,
+			}).Error("Iteration rollback failed")
+
+			node.Bury(ctx, err, store, iteration)
+
+			if result == nil {
+				result = err
+			}
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,221 @@
+package flow
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jexia/maestro/refs"
+	"github.com/jexia/maestro/specs"
+	"github.com/jexia/maestro/specs/lookup"
+)
+
+type iterator struct {
+	Resource string
+	Fail     string
+	Delay    time.Duration
+	Counter  int
+	Active   int
+	Max      int
+	mutex    sync.Mutex
+}
+
+func (caller *iterator) References() []*specs.Property {
+	return nil
+}
+
+func (caller *iterator) Do(ctx context.Context, store *refs.Store) error {
+	caller.mutex.Lock()
+	caller.Counter++
+	caller.Active++
+	if caller.Active > caller.Max {
+		caller.Max = caller.Active
+	}
+	caller.mutex.Unlock()
+
+	defer func() {
+		caller.mutex.Lock()
+		caller.Active--
+		caller.mutex.Unlock()
+	}()
+
+	time.Sleep(caller.Delay)
+
+	id := store.Load("input", "items.id").Value.(string)
+	if id == caller.Fail {
+		return errors.New("unexpected item")
+	}
+
+	store.StoreValue(caller.Resource, "id", id)
+	return nil
+}
+
+func NewMockItems(ids ...string) *refs.Store {
+	store := refs.NewStore(1)
+	reference := refs.New("items")
+	reference.Repeating(len(ids))
+
+	for index, id := range ids {
+		item := refs.NewStore(1)
+		item.StoreValue("input", "items.id", id)
+		reference.Set(index, item)
+	}
+
+	store.StoreReference("input", reference)
+	return store
+}
+
+func NewMockForeachNode(name string, parallel int, caller Call, rollback Call) *Node {
+	node := NewMockNode(name, caller, rollback)
+	node.Foreach = &specs.Foreach{
+		Property: &specs.Property{
+			Reference: &specs.PropertyReference{Resource: "input", Path: "items"},
+		},
+		Parallel: parallel,
+	}
+
+	return node
+}
+
+func TestNodeIterate(t *testing.T) {
+	ids := []string{"first", "second", "third"}
+	call := &iterator{Resource: "charge"}
+	node := NewMockForeachNode("charge", 10, call, nil)
+	store := NewMockItems(ids...)
+
+	err := node.Iterate(context.Background(), store)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result := store.Load("charge", lookup.SelfRef)
+	if result == nil {
+		t.Fatal("foreach results have not been stored")
+	}
+
+	if len(result.Repeated) != len(ids) {
+		t.Fatalf("unexpected results %d, expected %d", len(result.Repeated), len(ids))
+	}
+
+	for index, id := range ids {
+		ref := result.Repeated[index].Load("charge", "id")
+		if ref == nil {
+			t.Fatalf("iteration %d response has not been stored", index)
+		}
+
+		if ref.Value != id {
+			t.Fatalf("unexpected iteration response %s, expected %s", ref.Value, id)
+		}
+	}
+
+	if store.Load("charge", "id") != nil {
+		t.Fatal("iteration response has been stored inside the flow store")
+	}
+}
+
+func TestNodeIterateParallel(t *testing.T) {
+	parallel := 2
+	call := &iterator{Resource: "charge", Delay: 10 * time.Millisecond}
+	node := NewMockForeachNode("charge", parallel, call, nil)
+	store := NewMockItems("first", "second", "third", "fourth", "fifth")
+
+	err := node.Iterate(context.Background(), store)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if call.Counter != 5 {
+		t.Fatalf("unexpected counter total %d, expected %d", call.Counter, 5)
+	}
+
+	if call.Max > parallel {
+		t.Fatalf("unexpected concurrent calls %d, expected at most %d", call.Max, parallel)
+	}
+}
+
+func TestNodeIterateWithoutParallel(t *testing.T) {
+	call := &iterator{Resource: "charge", Delay: time.Millisecond}
+	node := NewMockForeachNode("charge", 0, call, nil)
+	store := NewMockItems("first", "second", "third")
+
+	err := node.Iterate(context.Background(), store)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if call.Counter != 3 {
+		t.Fatalf("unexpected counter total %d, expected %d", call.Counter, 3)
+	}
+
+	if call.Max > 1 {
+		t.Fatalf("unexpected concurrent calls %d, expected at most %d", call.Max, 1)
+	}
+}
+
+func TestNodeIterateEmpty(t *testing.T) {
+	call := &iterator{Resource: "charge"}
+	node := NewMockForeachNode("charge", 10, call, nil)
+	store := NewMockItems()
+
+	err := node.Iterate(context.Background(), store)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if call.Counter != 0 {
+		t.Fatalf("unexpected counter total %d, expected %d", call.Counter, 0)
+	}
+
+	result := store.Load("charge", lookup.SelfRef)
+	if result == nil || len(result.Repeated) != 0 {
+		t.Fatal("expected an empty repeated reference to be stored")
+	}
+}
+
+func TestNodeIterateRollback(t *testing.T) {
+	call := &iterator{Resource: "charge", Fail: "second"}
+	rollback := &caller{}
+	node := NewMockForeachNode("charge", 1, call, rollback)
+	store := NewMockItems("first", "second", "third")
+
+	tracker := NewTracker(1)
+	processes := NewProcesses(1)
+
+	node.Do(context.Background(), tracker, processes, store)
+	processes.Wait()
+
+	if processes.Err() == nil {
+		t.Fatal("expected a error to be thrown")
+	}
+
+	if tracker.Met(node) {
+		t.Fatal("failed foreach node has been marked as completed")
+	}
+
+	if rollback.Counter != 1 {
+		t.Fatalf("unexpected rollback counter total %d, expected %d", rollback.Counter, 1)
+	}
+}
+
+func TestNodeUndoIterations(t *testing.T) {
+	call := &iterator{Resource: "charge"}
+	rollback := &caller{}
+	node := NewMockForeachNode("charge", 10, call, rollback)
+	store := NewMockItems("first", "second", "third")
+
+	err := node.Iterate(context.Background(), store)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = node.Undo(context.Background(), store)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rollback.Counter != 3 {
+		t.Fatalf("unexpected rollback counter total %d, expected %d", rollback.Counter, 3)
+	}
+}
@@ -0,0 +1,133 @@
+package flow
+
+import (
+	"context"
+	"time"
+
+	"github.com/jexia/maestro/refs"
+)
+
+// Hooks represents cross-cutting logic executed around flow, node and rollback executions.
+// Errors returned by the before hooks abort the given execution.
+// The after hooks receive the duration and the error of the given execution.
+type Hooks interface {
+	BeforeFlow(ctx context.Context, flow string, store *refs.Store) error
+	AfterFlow(ctx context.Context, flow string, duration time.Duration, err error, store *refs.Store)
+	BeforeNode(ctx context.Context, flow string, node string, store *refs.Store) error
+	AfterNode(ctx context.Context, flow string, node string, duration time.Duration, err error, store *refs.Store)
+	BeforeRollback(ctx context.Context, flow string, node string, store *refs.Store) error
+	AfterRollback(ctx context.Context, flow string, node string, duration time.Duration, err error, store *refs.Store)
+}
+
+// NopHooks implements all hooks without performing any action.
+// NopHooks could be embedded to implement a subset of the hooks.
+type NopHooks struct{}
+
+// BeforeFlow is called before a flow is executed
+func (NopHooks) BeforeFlow(context.Context, string, *refs.Store) error { return nil }
+
+// AfterFlow is called once a flow has been executed
+func (NopHooks) AfterFlow(context.Context, string, time.Duration, error, *refs.Store) {}
+
+// BeforeNode is called before a node is executed
+func (NopHooks) BeforeNode(context.Context, string, string, *refs.Store) error { return nil }
+
+// AfterNode is called once a node has been executed
+func (NopHooks) AfterNode(context.Context, string, string, time.Duration, error, *refs.Store) {}
+
+// BeforeRollback is called before a node rollback is executed
+func (NopHooks) BeforeRollback(context.Context, string, string, *refs.Store) error { return nil }
+
+// AfterRollback is called once a node rollback has been executed
+func (NopHooks) AfterRollback(context.Context, string, string, time.Duration, error, *refs.Store) {}
+
+// HookChain calls the given hooks in order.
+// The before hooks stop at the first error, the after hooks are always called.
+type HookChain []Hooks
+
+// BeforeFlow calls the before flow hooks
+func (chain HookChain) BeforeFlow(ctx context.Context, flow string, store *refs.Store) error {
+	for _, hooks := range chain {
+		err := hooks.BeforeFlow(ctx, flow, store)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AfterFlow calls the after flow hooks
+func (chain HookChain) AfterFlow(ctx context.Context, flow string, duration time.Duration, err error, store *refs.Store) {
+	for _, hooks := range chain {
+		hooks.AfterFlow(ctx, flow, duration, err, store)
+	}
+}
+
+// BeforeNode calls the before node hooks
+func (chain HookChain) BeforeNode(ctx context.Context, flow string, node string, store *refs.Store) error {
+	for _, hooks := range chain {
+		err := hooks.BeforeNode(ctx, flow, node, store)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AfterNode calls the after node hooks
+func (chain HookChain) AfterNode(ctx context.Context, flow string, node string, duration time.Duration, err error, store *refs.Store) {
+	for _, hooks := range chain {
+		hooks.AfterNode(ctx, flow, node, duration, err, store)
+	}
+}
+
+// BeforeRollback calls the before rollback hooks
+func (chain HookChain) BeforeRollback(ctx context.Context, flow string, node string, store *refs.Store) error {
+	for _, hooks := range chain {
+		err := hooks.BeforeRollback(ctx, flow, node, store)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AfterRollback calls the after rollback hooks
+func (chain HookChain) AfterRollback(ctx context.Context, flow string, node string, duration time.Duration, err error, store *refs.Store) {
+	for _, hooks := range chain {
+		hooks.AfterRollback(ctx, flow, node, duration, err, store)
+	}
+}
+
+// Run executes the node call surrounded by the node hooks
+func (node *Node) Run(ctx context.Context, store *refs.Store) error {
+	start := time.Now()
+
+	err := node.hooks.BeforeNode(ctx, node.flow, node.Name, store)
+	if err == nil {
+		if node.Foreach != nil {
+			err = node.Iterate(ctx, store)
+		} else {
+			err = node.Execute(ctx, store)
+		}
+	}
+
+	node.hooks.AfterNode(ctx, node.flow, node.Name, time.Since(start), err, store)
+	return err
+}
+
+// RunRollback executes the node rollback surrounded by the rollback hooks
+func (node *Node) RunRollback(ctx context.Context, store *refs.Store) error {
+	start := time.Now()
+
+	err := node.hooks.BeforeRollback(ctx, node.flow, node.Name, store)
+	if err == nil {
+		err = node.Undo(ctx, store)
+	}
+
+	node.hooks.AfterRollback(ctx, node.flow, node.Name, time.Since(start), err, store)
+	return err
+}
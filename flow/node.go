@@ -2,10 +2,17 @@ package flow
 
 import (
 	"context"
+	"sync"
+	"time"
 
+	"github.com/jexia/maestro/deadletter"
+	"github.com/jexia/maestro/journal"
 	"github.com/jexia/maestro/logger"
 	"github.com/jexia/maestro/refs"
 	"github.com/jexia/maestro/specs"
+	"github.com/jexia/maestro/specs/lookup"
+	"github.com/jexia/maestro/tracing"
+	"github.com/jexia/maestro/transport"
 	"github.com/sirupsen/logrus"
 )
 
@@ -19,6 +26,24 @@ func NewNode(ctx context.Context, node *specs.Node, call, rollback Call) *Node {
 		references.MergeLeft(refs.ParameterReferences(node.Call.GetRequest()))
 	}
 
+	if node.Foreach != nil {
+		references.MergeLeft(refs.PropertyReferences(node.Foreach.Property))
+	}
+
+	if node.Cache != nil {
+		for _, property := range node.Cache.Key {
+			references.MergeLeft(refs.PropertyReferences(property))
+		}
+	}
+
+	if node.Condition != nil {
+		references.MergeLeft(refs.PropertyReferences(node.Condition.Left))
+
+		if node.Condition.Right != nil {
+			references.MergeLeft(refs.PropertyReferences(node.Condition.Right))
+		}
+	}
+
 	if call != nil {
 		for _, prop := range call.References() {
 			references.MergeLeft(refs.PropertyReferences(prop))
@@ -34,15 +59,22 @@ func NewNode(ctx context.Context, node *specs.Node, call, rollback Call) *Node {
 	logger := logger.FromCtx(ctx, logger.Flow)
 
 	return &Node{
-		ctx:        ctx,
-		logger:     logger,
-		Name:       node.GetName(),
-		Previous:   []*Node{},
-		Call:       call,
-		Rollback:   rollback,
-		DependsOn:  node.DependsOn,
-		References: references,
-		Next:       []*Node{},
+		ctx:           ctx,
+		logger:        logger,
+		Name:          node.GetName(),
+		Previous:      []*Node{},
+		Call:          call,
+		Rollback:      rollback,
+		Retry:         node.Retry,
+		RollbackRetry: node.RollbackRetry,
+		Timeout:       node.Timeout,
+		Condition:     node.Condition,
+		Foreach:       node.Foreach,
+		Async:         node.IsAsync(),
+		Optional:      node.IsOptional(),
+		DependsOn:     node.DependsOn,
+		References:    references,
+		Next:          []*Node{},
 	}
 }
 
@@ -62,19 +94,33 @@ func (nodes Nodes) Has(name string) bool {
 
 // Node represents a collection of callers and rollbacks which could be executed parallel.
 type Node struct {
-	ctx        context.Context
-	logger     *logrus.Logger
-	Name       string
-	Previous   Nodes
-	Call       Call
-	Rollback   Call
-	DependsOn  map[string]*specs.Node
-	References map[string]*specs.PropertyReference
-	Next       Nodes
+	ctx           context.Context
+	logger        *logrus.Logger
+	Name          string
+	Previous      Nodes
+	Call          Call
+	Rollback      Call
+	Retry         *specs.Retry
+	RollbackRetry *specs.Retry
+	Timeout       time.Duration
+	Condition     *specs.Condition
+	Foreach       *specs.Foreach
+	Async         bool
+	Optional      bool
+	DependsOn     map[string]*specs.Node
+	References    map[string]*specs.PropertyReference
+	Next          Nodes
+	flow          string
+	hooks         HookChain
+	wg            *sync.WaitGroup
 }
 
 // Do executes the given node an calls the next nodes.
 // If one of the nodes fails is the error marked and are the processes aborted.
+// Nodes whose condition is not met are skipped but still unblock their next nodes.
+// Async nodes are dispatched in the background and unblock their next nodes directly.
+// Optional nodes which fail are skipped and do not abort the flow.
+// Errors are wrapped into structured errors originating from the given node.
 func (node *Node) Do(ctx context.Context, tracker *Tracker, processes *Processes, refs *refs.Store) {
 	defer processes.Done()
 	node.logger.WithField("node", node.Name).Debug("Executing node call")
@@ -85,24 +131,64 @@ func (node *Node) Do(ctx context.Context, tracker *Tracker, processes *Processes
 	}
 
 	tracker.Lock(node)
+	defer tracker.Unlock(node)
+
 	if tracker.Met(node) {
 		node.logger.WithField("node", node.Name).Debug("Node already executed")
 		return
 	}
 
-	if node.Call != nil {
-		err := node.Call.Do(ctx, refs)
+	if node.Condition != nil && !Evaluate(node.Condition, refs) {
+		node.logger.WithFields(logrus.Fields{
+			"node":      node.Name,
+			"condition": node.Condition.Expression,
+		}).Debug("Condition not met, skipping node")
+
+		tracker.Skip(node)
+	} else if node.Call != nil && node.Async {
+		node.Dispatch(ctx, refs)
+	} else if node.Call != nil {
+		call, span := tracing.StartSpan(ctx, node.Name, tracing.KindInternal)
+		span.SetAttribute("node", node.Name)
+
+		recorder := journal.FromCtx(call)
+
+		err := recorder.Record(journal.NodeStarted, node.Name, nil)
+		if err == nil {
+			err = node.Run(call, refs)
+		}
+
+		if err == nil && recorder != nil {
+			err = recorder.Record(journal.NodeCompleted, node.Name, refs.Snapshot(node.Name, specs.JoinPath(node.Name, specs.ResourceHeader), node.Name+ExecutionResource))
+		}
+
 		if err != nil {
-			node.logger.WithField("node", node.Name).Error("Call failed")
+			err = transport.WrapError(err, node.Name)
+		}
+
+		span.SetError(err)
+		span.Finish()
+
+		if err != nil && node.Optional {
+			node.logger.WithFields(logrus.Fields{
+				"node": node.Name,
+				"err":  err,
+			}).Warn("Optional call failed, skipping node")
+
+			tracker.Skip(node)
+		} else if err != nil {
+			node.logger.WithFields(logrus.Fields{
+				"node": node.Name,
+				"err":  err,
+			}).Error("Call failed")
+
 			processes.Fatal(err)
 			return
 		}
 	}
 
 	node.logger.WithField("node", node.Name).Debug("Marking node as completed")
-
 	tracker.Mark(node)
-	tracker.Unlock(node)
 
 	if processes.Err() != nil {
 		node.logger.WithField("node", node.Name).Error("Stopping flow execution a error has been thrown")
@@ -115,6 +201,55 @@ func (node *Node) Do(ctx context.Context, tracker *Tracker, processes *Processes
 	}
 }
 
+// Execute calls the node call and retries failed attempts following the configured retry policy.
+// Attempts are no longer retried once the context is done or when the context deadline would be exceeded.
+func (node *Node) Execute(ctx context.Context, refs *refs.Store) error {
+	return node.Repeat(ctx, node.Retry, func() error {
+		return node.Attempt(ctx, refs)
+	})
+}
+
+// Repeat executes the given function and retries failed attempts following the given retry policy.
+// The function is executed once if no retry policy has been given.
+func (node *Node) Repeat(ctx context.Context, policy *specs.Retry, fn func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil {
+			return nil
+		}
+
+		if policy == nil || attempt >= policy.Attempts || ctx.Err() != nil || !Retryable(policy.Errors, err) {
+			return err
+		}
+
+		delay := Backoff(policy, attempt)
+
+		node.logger.WithFields(logrus.Fields{
+			"node":    node.Name,
+			"attempt": attempt,
+			"delay":   delay,
+			"err":     err,
+		}).Warn("Call failed, retrying")
+
+		if !Sleep(ctx, delay) {
+			node.logger.WithField("node", node.Name).Debug("Context done or deadline exceeded before the call could be retried")
+			return err
+		}
+	}
+}
+
+// Attempt calls the node call once.
+// The call is cancelled once the configured node timeout has been exceeded.
+func (node *Node) Attempt(ctx context.Context, refs *refs.Store) error {
+	if node.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, node.Timeout)
+		defer cancel()
+	}
+
+	return node.Call.Do(ctx, refs)
+}
+
 // Revert executes the given node rollback an calls the previous nodes.
 // If one of the nodes fails is the error marked but execution is not aborted.
 func (node *Node) Revert(ctx context.Context, tracker *Tracker, processes *Processes, refs *refs.Store) {
@@ -134,13 +269,22 @@ func (node *Node) Revert(ctx context.Context, tracker *Tracker, processes *Proce
 	}()
 
 	tracker.Lock(node)
+	defer tracker.Unlock(node)
+
 	if tracker.Met(node) {
 		node.logger.WithField("node", node.Name).Debug("Node already executed")
 		return
 	}
 
 	if node.Rollback != nil {
-		err := node.Rollback.Do(ctx, refs)
+		rollback, span := tracing.StartSpan(ctx, node.Name, tracing.KindInternal)
+		span.SetAttribute("node", node.Name)
+		span.SetAttribute("rollback", "true")
+
+		err := node.RunRollback(rollback, refs)
+		span.SetError(err)
+		span.Finish()
+
 		if err != nil {
 			processes.Fatal(err)
 			return
@@ -148,9 +292,66 @@ func (node *Node) Revert(ctx context.Context, tracker *Tracker, processes *Proce
 	}
 
 	node.logger.WithField("node", node.Name).Debug("Marking node as completed")
-
 	tracker.Mark(node)
-	tracker.Unlock(node)
+
+	err := journal.FromCtx(ctx).Record(journal.NodeReverted, node.Name, nil)
+	if err != nil {
+		node.logger.WithFields(logrus.Fields{
+			"node": node.Name,
+			"err":  err,
+		}).Error("Unable to record node revert")
+	}
+}
+
+// Undo executes the node rollback.
+// Failed rollbacks are retried following the configured rollback retry policy.
+// Rollbacks which keep failing are send to the dead letter sink found inside the given context.
+// Foreach nodes execute the rollback for each of the successful iterations.
+func (node *Node) Undo(ctx context.Context, store *refs.Store) error {
+	if node.Foreach == nil {
+		err := node.Compensate(ctx, store)
+		if err != nil {
+			node.Bury(ctx, err, store)
+		}
+
+		return err
+	}
+
+	ref := store.Load(node.Name, lookup.SelfRef)
+	if ref == nil {
+		return nil
+	}
+
+	return node.RevertIterations(ctx, store, ref.Repeated)
+}
+
+// Compensate executes the node rollback and retries failed attempts following the configured rollback retry policy
+func (node *Node) Compensate(ctx context.Context, store *refs.Store) error {
+	return node.Repeat(ctx, node.RollbackRetry, func() error {
+		return node.Rollback.Do(ctx, store)
+	})
+}
+
+// Bury sends the given failed rollback to the dead letter sink found inside the given context.
+// The values of the given stores are included inside the dead letter.
+func (node *Node) Bury(ctx context.Context, err error, stores ...*refs.Store) {
+	sender := deadletter.FromCtx(ctx)
+	if sender == nil {
+		return
+	}
+
+	node.logger.WithFields(logrus.Fields{
+		"node": node.Name,
+		"err":  err,
+	}).Warn("Sending failed rollback to the dead letter sink")
+
+	err = sender.Send(node.Name, err, stores...)
+	if err != nil {
+		node.logger.WithFields(logrus.Fields{
+			"node": node.Name,
+			"err":  err,
+		}).Error("Unable to send failed rollback to the dead letter sink")
+	}
 }
 
 // Walk iterates over all nodes and returns the lose ends nodes
@@ -2,7 +2,9 @@ package flow
 
 import (
 	"context"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/jexia/maestro/logger"
 	"github.com/jexia/maestro/refs"
@@ -25,6 +27,26 @@ func NewMockNode(name string, caller Call, rollback Call) *Node {
 	}
 }
 
+func TestNodeTimeout(t *testing.T) {
+	caller := &blocking{}
+	node := NewMockNode("first", caller, nil)
+	node.Timeout = 10 * time.Millisecond
+
+	tracker := NewTracker(1)
+	processes := NewProcesses(1)
+
+	node.Do(context.Background(), tracker, processes, refs.NewStore(0))
+	processes.Wait()
+
+	if !errors.Is(processes.Err(), context.DeadlineExceeded) {
+		t.Fatalf("unexpected err %v, expected %s", processes.Err(), context.DeadlineExceeded)
+	}
+
+	if tracker.Met(node) {
+		t.Fatal("timed out node has been marked as completed")
+	}
+}
+
 func BenchmarkSingleNodeCalling(b *testing.B) {
 	caller := &caller{}
 	node := NewMockNode("first", caller, nil)
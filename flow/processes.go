@@ -1,6 +1,7 @@
 package flow
 
 import (
+	"context"
 	"sync"
 )
 
@@ -15,9 +16,20 @@ func NewProcesses(delta int) *Processes {
 
 // Processes tracks processes
 type Processes struct {
-	err   error
-	wg    sync.WaitGroup
-	mutex sync.Mutex
+	err    error
+	wg     sync.WaitGroup
+	mutex  sync.Mutex
+	cancel context.CancelFunc
+}
+
+// Context returns a copy of the given context which is cancelled once a fatal error has been marked.
+// The returned context could be used to abort all processes that are still in progress.
+func (processes *Processes) Context(ctx context.Context) context.Context {
+	processes.mutex.Lock()
+	defer processes.mutex.Unlock()
+
+	ctx, processes.cancel = context.WithCancel(ctx)
+	return ctx
 }
 
 // Add adds delta, which may be negative, to the WaitGroup counter.
@@ -44,6 +56,16 @@ func (processes *Processes) Err() error {
 	return processes.err
 }
 
+// Cancel cancels the context returned by Context
+func (processes *Processes) Cancel() {
+	processes.mutex.Lock()
+	defer processes.mutex.Unlock()
+
+	if processes.cancel != nil {
+		processes.cancel()
+	}
+}
+
 // Fatal marks the given error and is returned on Err()
 func (processes *Processes) Fatal(err error) {
 	if err == nil {
@@ -58,4 +80,8 @@ func (processes *Processes) Fatal(err error) {
 	}
 
 	processes.err = err
+
+	if processes.cancel != nil {
+		processes.cancel()
+	}
 }
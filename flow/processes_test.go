@@ -1,8 +1,10 @@
 package flow
 
 import (
+	"context"
 	"errors"
 	"testing"
+	"time"
 )
 
 func TestProcesses(t *testing.T) {
@@ -18,3 +20,20 @@ func TestProcesses(t *testing.T) {
 	processes.Add(1)
 	processes.Done()
 }
+
+func TestProcessesContextCancel(t *testing.T) {
+	processes := NewProcesses(0)
+	ctx := processes.Context(context.Background())
+
+	if ctx.Err() != nil {
+		t.Fatalf("unexpected context err %s", ctx.Err())
+	}
+
+	processes.Fatal(errors.New("expected"))
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("context has not been cancelled after a fatal error")
+	}
+}
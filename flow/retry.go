@@ -0,0 +1,144 @@
+package flow
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"syscall"
+	"time"
+
+	"github.com/jexia/maestro/specs"
+	"github.com/jexia/maestro/transport"
+)
+
+// DefaultMaxDelay represents the max delay in between attempts when no max delay has been configured
+const DefaultMaxDelay = 5 * time.Minute
+
+// Backoff returns the delay to be awaited before the given attempt is retried.
+// The delay is capped at the configured max delay (or the default max delay) and optionally randomised when jitter is enabled.
+func Backoff(policy *specs.Retry, attempt int) time.Duration {
+	limit := policy.MaxDelay
+	if limit <= 0 {
+		limit = DefaultMaxDelay
+	}
+
+	delay := policy.Delay
+
+	switch policy.Backoff {
+	case specs.BackoffLinear:
+		if attempt > 1 && delay > limit/time.Duration(attempt) {
+			delay = limit
+			break
+		}
+
+		delay = policy.Delay * time.Duration(attempt)
+	case specs.BackoffExponential:
+		for index := 1; index < attempt; index++ {
+			// The delay is capped before doubling to prevent it from overflowing
+			if delay >= limit/2 {
+				delay = limit
+				break
+			}
+
+			delay *= 2
+		}
+	}
+
+	if delay > limit {
+		delay = limit
+	}
+
+	if policy.Jitter && delay > 1 {
+		half := delay / 2
+		delay = half + time.Duration(rand.Int63n(int64(half)))
+	}
+
+	return delay
+}
+
+// Retryable checks whether the given error matches one of the given retryable error classes
+func Retryable(classes []string, err error) bool {
+	if err == nil {
+		return false
+	}
+
+	for _, class := range classes {
+		switch class {
+		case specs.RetryAll:
+			return true
+		case specs.RetryTimeout:
+			if IsTimeout(err) {
+				return true
+			}
+		case specs.RetryConnection:
+			if IsConnection(err) {
+				return true
+			}
+		case specs.RetryUpstream:
+			if IsUpstream(err) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// IsTimeout checks whether the given error is caused by a exceeded deadline or timeout
+func IsTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var target net.Error
+	if errors.As(err, &target) {
+		return target.Timeout()
+	}
+
+	return false
+}
+
+// IsConnection checks whether the given error is caused by a failing connection
+func IsConnection(err error) bool {
+	if errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+
+	var target *net.OpError
+	if errors.As(err, &target) {
+		return !target.Timeout()
+	}
+
+	return false
+}
+
+// IsUpstream checks whether the given error is a upstream error which has been marked as retryable by the transport
+func IsUpstream(err error) bool {
+	var target *transport.Error
+	if errors.As(err, &target) {
+		return target.Code == transport.CodeUpstream && target.Retryable
+	}
+
+	return false
+}
+
+// Sleep blocks for the given delay or until the given context is done.
+// False is returned if the context is done or if the context deadline would be exceeded before the delay has passed.
+func Sleep(ctx context.Context, delay time.Duration) bool {
+	deadline, has := ctx.Deadline()
+	if has && time.Now().Add(delay).After(deadline) {
+		return false
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
@@ -0,0 +1,258 @@
+package flow
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jexia/maestro/refs"
+	"github.com/jexia/maestro/specs"
+	"github.com/jexia/maestro/transport"
+)
+
+type flaky struct {
+	Failures int
+	Counter  int
+	Err      error
+	mutex    sync.Mutex
+}
+
+func (caller *flaky) References() []*specs.Property {
+	return nil
+}
+
+func (caller *flaky) Do(context.Context, *refs.Store) error {
+	caller.mutex.Lock()
+	defer caller.mutex.Unlock()
+
+	caller.Counter++
+	if caller.Counter <= caller.Failures {
+		return caller.Err
+	}
+
+	return nil
+}
+
+func TestBackoff(t *testing.T) {
+	type test struct {
+		policy   *specs.Retry
+		attempt  int
+		expected time.Duration
+	}
+
+	tests := map[string]test{
+		"constant": {
+			policy:   &specs.Retry{Backoff: specs.BackoffConstant, Delay: time.Second},
+			attempt:  3,
+			expected: time.Second,
+		},
+		"linear": {
+			policy:   &specs.Retry{Backoff: specs.BackoffLinear, Delay: time.Second},
+			attempt:  3,
+			expected: 3 * time.Second,
+		},
+		"exponential": {
+			policy:   &specs.Retry{Backoff: specs.BackoffExponential, Delay: time.Second},
+			attempt:  3,
+			expected: 4 * time.Second,
+		},
+		"max delay": {
+			policy:   &specs.Retry{Backoff: specs.BackoffExponential, Delay: time.Second, MaxDelay: 5 * time.Second},
+			attempt:  64,
+			expected: 5 * time.Second,
+		},
+		"exponential overflow": {
+			policy:   &specs.Retry{Backoff: specs.BackoffExponential, Delay: time.Second},
+			attempt:  1000,
+			expected: DefaultMaxDelay,
+		},
+		"linear overflow": {
+			policy:   &specs.Retry{Backoff: specs.BackoffLinear, Delay: time.Hour},
+			attempt:  1 << 40,
+			expected: DefaultMaxDelay,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			result := Backoff(test.policy, test.attempt)
+			if result != test.expected {
+				t.Fatalf("unexpected delay %s, expected %s", result, test.expected)
+			}
+		})
+	}
+}
+
+func TestBackoffJitter(t *testing.T) {
+	policy := &specs.Retry{
+		Backoff: specs.BackoffConstant,
+		Delay:   time.Second,
+		Jitter:  true,
+	}
+
+	for index := 0; index < 100; index++ {
+		result := Backoff(policy, 1)
+		if result < policy.Delay/2 || result > policy.Delay {
+			t.Fatalf("unexpected delay %s, expected a delay between %s and %s", result, policy.Delay/2, policy.Delay)
+		}
+	}
+}
+
+func TestRetryable(t *testing.T) {
+	type test struct {
+		classes  []string
+		err      error
+		expected bool
+	}
+
+	tests := map[string]test{
+		"all": {
+			classes:  []string{specs.RetryAll},
+			err:      errors.New("unexpected err"),
+			expected: true,
+		},
+		"timeout": {
+			classes:  []string{specs.RetryTimeout},
+			err:      context.DeadlineExceeded,
+			expected: true,
+		},
+		"connection": {
+			classes:  []string{specs.RetryConnection},
+			err:      &net.OpError{Op: "dial", Err: errors.New("connection refused")},
+			expected: true,
+		},
+		"upstream": {
+			classes:  []string{specs.RetryUpstream},
+			err:      &transport.Error{Code: transport.CodeUpstream, Status: 503, Retryable: true},
+			expected: true,
+		},
+		"upstream not retryable": {
+			classes:  []string{specs.RetryUpstream},
+			err:      &transport.Error{Code: transport.CodeUpstream, Status: 500},
+			expected: false,
+		},
+		"unmatched": {
+			classes:  []string{specs.RetryTimeout, specs.RetryConnection},
+			err:      errors.New("unexpected err"),
+			expected: false,
+		},
+		"nil": {
+			classes:  []string{specs.RetryAll},
+			err:      nil,
+			expected: false,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			result := Retryable(test.classes, test.err)
+			if result != test.expected {
+				t.Fatalf("unexpected result %t, expected %t", result, test.expected)
+			}
+		})
+	}
+}
+
+func TestNodeRetry(t *testing.T) {
+	caller := &flaky{Failures: 2, Err: errors.New("unexpected err")}
+	node := NewMockNode("first", caller, nil)
+	node.Retry = &specs.Retry{
+		Attempts: 3,
+		Backoff:  specs.BackoffConstant,
+		Errors:   []string{specs.RetryAll},
+	}
+
+	tracker := NewTracker(1)
+	processes := NewProcesses(1)
+
+	node.Do(context.Background(), tracker, processes, refs.NewStore(0))
+	processes.Wait()
+
+	if processes.Err() != nil {
+		t.Fatal(processes.Err())
+	}
+
+	if caller.Counter != 3 {
+		t.Fatalf("unexpected counter total %d, expected %d", caller.Counter, 3)
+	}
+}
+
+func TestNodeRetryExhausted(t *testing.T) {
+	expected := errors.New("unexpected err")
+	caller := &flaky{Failures: 5, Err: expected}
+	node := NewMockNode("first", caller, nil)
+	node.Retry = &specs.Retry{
+		Attempts: 2,
+		Backoff:  specs.BackoffConstant,
+		Errors:   []string{specs.RetryAll},
+	}
+
+	tracker := NewTracker(1)
+	processes := NewProcesses(1)
+
+	node.Do(context.Background(), tracker, processes, refs.NewStore(0))
+	processes.Wait()
+
+	if !errors.Is(processes.Err(), expected) {
+		t.Fatalf("unexpected err %s, expected %s", processes.Err(), expected)
+	}
+
+	if caller.Counter != 2 {
+		t.Fatalf("unexpected counter total %d, expected %d", caller.Counter, 2)
+	}
+}
+
+func TestNodeRetryDeadline(t *testing.T) {
+	expected := errors.New("unexpected err")
+	caller := &flaky{Failures: 5, Err: expected}
+	node := NewMockNode("first", caller, nil)
+	node.Retry = &specs.Retry{
+		Attempts: 5,
+		Backoff:  specs.BackoffConstant,
+		Delay:    time.Hour,
+		Errors:   []string{specs.RetryAll},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	err := node.Execute(ctx, refs.NewStore(0))
+	if err != expected {
+		t.Fatalf("unexpected err %s, expected %s", err, expected)
+	}
+
+	if caller.Counter != 1 {
+		t.Fatalf("unexpected counter total %d, expected %d", caller.Counter, 1)
+	}
+}
+
+func TestNodeRollbackRetry(t *testing.T) {
+	rollback := &flaky{Failures: 2, Err: errors.New("unexpected err")}
+	node := NewMockNode("first", nil, rollback)
+	node.RollbackRetry = &specs.Retry{
+		Attempts: 3,
+		Backoff:  specs.BackoffConstant,
+		Errors:   []string{specs.RetryAll},
+	}
+
+	tracker := NewTracker(1)
+	processes := NewProcesses(1)
+
+	node.Revert(context.Background(), tracker, processes, refs.NewStore(0))
+	processes.Wait()
+
+	if processes.Err() != nil {
+		t.Fatal(processes.Err())
+	}
+
+	if rollback.Counter != 3 {
+		t.Fatalf("unexpected counter total %d, expected %d", rollback.Counter, 3)
+	}
+
+	if !tracker.Met(node) {
+		t.Fatal("reverted node has not been marked")
+	}
+}
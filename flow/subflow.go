@@ -0,0 +1,183 @@
+package flow
+
+import (
+	"context"
+
+	"github.com/jexia/maestro/logger"
+	"github.com/jexia/maestro/refs"
+	"github.com/jexia/maestro/specs"
+	"github.com/jexia/maestro/specs/trace"
+	"github.com/jexia/maestro/specs/types"
+	"github.com/sirupsen/logrus"
+)
+
+// NewFlowCall constructs a new flow caller which calls the given flow manager in-process.
+// The request properties are passed as input to the flow and the flow output is stored as the node response.
+func NewFlowCall(ctx context.Context, node *specs.Node, manager *Manager, request *specs.ParameterMap, output *specs.ParameterMap) Call {
+	return &FlowCaller{
+		ctx:      ctx,
+		node:     node,
+		manager:  manager,
+		request:  request,
+		response: output,
+	}
+}
+
+// FlowCaller represents a caller which calls a flow manager in-process
+type FlowCaller struct {
+	ctx      context.Context
+	node     *specs.Node
+	manager  *Manager
+	request  *specs.ParameterMap
+	response *specs.ParameterMap
+}
+
+// References returns the references inside the configured flow caller request
+func (caller *FlowCaller) References() []*specs.Property {
+	if caller.request == nil {
+		return make([]*specs.Property, 0)
+	}
+
+	result := make([]*specs.Property, 0, len(caller.request.Header)+1)
+	for _, header := range caller.request.Header {
+		result = append(result, header)
+	}
+
+	if caller.request.Property != nil {
+		result = append(result, caller.request.Property)
+	}
+
+	return result
+}
+
+// Do calls the configured flow with its own reference store.
+// The flow is reverted by the flow manager itself when one of its nodes fails.
+// The completed execution is kept inside the given store to be reverted by the flow rollback.
+func (caller *FlowCaller) Do(ctx context.Context, store *refs.Store) error {
+	logger.FromCtx(caller.ctx, logger.Flow).WithFields(logrus.Fields{
+		"node": caller.node.GetName(),
+		"flow": caller.manager.GetName(),
+	}).Debug("Calling flow")
+
+	child := caller.manager.NewStore()
+
+	if caller.request != nil && caller.request.Property != nil {
+		Transfer(specs.InputResource, caller.request.Property, store, child)
+	}
+
+	execution, err := caller.manager.Execute(ctx, child)
+	if err != nil {
+		return err
+	}
+
+	store.StoreValue(caller.resource(), ExecutionPath, execution)
+
+	if caller.response != nil && caller.response.Property != nil {
+		Transfer(caller.node.GetName(), caller.response.Property, child, store)
+	}
+
+	return nil
+}
+
+// Rollback constructs a new rollback reverting the flow executions completed by the given flow caller
+func (caller *FlowCaller) Rollback() Call {
+	return &FlowRollback{
+		caller: caller,
+	}
+}
+
+func (caller *FlowCaller) resource() string {
+	return caller.node.GetName() + ExecutionResource
+}
+
+// ExecutionResource represents the resource suffix under which completed flow executions are stored
+const ExecutionResource = ":flow"
+
+// ExecutionPath represents the path under which completed flow executions are stored
+const ExecutionPath = "execution"
+
+// FlowRollback represents a rollback which reverts the flow execution completed by a flow caller
+type FlowRollback struct {
+	caller *FlowCaller
+}
+
+// References returns the references inside the configured flow rollback
+func (rollback *FlowRollback) References() []*specs.Property {
+	return make([]*specs.Property, 0)
+}
+
+// Do reverts the flow execution stored inside the given store by the flow caller.
+// Executions restored from the journal could not be reverted, a error containing the execution id is returned
+// to send the rollback to the dead letter sink.
+func (rollback *FlowRollback) Do(ctx context.Context, store *refs.Store) error {
+	caller := rollback.caller
+
+	ref := store.Load(caller.resource(), ExecutionPath)
+	if ref == nil {
+		return nil
+	}
+
+	execution, is := ref.Value.(*Execution)
+	if !is || execution.Restored() {
+		logger.FromCtx(caller.ctx, logger.Flow).WithFields(logrus.Fields{
+			"node": caller.node.GetName(),
+			"flow": caller.manager.GetName(),
+		}).Error("Flow execution not available, unable to revert flow")
+
+		return trace.New(trace.WithMessage("unable to revert flow '%s' execution '%s' in '%s', the execution is not available", caller.manager.GetName(), ref.Value, caller.node.GetName()))
+	}
+
+	logger.FromCtx(caller.ctx, logger.Flow).WithFields(logrus.Fields{
+		"node": caller.node.GetName(),
+		"flow": caller.manager.GetName(),
+	}).Debug("Reverting flow")
+
+	return caller.manager.Undo(ctx, execution)
+}
+
+// Transfer stores the values of the given property, found inside the source store, inside the target store.
+// The values are stored under the given resource using the property path.
+func Transfer(resource string, property *specs.Property, source *refs.Store, target *refs.Store) {
+	if property.Label == types.LabelRepeated {
+		if property.Reference == nil {
+			return
+		}
+
+		ref := source.Load(property.Reference.Resource, property.Reference.Path)
+		if ref == nil {
+			return
+		}
+
+		result := refs.New(property.Path)
+		result.Repeating(len(ref.Repeated))
+
+		for index, item := range ref.Repeated {
+			store := refs.NewStore(len(property.Nested))
+
+			for _, nested := range property.Nested {
+				Transfer(resource, nested, item, store)
+			}
+
+			result.Set(index, store)
+		}
+
+		target.StoreReference(resource, result)
+		return
+	}
+
+	if property.Type == types.TypeMessage {
+		for _, nested := range property.Nested {
+			Transfer(resource, nested, source, target)
+		}
+
+		return
+	}
+
+	value := source.Value(property)
+
+	if value == nil {
+		return
+	}
+
+	target.StoreValue(resource, property.Path, value)
+}
@@ -0,0 +1,261 @@
+package flow
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jexia/maestro/logger"
+	"github.com/jexia/maestro/refs"
+	"github.com/jexia/maestro/specs"
+	"github.com/jexia/maestro/specs/types"
+)
+
+type echo struct {
+	Resource string
+	Err      error
+}
+
+func (caller *echo) References() []*specs.Property {
+	return nil
+}
+
+func (caller *echo) Do(ctx context.Context, store *refs.Store) error {
+	if caller.Err != nil {
+		return caller.Err
+	}
+
+	ref := store.Load(specs.InputResource, "message")
+	if ref == nil {
+		return errors.New("input message not set")
+	}
+
+	store.StoreValue(caller.Resource, "message", ref.Value)
+	return nil
+}
+
+func NewMockMessage(resource string, path string) *specs.ParameterMap {
+	return &specs.ParameterMap{
+		Property: &specs.Property{
+			Type:  types.TypeMessage,
+			Label: types.LabelOptional,
+			Nested: map[string]*specs.Property{
+				"message": {
+					Name:      "message",
+					Path:      "message",
+					Type:      types.TypeString,
+					Label:     types.LabelOptional,
+					Reference: &specs.PropertyReference{Resource: resource, Path: path},
+				},
+			},
+		},
+	}
+}
+
+func NewMockFlowCaller(err error) Call {
+	ctx := logger.WithValue(context.Background())
+	node := NewMockNode("opening", &echo{Resource: "opening", Err: err}, nil)
+	manager := NewManager(ctx, "greeter", []*Node{node})
+
+	return NewFlowCall(ctx, &specs.Node{Name: "greeting"}, manager, NewMockMessage(specs.InputResource, "greeting"), NewMockMessage("opening", "message"))
+}
+
+func TestFlowCall(t *testing.T) {
+	expected := "hello world"
+	caller := NewMockFlowCaller(nil)
+
+	store := refs.NewStore(1)
+	store.StoreValue(specs.InputResource, "greeting", expected)
+
+	err := caller.Do(context.Background(), store)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result := store.Load("greeting", "message")
+	if result == nil {
+		t.Fatal("flow output has not been stored")
+	}
+
+	if result.Value != expected {
+		t.Fatalf("unexpected result %s, expected %s", result.Value, expected)
+	}
+
+	if store.Load("opening", "message") != nil {
+		t.Fatal("flow references have been stored inside the parent store")
+	}
+}
+
+func TestFlowCallFailure(t *testing.T) {
+	expected := errors.New("unexpected err")
+	caller := NewMockFlowCaller(expected)
+
+	err := caller.Do(context.Background(), refs.NewStore(0))
+	if !errors.Is(err, expected) {
+		t.Fatalf("unexpected err %v, expected %s", err, expected)
+	}
+}
+
+func TestTransferRepeated(t *testing.T) {
+	source := NewMockItems("first", "second")
+	target := refs.NewStore(1)
+
+	property := &specs.Property{
+		Path:      "products",
+		Type:      types.TypeMessage,
+		Label:     types.LabelRepeated,
+		Reference: &specs.PropertyReference{Resource: "input", Path: "items"},
+		Nested: map[string]*specs.Property{
+			"id": {
+				Name:      "id",
+				Path:      "products.id",
+				Type:      types.TypeString,
+				Label:     types.LabelOptional,
+				Reference: &specs.PropertyReference{Resource: "input", Path: "items.id"},
+			},
+		},
+	}
+
+	Transfer("node", property, source, target)
+
+	result := target.Load("node", "products")
+	if result == nil {
+		t.Fatal("repeated reference has not been stored")
+	}
+
+	if len(result.Repeated) != 2 {
+		t.Fatalf("unexpected repeated length %d, expected %d", len(result.Repeated), 2)
+	}
+
+	item := result.Repeated[1].Load("node", "products.id")
+	if item == nil || item.Value != "second" {
+		t.Fatalf("unexpected repeated item %+v", item)
+	}
+}
+
+func TestFlowCallReferences(t *testing.T) {
+	caller := NewMockFlowCaller(nil)
+
+	references := refs.References{}
+	for _, property := range caller.References() {
+		references.MergeLeft(refs.PropertyReferences(property))
+	}
+
+	if len(references) != 1 || references["input:greeting"] == nil {
+		t.Fatalf("unexpected references %+v", references)
+	}
+}
+
+func TestFlowCallRollback(t *testing.T) {
+	ctx := logger.WithValue(context.Background())
+	rollback := &caller{}
+	node := NewMockNode("opening", &echo{Resource: "opening"}, rollback)
+	manager := NewManager(ctx, "greeter", []*Node{node})
+
+	call := NewFlowCall(ctx, &specs.Node{Name: "greeting"}, manager, NewMockMessage(specs.InputResource, "greeting"), NewMockMessage("opening", "message")).(*FlowCaller)
+
+	store := refs.NewStore(1)
+	store.StoreValue(specs.InputResource, "greeting", "hello world")
+
+	err := call.Do(context.Background(), store)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rollback.Counter != 0 {
+		t.Fatalf("unexpected rollback counter %d before the flow has been reverted", rollback.Counter)
+	}
+
+	err = call.Rollback().Do(context.Background(), store)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	manager.Wait()
+
+	if rollback.Counter != 1 {
+		t.Fatalf("unexpected rollback counter %d, expected %d", rollback.Counter, 1)
+	}
+}
+
+func TestFlowCallRollbackFailure(t *testing.T) {
+	ctx := logger.WithValue(context.Background())
+	expected := errors.New("unexpected err")
+	node := NewMockNode("opening", &echo{Resource: "opening"}, &caller{Err: expected})
+	manager := NewManager(ctx, "greeter", []*Node{node})
+
+	call := NewFlowCall(ctx, &specs.Node{Name: "greeting"}, manager, NewMockMessage(specs.InputResource, "greeting"), NewMockMessage("opening", "message")).(*FlowCaller)
+
+	store := refs.NewStore(1)
+	store.StoreValue(specs.InputResource, "greeting", "hello world")
+
+	err := call.Do(context.Background(), store)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = call.Rollback().Do(context.Background(), store)
+	if !errors.Is(err, expected) {
+		t.Fatalf("unexpected err %v, expected %s", err, expected)
+	}
+}
+
+func TestFlowCallRollbackUnavailable(t *testing.T) {
+	call := NewMockFlowCaller(nil).(*FlowCaller)
+
+	err := call.Rollback().Do(context.Background(), refs.NewStore(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFlowCallRollbackRestored(t *testing.T) {
+	ctx := logger.WithValue(context.Background())
+	rollback := &caller{}
+	node := NewMockNode("opening", &echo{Resource: "opening"}, rollback)
+	manager := NewManager(ctx, "greeter", []*Node{node}, WithJournal(&memory{}))
+
+	call := NewFlowCall(ctx, &specs.Node{Name: "greeting"}, manager, NewMockMessage(specs.InputResource, "greeting"), NewMockMessage("opening", "message")).(*FlowCaller)
+
+	store := refs.NewStore(1)
+	store.StoreValue(specs.InputResource, "greeting", "hello world")
+
+	err := call.Do(context.Background(), store)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	execution := store.Load(call.resource(), ExecutionPath).Value.(*Execution)
+
+	// The completed node values are journaled and restored after a crash
+	buffer := bytes.NewBuffer(nil)
+	err = gob.NewEncoder(buffer).Encode(store.Snapshot(call.resource()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	snapshots := []*refs.Snapshot{}
+	err = gob.NewDecoder(buffer).Decode(&snapshots)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	restored := refs.NewStore(1)
+	restored.Restore(snapshots)
+
+	err = call.Rollback().Do(context.Background(), restored)
+	if err == nil {
+		t.Fatal("expected a error to be returned for a restored execution")
+	}
+
+	if !strings.Contains(err.Error(), execution.ID) {
+		t.Fatalf("unexpected err %s, expected the execution id %s to be included", err, execution.ID)
+	}
+
+	if rollback.Counter != 0 {
+		t.Fatalf("unexpected rollback counter %d, expected %d", rollback.Counter, 0)
+	}
+}
@@ -8,6 +8,7 @@ import (
 func NewTracker(nodes int) *Tracker {
 	return &Tracker{
 		Nodes: make(map[string]struct{}, nodes),
+		Skips: make(map[string]struct{}),
 		Locks: make(map[*Node]*sync.Mutex, nodes),
 	}
 }
@@ -16,6 +17,7 @@ func NewTracker(nodes int) *Tracker {
 type Tracker struct {
 	mutex sync.Mutex
 	Nodes map[string]struct{}
+	Skips map[string]struct{}
 	Locks map[*Node]*sync.Mutex
 }
 
@@ -26,6 +28,21 @@ func (tracker *Tracker) Mark(node *Node) {
 	tracker.mutex.Unlock()
 }
 
+// Skip marks the given node as skipped, skipped nodes are ignored during rollbacks
+func (tracker *Tracker) Skip(node *Node) {
+	tracker.mutex.Lock()
+	tracker.Skips[node.Name] = struct{}{}
+	tracker.mutex.Unlock()
+}
+
+// Skipped checks whether the given node has been skipped
+func (tracker *Tracker) Skipped(node *Node) bool {
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+	_, has := tracker.Skips[node.Name]
+	return has
+}
+
 // Met checks whether the given nodes have been called
 func (tracker *Tracker) Met(nodes ...*Node) bool {
 	tracker.mutex.Lock()
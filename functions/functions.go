@@ -0,0 +1,295 @@
+package functions
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jexia/maestro/specs"
+	"github.com/jexia/maestro/specs/trace"
+	"github.com/jexia/maestro/specs/types"
+)
+
+// Default represents the built-in functions available inside templates
+var Default = specs.CustomDefinedFunctions{
+	"uuid":          UUID,
+	"now":           Now,
+	"sprintf":       Sprintf,
+	"upper":         StringFunction("upper", strings.ToUpper),
+	"lower":         StringFunction("lower", strings.ToLower),
+	"trim":          StringFunction("trim", strings.TrimSpace),
+	"base64_encode": StringFunction("base64_encode", Base64Encode),
+	"base64_decode": Base64Decode,
+	"sha256":        StringFunction("sha256", SHA256),
+	"hmac":          HMAC,
+	"len":           Len,
+	"concat":        Concat,
+	"int":           Int,
+	"string":        String,
+}
+
+// Result constructs the function result property of the given type
+func Result(path string, typed types.Type, fn specs.HandleCustomFunction) *specs.Property {
+	return &specs.Property{
+		Path:     path,
+		Type:     typed,
+		Label:    types.LabelOptional,
+		Function: fn,
+	}
+}
+
+// CheckArguments checks whether the given arguments match the expected argument types.
+// Arguments whose type is not known while parsing (ex: references) are checked once the argument types have been defined.
+func CheckArguments(name string, args []*specs.Property, expected ...types.Type) error {
+	if len(args) != len(expected) {
+		return trace.New(trace.WithMessage("invalid number of arguments passed to '%s', expected %d got %d", name, len(expected), len(args)))
+	}
+
+	for index, arg := range args {
+		if arg.Type == "" || arg.Type == expected[index] {
+			continue
+		}
+
+		return trace.New(trace.WithMessage("cannot use (%s) as argument %d of '%s', expected (%s)", arg.Type, index+1, name, expected[index]))
+	}
+
+	return nil
+}
+
+// StringFunction constructs a function accepting a single string argument and returning the result of the given handler
+func StringFunction(name string, handle func(string) string) specs.PrepareCustomFunction {
+	return func(path string, args ...*specs.Property) (*specs.Property, error) {
+		err := CheckArguments(name, args, types.TypeString)
+		if err != nil {
+			return nil, err
+		}
+
+		return Result(path, types.TypeString, func(values ...interface{}) interface{} {
+			value, is := values[0].(string)
+			if !is {
+				return nil
+			}
+
+			return handle(value)
+		}), nil
+	}
+}
+
+// UUID returns a random (version 4) UUID
+func UUID(path string, args ...*specs.Property) (*specs.Property, error) {
+	err := CheckArguments("uuid", args)
+	if err != nil {
+		return nil, err
+	}
+
+	return Result(path, types.TypeString, func(values ...interface{}) interface{} {
+		bb := make([]byte, 16)
+		_, err := rand.Read(bb)
+		if err != nil {
+			return nil
+		}
+
+		bb[6] = (bb[6] & 0x0f) | 0x40
+		bb[8] = (bb[8] & 0x3f) | 0x80
+
+		return fmt.Sprintf("%x-%x-%x-%x-%x", bb[0:4], bb[4:6], bb[6:8], bb[8:10], bb[10:])
+	}), nil
+}
+
+// Now returns the current unix time in seconds
+func Now(path string, args ...*specs.Property) (*specs.Property, error) {
+	err := CheckArguments("now", args)
+	if err != nil {
+		return nil, err
+	}
+
+	return Result(path, types.TypeInt64, func(values ...interface{}) interface{} {
+		return time.Now().Unix()
+	}), nil
+}
+
+// Sprintf formats the given arguments according to the given format
+func Sprintf(path string, args ...*specs.Property) (*specs.Property, error) {
+	if len(args) == 0 {
+		return nil, trace.New(trace.WithMessage("invalid number of arguments passed to 'sprintf', expected a format"))
+	}
+
+	err := CheckArguments("sprintf", args[:1], types.TypeString)
+	if err != nil {
+		return nil, err
+	}
+
+	return Result(path, types.TypeString, func(values ...interface{}) interface{} {
+		format, is := values[0].(string)
+		if !is {
+			return nil
+		}
+
+		return fmt.Sprintf(format, values[1:]...)
+	}), nil
+}
+
+// Base64Encode returns the standard base64 encoding of the given value
+func Base64Encode(value string) string {
+	return base64.StdEncoding.EncodeToString([]byte(value))
+}
+
+// Base64Decode decodes the given standard base64 encoded string.
+// A empty value is returned when the given string is not base64 encoded.
+func Base64Decode(path string, args ...*specs.Property) (*specs.Property, error) {
+	err := CheckArguments("base64_decode", args, types.TypeString)
+	if err != nil {
+		return nil, err
+	}
+
+	return Result(path, types.TypeString, func(values ...interface{}) interface{} {
+		value, is := values[0].(string)
+		if !is {
+			return nil
+		}
+
+		bb, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return nil
+		}
+
+		return string(bb)
+	}), nil
+}
+
+// SHA256 returns the hex encoded SHA256 hash of the given value
+func SHA256(value string) string {
+	hash := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(hash[:])
+}
+
+// HMAC returns the hex encoded HMAC-SHA256 of the given message using the given key
+func HMAC(path string, args ...*specs.Property) (*specs.Property, error) {
+	err := CheckArguments("hmac", args, types.TypeString, types.TypeString)
+	if err != nil {
+		return nil, err
+	}
+
+	return Result(path, types.TypeString, func(values ...interface{}) interface{} {
+		key, is := values[0].(string)
+		if !is {
+			return nil
+		}
+
+		message, is := values[1].(string)
+		if !is {
+			return nil
+		}
+
+		mac := hmac.New(sha256.New, []byte(key))
+		mac.Write([]byte(message))
+
+		return hex.EncodeToString(mac.Sum(nil))
+	}), nil
+}
+
+// Len returns the length of the given string or repeated value
+func Len(path string, args ...*specs.Property) (*specs.Property, error) {
+	if len(args) != 1 {
+		return nil, trace.New(trace.WithMessage("invalid number of arguments passed to 'len', expected %d got %d", 1, len(args)))
+	}
+
+	return Result(path, types.TypeInt64, func(values ...interface{}) interface{} {
+		if values[0] == nil {
+			return int64(0)
+		}
+
+		value := reflect.ValueOf(values[0])
+		switch value.Kind() {
+		case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+			return int64(value.Len())
+		}
+
+		return nil
+	}), nil
+}
+
+// Concat concatenates the given strings
+func Concat(path string, args ...*specs.Property) (*specs.Property, error) {
+	expected := make([]types.Type, len(args))
+	for index := range args {
+		expected[index] = types.TypeString
+	}
+
+	err := CheckArguments("concat", args, expected...)
+	if err != nil {
+		return nil, err
+	}
+
+	return Result(path, types.TypeString, func(values ...interface{}) interface{} {
+		result := strings.Builder{}
+
+		for _, value := range values {
+			str, is := value.(string)
+			if !is && value != nil {
+				return nil
+			}
+
+			result.WriteString(str)
+		}
+
+		return result.String()
+	}), nil
+}
+
+// Int casts the given string, number or boolean to a int64
+func Int(path string, args ...*specs.Property) (*specs.Property, error) {
+	if len(args) != 1 {
+		return nil, trace.New(trace.WithMessage("invalid number of arguments passed to 'int', expected %d got %d", 1, len(args)))
+	}
+
+	return Result(path, types.TypeInt64, func(values ...interface{}) interface{} {
+		switch value := values[0].(type) {
+		case string:
+			result, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+			if err != nil {
+				return nil
+			}
+
+			return result
+		case bool:
+			if value {
+				return int64(1)
+			}
+
+			return int64(0)
+		}
+
+		if integer, is := specs.Integer(values[0]); is {
+			return integer
+		}
+
+		if number, is := specs.Numeric(values[0]); is {
+			return int64(number)
+		}
+
+		return nil
+	}), nil
+}
+
+// String casts the given value to a string
+func String(path string, args ...*specs.Property) (*specs.Property, error) {
+	if len(args) != 1 {
+		return nil, trace.New(trace.WithMessage("invalid number of arguments passed to 'string', expected %d got %d", 1, len(args)))
+	}
+
+	return Result(path, types.TypeString, func(values ...interface{}) interface{} {
+		if values[0] == nil {
+			return nil
+		}
+
+		return fmt.Sprint(values[0])
+	}), nil
+}
@@ -0,0 +1,137 @@
+package functions
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/jexia/maestro/specs"
+	"github.com/jexia/maestro/specs/types"
+)
+
+func Constant(value interface{}, typed types.Type) *specs.Property {
+	return &specs.Property{
+		Type:    typed,
+		Default: value,
+	}
+}
+
+func Reference(path string) *specs.Property {
+	return &specs.Property{
+		Reference: &specs.PropertyReference{Resource: "input", Path: path},
+	}
+}
+
+func TestFunctions(t *testing.T) {
+	type test struct {
+		function string
+		args     []*specs.Property
+		values   []interface{}
+		typed    types.Type
+		expected interface{}
+	}
+
+	tests := map[string]test{
+		"sprintf":          {"sprintf", []*specs.Property{Constant("%s: %d", types.TypeString), Reference("name"), Reference("age")}, []interface{}{"%s: %d", "john", int32(42)}, types.TypeString, "john: 42"},
+		"upper":            {"upper", []*specs.Property{Reference("name")}, []interface{}{"john"}, types.TypeString, "JOHN"},
+		"lower":            {"lower", []*specs.Property{Reference("name")}, []interface{}{"JOHN"}, types.TypeString, "john"},
+		"trim":             {"trim", []*specs.Property{Reference("name")}, []interface{}{"  john "}, types.TypeString, "john"},
+		"upper unset":      {"upper", []*specs.Property{Reference("name")}, []interface{}{nil}, types.TypeString, nil},
+		"base64_encode":    {"base64_encode", []*specs.Property{Reference("name")}, []interface{}{"john"}, types.TypeString, "am9obg=="},
+		"base64_decode":    {"base64_decode", []*specs.Property{Reference("name")}, []interface{}{"am9obg=="}, types.TypeString, "john"},
+		"base64_invalid":   {"base64_decode", []*specs.Property{Reference("name")}, []interface{}{"%%%"}, types.TypeString, nil},
+		"sha256":           {"sha256", []*specs.Property{Reference("name")}, []interface{}{"john"}, types.TypeString, "96d9632f363564cc3032521409cf22a852f2032eec099ed5967c0d000cec607a"},
+		"hmac":             {"hmac", []*specs.Property{Constant("secret", types.TypeString), Reference("name")}, []interface{}{"secret", "john"}, types.TypeString, "337e3f715bf0aaed50eba983b89a1f9d1b8bded611f857a3c07644fff46bac0b"},
+		"len string":       {"len", []*specs.Property{Reference("name")}, []interface{}{"john"}, types.TypeInt64, int64(4)},
+		"len repeated":     {"len", []*specs.Property{Reference("items")}, []interface{}{[]interface{}{1, 2, 3}}, types.TypeInt64, int64(3)},
+		"len unset":        {"len", []*specs.Property{Reference("items")}, []interface{}{nil}, types.TypeInt64, int64(0)},
+		"concat":           {"concat", []*specs.Property{Reference("first"), Constant(" ", types.TypeString), Reference("last")}, []interface{}{"john", " ", "doe"}, types.TypeString, "john doe"},
+		"int string":       {"int", []*specs.Property{Reference("age")}, []interface{}{"42"}, types.TypeInt64, int64(42)},
+		"int float":        {"int", []*specs.Property{Reference("age")}, []interface{}{42.5}, types.TypeInt64, int64(42)},
+		"int bool":         {"int", []*specs.Property{Reference("verified")}, []interface{}{true}, types.TypeInt64, int64(1)},
+		"int invalid":      {"int", []*specs.Property{Reference("age")}, []interface{}{"john"}, types.TypeInt64, nil},
+		"string":           {"string", []*specs.Property{Reference("age")}, []interface{}{int32(42)}, types.TypeString, "42"},
+		"string unset":     {"string", []*specs.Property{Reference("age")}, []interface{}{nil}, types.TypeString, nil},
+		"concat not typed": {"concat", []*specs.Property{Reference("first"), Reference("age")}, []interface{}{"john", int32(42)}, types.TypeString, nil},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			property, err := Default[test.function]("message", test.args...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if property.Type != test.typed {
+				t.Fatalf("unexpected type %s, expected %s", property.Type, test.typed)
+			}
+
+			if property.Path != "message" {
+				t.Fatalf("unexpected path %s, expected %s", property.Path, "message")
+			}
+
+			result := property.Function(test.values...)
+			if result != test.expected {
+				t.Fatalf("unexpected result %v, expected %v", result, test.expected)
+			}
+		})
+	}
+}
+
+func TestUUID(t *testing.T) {
+	property, err := UUID("id")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pattern := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+	first := property.Function()
+	if !pattern.MatchString(first.(string)) {
+		t.Fatalf("unexpected uuid %s", first)
+	}
+
+	if first == property.Function() {
+		t.Fatal("unexpected duplicate uuid")
+	}
+}
+
+func TestNow(t *testing.T) {
+	property, err := Now("timestamp")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if property.Type != types.TypeInt64 {
+		t.Fatalf("unexpected type %s, expected %s", property.Type, types.TypeInt64)
+	}
+
+	result, is := property.Function().(int64)
+	if !is || result <= 0 {
+		t.Fatalf("unexpected timestamp %v", result)
+	}
+}
+
+func TestFunctionsFail(t *testing.T) {
+	tests := map[string][]*specs.Property{
+		"uuid":          {Reference("name")},
+		"now":           {Reference("name")},
+		"sprintf":       {},
+		"upper":         {Constant(int64(1), types.TypeInt64)},
+		"lower":         {Reference("first"), Reference("last")},
+		"base64_decode": {},
+		"hmac":          {Reference("key")},
+		"len":           {},
+		"concat":        {Reference("name"), Constant(true, types.TypeBool)},
+		"int":           {},
+		"string":        {Reference("first"), Reference("last")},
+	}
+
+	for name, args := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := Default[name]("message", args...)
+			if err == nil {
+				t.Fatal("expected function to fail")
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package graph
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// DOT writes the given graph as a Graphviz DOT digraph to the given writer.
+// Each flow is written as a separate cluster.
+func (graph *Graph) DOT(writer io.Writer) error {
+	_, err := fmt.Fprintln(writer, "digraph maestro {")
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(writer, "\trankdir=TB;")
+	fmt.Fprintln(writer, "\tnode [shape=box];")
+
+	for _, flow := range graph.Flows {
+		fmt.Fprintf(writer, "\tsubgraph %s {\n", strconv.Quote("cluster_"+flow.Name))
+		fmt.Fprintf(writer, "\t\tlabel=%s;\n", strconv.Quote(flow.Name))
+
+		for _, vertex := range flow.Vertices {
+			fmt.Fprintf(writer, "\t\t%s [label=%s%s];\n", strconv.Quote(vertex.ID), strconv.Quote(vertex.Text("\n")), DOTVertexStyle(vertex))
+		}
+
+		for _, edge := range flow.Edges {
+			fmt.Fprintf(writer, "\t\t%s -> %s%s;\n", strconv.Quote(edge.From), strconv.Quote(edge.To), DOTEdgeStyle(edge))
+		}
+
+		fmt.Fprintln(writer, "\t}")
+	}
+
+	_, err = fmt.Fprintln(writer, "}")
+	return err
+}
+
+// DOTVertexStyle returns the DOT attributes of the given vertex
+func DOTVertexStyle(vertex *Vertex) string {
+	if vertex.Rollback {
+		return ", style=dashed"
+	}
+
+	return ""
+}
+
+// DOTEdgeStyle returns the DOT attributes of the given edge
+func DOTEdgeStyle(edge *Edge) string {
+	switch edge.Kind {
+	case DependsOn:
+		return " [label=\"depends_on\", style=bold]"
+	case Rollback:
+		return " [label=\"rollback\", style=dashed]"
+	}
+
+	return ""
+}
@@ -0,0 +1,259 @@
+package graph
+
+import (
+	"context"
+	"sort"
+
+	"github.com/jexia/maestro/flow"
+	"github.com/jexia/maestro/refs"
+	"github.com/jexia/maestro/specs"
+	"github.com/jexia/maestro/specs/lookup"
+	"github.com/jexia/maestro/specs/trace"
+)
+
+// Kind represents the kind of a edge
+type Kind string
+
+// Available edge kinds
+const (
+	// Reference represents a implicit dependency created by referencing the resource
+	Reference Kind = "reference"
+	// DependsOn represents a explicit dependency defined inside depends_on
+	DependsOn Kind = "depends_on"
+	// Rollback represents the order in which rollbacks are executed
+	Rollback Kind = "rollback"
+)
+
+// RollbackSuffix is appended to the id of rollback vertices
+const RollbackSuffix = ".rollback"
+
+// Graph represents a collection of flow graphs
+type Graph struct {
+	Flows []*Flow
+}
+
+// Flow represents the resolved node graph of a single flow
+type Flow struct {
+	Name     string
+	Vertices []*Vertex
+	Edges    []*Edge
+}
+
+// Vertex represents a single resource or rollback inside a flow
+type Vertex struct {
+	ID       string
+	Name     string
+	Call     string
+	Rollback bool
+}
+
+// Edge represents a directed dependency between two vertices.
+// The target vertex is executed once the source vertex has been completed.
+type Edge struct {
+	From string
+	To   string
+	Kind Kind
+}
+
+// New constructs the graphs of the flows and proxies inside the given manifest.
+// Only the flows matching the given names are included when names are given.
+func New(ctx context.Context, manifest *specs.Manifest, names ...string) (*Graph, error) {
+	result := &Graph{}
+
+	if len(names) == 0 {
+		for _, flow := range manifest.Flows {
+			result.Flows = append(result.Flows, NewFlow(ctx, flow))
+		}
+
+		for _, proxy := range manifest.Proxy {
+			result.Flows = append(result.Flows, NewFlow(ctx, proxy))
+		}
+
+		return result, nil
+	}
+
+	for _, name := range names {
+		manager := manifest.GetFlow(name)
+		if manager == nil {
+			return nil, trace.New(trace.WithMessage("the flow %s was not found", name))
+		}
+
+		result.Flows = append(result.Flows, NewFlow(ctx, manager))
+	}
+
+	return result, nil
+}
+
+// NewFlow constructs the graph of the given flow manager.
+// The nodes and their branches are constructed the same way as they are while constructing a flow manager.
+// References inside rollback requests are drawn as rollback edges.
+func NewFlow(ctx context.Context, manager specs.FlowManager) *Flow {
+	definitions := manager.GetNodes()
+	nodes := make([]*flow.Node, len(definitions))
+	index := make(map[string]int, len(definitions))
+
+	result := &Flow{
+		Name: manager.GetName(),
+	}
+
+	for position, definition := range definitions {
+		node := flow.NewNode(ctx, definition, nil, nil)
+		nodes[position] = node
+		index[node.Name] = position
+
+		result.Vertices = append(result.Vertices, &Vertex{
+			ID:   VertexID(result.Name, node.Name),
+			Name: node.Name,
+			Call: Label(definition.Call),
+		})
+	}
+
+	flow.ConstructBranches(nodes)
+
+	for position, node := range nodes {
+		previous := make([]*flow.Node, len(node.Previous))
+		copy(previous, node.Previous)
+
+		sort.Slice(previous, func(i, j int) bool {
+			return index[previous[i].Name] < index[previous[j].Name]
+		})
+
+		for _, parent := range previous {
+			if parent == node {
+				continue
+			}
+
+			kind := Reference
+			if _, has := definitions[position].DependsOn[parent.Name]; has {
+				kind = DependsOn
+			}
+
+			result.Edges = append(result.Edges, &Edge{
+				From: VertexID(result.Name, parent.Name),
+				To:   VertexID(result.Name, node.Name),
+				Kind: kind,
+			})
+		}
+	}
+
+	for position, node := range nodes {
+		if definitions[position].Rollback == nil {
+			continue
+		}
+
+		result.Vertices = append(result.Vertices, &Vertex{
+			ID:       VertexID(result.Name, node.Name) + RollbackSuffix,
+			Name:     node.Name,
+			Call:     Label(definitions[position].Rollback),
+			Rollback: true,
+		})
+
+		followers := Rollbacks(node, definitions, index, make(map[string]bool))
+		sort.Slice(followers, func(i, j int) bool {
+			return index[followers[i]] < index[followers[j]]
+		})
+
+		for _, follower := range followers {
+			result.Edges = append(result.Edges, &Edge{
+				From: VertexID(result.Name, follower) + RollbackSuffix,
+				To:   VertexID(result.Name, node.Name) + RollbackSuffix,
+				Kind: Rollback,
+			})
+		}
+
+		for _, reference := range RollbackReferences(definitions[position].Rollback, index) {
+			result.Edges = append(result.Edges, &Edge{
+				From: VertexID(result.Name, reference),
+				To:   VertexID(result.Name, node.Name) + RollbackSuffix,
+				Kind: Rollback,
+			})
+		}
+	}
+
+	return result
+}
+
+// Rollbacks returns the names of the nearest next nodes defining a rollback.
+// The rollback of the given node is executed once the rollbacks of the returned nodes have been completed.
+func Rollbacks(node *flow.Node, definitions []*specs.Node, index map[string]int, visited map[string]bool) (result []string) {
+	for _, next := range node.Next {
+		if visited[next.Name] || next == node {
+			continue
+		}
+
+		visited[next.Name] = true
+
+		if definitions[index[next.Name]].Rollback != nil {
+			result = append(result, next.Name)
+			continue
+		}
+
+		result = append(result, Rollbacks(next, definitions, index, visited)...)
+	}
+
+	return result
+}
+
+// RollbackReferences returns the names of the nodes referenced inside the request of the given rollback.
+// Rollback references do not affect the execution order of the node calls.
+func RollbackReferences(rollback *specs.Call, index map[string]int) []string {
+	if rollback.GetRequest() == nil {
+		return nil
+	}
+
+	references := refs.ParameterReferences(rollback.GetRequest())
+	unique := make(map[string]struct{}, len(references))
+	result := make([]string, 0, len(references))
+
+	for _, reference := range references {
+		target, _ := lookup.ParseResource(reference.Resource)
+		if _, has := index[target]; !has {
+			continue
+		}
+
+		if _, has := unique[target]; has {
+			continue
+		}
+
+		unique[target] = struct{}{}
+		result = append(result, target)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return index[result[i]] < index[result[j]]
+	})
+
+	return result
+}
+
+// VertexID returns the vertex id of the given node inside the given flow
+func VertexID(flow string, node string) string {
+	return flow + "." + node
+}
+
+// Label returns the vertex label of the given call
+func Label(call *specs.Call) string {
+	if call == nil {
+		return ""
+	}
+
+	if call.GetService() == specs.FlowService {
+		return specs.FlowService + " " + call.GetMethod()
+	}
+
+	return call.GetService() + "." + call.GetMethod()
+}
+
+// Text returns the label text of the given vertex where each line is separated by the given separator
+func (vertex *Vertex) Text(separator string) string {
+	name := vertex.Name
+	if vertex.Rollback {
+		name = "rollback " + name
+	}
+
+	if vertex.Call == "" {
+		return name
+	}
+
+	return name + separator + vertex.Call
+}
@@ -0,0 +1,176 @@
+package graph
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/jexia/maestro/specs"
+)
+
+func NewRequest(references ...string) *specs.ParameterMap {
+	nested := make(map[string]*specs.Property, len(references))
+
+	for _, reference := range references {
+		nested[reference] = specs.ParseReference(reference, reference)
+	}
+
+	return &specs.ParameterMap{
+		Property: &specs.Property{
+			Nested: nested,
+		},
+	}
+}
+
+func NewMockManifest() *specs.Manifest {
+	user := &specs.Node{
+		Name:     "user",
+		Call:     &specs.Call{Service: "users", Method: "Get", Request: NewRequest("input:id")},
+		Rollback: &specs.Call{Service: "users", Method: "Unlock", Request: NewRequest("input:id")},
+	}
+
+	return &specs.Manifest{
+		Flows: specs.Flows{
+			&specs.Flow{
+				Name: "checkout",
+				Nodes: []*specs.Node{
+					user,
+					{
+						Name: "stock",
+						Call: &specs.Call{Service: "warehouse", Method: "Reserve", Request: NewRequest("user:id")},
+					},
+					{
+						Name:      "payment",
+						DependsOn: map[string]*specs.Node{"user": user},
+						Call:      &specs.Call{Service: "payments", Method: "Charge", Request: NewRequest("input:amount")},
+						Rollback:  &specs.Call{Service: "payments", Method: "Refund", Request: NewRequest("payment:id", "stock:id")},
+					},
+					{
+						Name:     "order",
+						Call:     &specs.Call{Service: specs.FlowService, Method: "orders", Request: NewRequest("stock:id", "payment:id")},
+						Rollback: &specs.Call{Service: "orders", Method: "Cancel", Request: NewRequest("order:id")},
+					},
+				},
+			},
+			&specs.Flow{
+				Name: "ping",
+			},
+		},
+	}
+}
+
+func TestNew(t *testing.T) {
+	result, err := New(context.Background(), NewMockManifest())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result.Flows) != 2 {
+		t.Fatalf("unexpected amount of flows %d, expected 2", len(result.Flows))
+	}
+
+	flow := result.Flows[0]
+	if len(flow.Vertices) != 7 {
+		t.Fatalf("unexpected amount of vertices %d, expected 7", len(flow.Vertices))
+	}
+
+	expected := []Edge{
+		{From: "checkout.user", To: "checkout.stock", Kind: Reference},
+		{From: "checkout.user", To: "checkout.payment", Kind: DependsOn},
+		{From: "checkout.stock", To: "checkout.order", Kind: Reference},
+		{From: "checkout.payment", To: "checkout.order", Kind: Reference},
+		{From: "checkout.payment.rollback", To: "checkout.user.rollback", Kind: Rollback},
+		{From: "checkout.order.rollback", To: "checkout.user.rollback", Kind: Rollback},
+		{From: "checkout.order.rollback", To: "checkout.payment.rollback", Kind: Rollback},
+		{From: "checkout.stock", To: "checkout.payment.rollback", Kind: Rollback},
+		{From: "checkout.payment", To: "checkout.payment.rollback", Kind: Rollback},
+		{From: "checkout.order", To: "checkout.order.rollback", Kind: Rollback},
+	}
+
+	if len(flow.Edges) != len(expected) {
+		t.Fatalf("unexpected amount of edges %d, expected %d", len(flow.Edges), len(expected))
+	}
+
+	for index, edge := range expected {
+		if *flow.Edges[index] != edge {
+			t.Errorf("unexpected edge %+v at %d, expected %+v", flow.Edges[index], index, edge)
+		}
+	}
+}
+
+func TestNewSelected(t *testing.T) {
+	result, err := New(context.Background(), NewMockManifest(), "ping")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result.Flows) != 1 || result.Flows[0].Name != "ping" {
+		t.Fatalf("unexpected flows %+v", result.Flows)
+	}
+}
+
+func TestNewUnknownFlow(t *testing.T) {
+	_, err := New(context.Background(), NewMockManifest(), "unknown")
+	if err == nil {
+		t.Fatal("unexpected pass")
+	}
+}
+
+func TestDOT(t *testing.T) {
+	result, err := New(context.Background(), NewMockManifest(), "checkout")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	writer := bytes.NewBuffer(nil)
+	err = result.DOT(writer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"digraph maestro {",
+		`subgraph "cluster_checkout" {`,
+		`"checkout.order" [label="order\nflow orders"];`,
+		`"checkout.user.rollback" [label="rollback user\nusers.Unlock", style=dashed];`,
+		`"checkout.user" -> "checkout.stock";`,
+		`"checkout.user" -> "checkout.payment" [label="depends_on", style=bold];`,
+		`"checkout.order.rollback" -> "checkout.payment.rollback" [label="rollback", style=dashed];`,
+	}
+
+	for _, line := range expected {
+		if !strings.Contains(writer.String(), line) {
+			t.Errorf("expected line %s inside:\n%s", line, writer.String())
+		}
+	}
+}
+
+func TestMermaid(t *testing.T) {
+	result, err := New(context.Background(), NewMockManifest(), "checkout")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	writer := bytes.NewBuffer(nil)
+	err = result.Mermaid(writer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"flowchart TD",
+		`subgraph checkout["checkout"]`,
+		`checkout_stock["stock<br/>warehouse.Reserve"]`,
+		`checkout_payment_rollback(["rollback payment<br/>payments.Refund"])`,
+		`checkout_user --> checkout_stock`,
+		`checkout_user ==>|depends_on| checkout_payment`,
+		`checkout_order_rollback -.->|rollback| checkout_user_rollback`,
+	}
+
+	for _, line := range expected {
+		if !strings.Contains(writer.String(), line) {
+			t.Errorf("expected line %s inside:\n%s", line, writer.String())
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package graph
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Mermaid writes the given graph as a Mermaid flowchart to the given writer.
+// Each flow is written as a separate subgraph.
+func (graph *Graph) Mermaid(writer io.Writer) error {
+	_, err := fmt.Fprintln(writer, "flowchart TD")
+	if err != nil {
+		return err
+	}
+
+	for _, flow := range graph.Flows {
+		fmt.Fprintf(writer, "\tsubgraph %s[\"%s\"]\n", MermaidID(flow.Name), MermaidEscape(flow.Name))
+
+		for _, vertex := range flow.Vertices {
+			open, close := "[", "]"
+			if vertex.Rollback {
+				open, close = "([", "])"
+			}
+
+			fmt.Fprintf(writer, "\t\t%s%s\"%s\"%s\n", MermaidID(vertex.ID), open, MermaidEscape(vertex.Text("<br/>")), close)
+		}
+
+		for _, edge := range flow.Edges {
+			fmt.Fprintf(writer, "\t\t%s %s %s\n", MermaidID(edge.From), MermaidArrow(edge), MermaidID(edge.To))
+		}
+
+		fmt.Fprintln(writer, "\tend")
+	}
+
+	return nil
+}
+
+// MermaidArrow returns the Mermaid arrow of the given edge
+func MermaidArrow(edge *Edge) string {
+	switch edge.Kind {
+	case DependsOn:
+		return "==>|depends_on|"
+	case Rollback:
+		return "-.->|rollback|"
+	}
+
+	return "-->"
+}
+
+// MermaidID returns a Mermaid safe identifier for the given id
+func MermaidID(id string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' {
+			return r
+		}
+
+		return '_'
+	}, id)
+}
+
+// MermaidEscape escapes the quotes inside the given label
+func MermaidEscape(label string) string {
+	return strings.ReplaceAll(label, "\"", "#quot;")
+}
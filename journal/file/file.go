@@ -0,0 +1,382 @@
+package file
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/jexia/maestro/journal"
+)
+
+// Default journal intervals
+const (
+	// DefaultSyncInterval represents the default interval in which written entries are synced
+	DefaultSyncInterval = 100 * time.Millisecond
+	// DefaultCompactInterval represents the default interval in which entries of completed executions are removed
+	DefaultCompactInterval = 5 * time.Minute
+)
+
+// Option represents a option which is applied to the journal on open
+type Option func(*Journal)
+
+// WithSyncInterval sets the interval in which written entries are synced to disk.
+// Every entry is synced before returning when a interval of zero is given.
+func WithSyncInterval(interval time.Duration) Option {
+	return func(journal *Journal) {
+		journal.SyncInterval = interval
+	}
+}
+
+// WithCompactInterval sets the interval in which the entries of completed executions are removed from the journal file.
+// The journal file is only compacted on open when a interval of zero is given.
+func WithCompactInterval(interval time.Duration) Option {
+	return func(journal *Journal) {
+		journal.CompactInterval = interval
+	}
+}
+
+// Open opens or creates the journal file at the given path.
+// The pending executions are read from the journal file, entries of completed executions are removed.
+// Incomplete trailing entries, written during a crash, are ignored.
+func Open(path string, options ...Option) (*Journal, error) {
+	entries, err := Read(path)
+	if err != nil {
+		return nil, err
+	}
+
+	pending := journal.Replay(entries)
+
+	err = Compact(path, entries, pending)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Journal{
+		path:            path,
+		file:            file,
+		writer:          bufio.NewWriter(file),
+		pending:         pending,
+		closing:         make(chan struct{}),
+		closed:          make(chan struct{}),
+		SyncInterval:    DefaultSyncInterval,
+		CompactInterval: DefaultCompactInterval,
+	}
+
+	for _, option := range options {
+		option(result)
+	}
+
+	go result.run()
+
+	return result, nil
+}
+
+// Journal represents a local file journal.
+// Entries are gob encoded and prefixed with their length.
+// Written entries are group committed, they are synced to disk in the configured sync interval.
+// Entries required to compensate executed nodes are synced before returning.
+type Journal struct {
+	path            string
+	file            *os.File
+	writer          *bufio.Writer
+	pending         []*journal.Execution
+	mutex           sync.Mutex
+	dirty           bool
+	completed       int
+	err             error
+	closing         chan struct{}
+	closed          chan struct{}
+	SyncInterval    time.Duration
+	CompactInterval time.Duration
+}
+
+// Append encodes and writes the given entry to the journal file.
+// The entry is synced together with the other written entries once the sync interval has passed.
+// Completed nodes and started rollbacks are synced, together with all previously written entries, before returning.
+// Errors which occurred while syncing or compacting in the background are returned on the next append.
+func (journal *Journal) Append(entry *journal.Entry) error {
+	bb, err := Encode(entry)
+	if err != nil {
+		return err
+	}
+
+	journal.mutex.Lock()
+	defer journal.mutex.Unlock()
+
+	if journal.err != nil {
+		err := journal.err
+		journal.err = nil
+		return err
+	}
+
+	_, err = journal.writer.Write(bb)
+	if err != nil {
+		return err
+	}
+
+	journal.dirty = true
+
+	if Completes(entry) {
+		journal.completed++
+	}
+
+	if Durable(entry) || journal.SyncInterval <= 0 {
+		return journal.sync()
+	}
+
+	return nil
+}
+
+// Pending returns the executions which were not completed when the journal was opened
+func (journal *Journal) Pending() ([]*journal.Execution, error) {
+	return journal.pending, nil
+}
+
+// Close syncs all written entries and closes the journal file
+func (journal *Journal) Close() error {
+	close(journal.closing)
+	<-journal.closed
+
+	journal.mutex.Lock()
+	defer journal.mutex.Unlock()
+
+	err := journal.sync()
+	if err != nil {
+		journal.file.Close()
+		return err
+	}
+
+	return journal.file.Close()
+}
+
+// run syncs the written entries and compacts the journal file in the configured intervals till the journal is closed
+func (journal *Journal) run() {
+	defer close(journal.closed)
+
+	var syncing, compacting <-chan time.Time
+
+	if journal.SyncInterval > 0 {
+		ticker := time.NewTicker(journal.SyncInterval)
+		defer ticker.Stop()
+		syncing = ticker.C
+	}
+
+	if journal.CompactInterval > 0 {
+		ticker := time.NewTicker(journal.CompactInterval)
+		defer ticker.Stop()
+		compacting = ticker.C
+	}
+
+	for {
+		select {
+		case <-journal.closing:
+			return
+		case <-syncing:
+			journal.mutex.Lock()
+			err := journal.sync()
+			if err != nil {
+				journal.err = err
+			}
+			journal.mutex.Unlock()
+		case <-compacting:
+			journal.mutex.Lock()
+			err := journal.compact()
+			if err != nil {
+				journal.err = err
+			}
+			journal.mutex.Unlock()
+		}
+	}
+}
+
+// sync flushes and syncs the written entries to disk.
+// The journal mutex should be locked when calling this method.
+func (journal *Journal) sync() error {
+	if !journal.dirty {
+		return nil
+	}
+
+	err := journal.writer.Flush()
+	if err != nil {
+		return err
+	}
+
+	err = journal.file.Sync()
+	if err != nil {
+		return err
+	}
+
+	journal.dirty = false
+	return nil
+}
+
+// compact removes the entries of the executions completed since the previous compaction from the journal file.
+// The journal mutex should be locked when calling this method.
+func (journal *Journal) compact() error {
+	if journal.completed == 0 {
+		return nil
+	}
+
+	err := journal.sync()
+	if err != nil {
+		return err
+	}
+
+	err = Rewrite(journal.path)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(journal.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+
+	journal.file.Close()
+	journal.file = file
+	journal.writer.Reset(file)
+	journal.completed = 0
+
+	return nil
+}
+
+// Durable checks whether the given entry has to be synced before returning.
+// Completed nodes are synced to ensure that their side effects could be compensated after a crash,
+// started rollbacks are synced to ensure that pending rollbacks could be resumed.
+func Durable(entry *journal.Entry) bool {
+	return entry.Event == journal.NodeCompleted || entry.Event == journal.RollbackStarted
+}
+
+// Completes checks whether the given entry completes a execution
+func Completes(entry *journal.Entry) bool {
+	return entry.Event == journal.Completed
+}
+
+// Rewrite reads the journal file at the given path and removes the entries of completed executions
+func Rewrite(path string) error {
+	entries, err := Read(path)
+	if err != nil {
+		return err
+	}
+
+	return Compact(path, entries, journal.Replay(entries))
+}
+
+// Encode encodes the given entry and prefixes it with its length
+func Encode(entry *journal.Entry) ([]byte, error) {
+	buffer := bytes.NewBuffer(make([]byte, 4))
+
+	err := gob.NewEncoder(buffer).Encode(entry)
+	if err != nil {
+		return nil, err
+	}
+
+	bb := buffer.Bytes()
+	binary.BigEndian.PutUint32(bb[:4], uint32(len(bb)-4))
+
+	return bb, nil
+}
+
+// Read reads all entries from the journal file at the given path.
+// No entries are returned if the given file does not exist.
+func Read(path string) ([]*journal.Entry, error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	result := make([]*journal.Entry, 0)
+	header := make([]byte, 4)
+
+	for {
+		_, err := io.ReadFull(reader, header)
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return result, nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		bb := make([]byte, binary.BigEndian.Uint32(header))
+		_, err = io.ReadFull(reader, bb)
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return result, nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		entry := &journal.Entry{}
+		err = gob.NewDecoder(bytes.NewReader(bb)).Decode(entry)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, entry)
+	}
+}
+
+// Compact rewrites the journal file at the given path containing only the entries of the given pending executions.
+// The file is replaced atomically once all entries have been written.
+func Compact(path string, entries []*journal.Entry, pending []*journal.Execution) error {
+	executions := make(map[string]struct{}, len(pending))
+	for _, execution := range pending {
+		executions[execution.ID] = struct{}{}
+	}
+
+	temp := path + ".tmp"
+	file, err := os.OpenFile(temp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if _, has := executions[entry.Execution]; !has {
+			continue
+		}
+
+		bb, err := Encode(entry)
+		if err != nil {
+			file.Close()
+			return err
+		}
+
+		_, err = file.Write(bb)
+		if err != nil {
+			file.Close()
+			return err
+		}
+	}
+
+	err = file.Sync()
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	err = file.Close()
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(temp, path)
+}
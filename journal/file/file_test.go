@@ -0,0 +1,303 @@
+package file
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jexia/maestro/journal"
+	"github.com/jexia/maestro/refs"
+)
+
+func NewMockPath(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "journal")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return filepath.Join(dir, "maestro.journal"), func() { os.RemoveAll(dir) }
+}
+
+func TestPending(t *testing.T) {
+	path, cleanup := NewMockPath(t)
+	defer cleanup()
+
+	file, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entries := []*journal.Entry{
+		{Execution: "first", Flow: "checkout", Event: journal.Started, Values: []*refs.Snapshot{{Resource: "input", Path: "id", Value: int64(42)}}},
+		{Execution: "first", Flow: "checkout", Event: journal.NodeCompleted, Node: "charge"},
+		{Execution: "second", Flow: "checkout", Event: journal.Started},
+		{Execution: "second", Flow: "checkout", Event: journal.Completed},
+	}
+
+	for _, entry := range entries {
+		err := file.Append(entry)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	file.Close()
+
+	file, err = Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer file.Close()
+
+	pending, err := file.Pending()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(pending) != 1 {
+		t.Fatalf("unexpected pending executions %d, expected 1", len(pending))
+	}
+
+	if _, has := pending[0].Completed["charge"]; !has {
+		t.Fatal("completed node has not been replayed")
+	}
+
+	if len(pending[0].Values) != 1 || pending[0].Values[0].Value != int64(42) {
+		t.Fatalf("unexpected values %+v", pending[0].Values)
+	}
+}
+
+func TestCompact(t *testing.T) {
+	path, cleanup := NewMockPath(t)
+	defer cleanup()
+
+	file, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	file.Append(&journal.Entry{Execution: "first", Event: journal.Started})
+	file.Append(&journal.Entry{Execution: "first", Event: journal.Completed})
+	file.Close()
+
+	file, err = Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	file.Close()
+
+	entries, err := Read(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entries) != 0 {
+		t.Fatalf("unexpected entries %d, expected completed executions to be removed", len(entries))
+	}
+}
+
+func TestTruncatedEntry(t *testing.T) {
+	path, cleanup := NewMockPath(t)
+	defer cleanup()
+
+	first, err := Encode(&journal.Entry{Execution: "first", Event: journal.Started})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	second, err := Encode(&journal.Entry{Execution: "second", Event: journal.Started})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bb := append(first, second[:len(second)-2]...)
+	err = ioutil.WriteFile(path, bb, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := Read(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entries) != 1 {
+		t.Fatalf("unexpected entries %d, expected 1", len(entries))
+	}
+}
+
+func TestGroupCommit(t *testing.T) {
+	path, cleanup := NewMockPath(t)
+	defer cleanup()
+
+	file, err := Open(path, WithSyncInterval(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer file.Close()
+
+	file.Append(&journal.Entry{Execution: "first", Event: journal.Started})
+	file.Append(&journal.Entry{Execution: "first", Event: journal.NodeStarted, Node: "charge"})
+
+	entries, err := Read(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entries) != 0 {
+		t.Fatalf("unexpected entries %d, expected entries to be batched", len(entries))
+	}
+
+	err = file.Append(&journal.Entry{Execution: "first", Event: journal.NodeCompleted, Node: "charge"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err = Read(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entries) != 3 {
+		t.Fatalf("unexpected entries %d, expected entries to be synced once the node completed", len(entries))
+	}
+
+	file.Append(&journal.Entry{Execution: "first", Event: journal.NodeStarted, Node: "ship"})
+
+	err = file.Append(&journal.Entry{Execution: "first", Event: journal.RollbackStarted})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err = Read(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entries) != 5 {
+		t.Fatalf("unexpected entries %d, expected entries to be synced before the rollback", len(entries))
+	}
+}
+
+func TestNodeCompletedCrash(t *testing.T) {
+	path, cleanup := NewMockPath(t)
+	defer cleanup()
+
+	crashed, err := Open(path, WithSyncInterval(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer crashed.Close()
+
+	crashed.Append(&journal.Entry{Execution: "first", Flow: "checkout", Event: journal.Started})
+
+	err = crashed.Append(&journal.Entry{Execution: "first", Flow: "checkout", Event: journal.NodeCompleted, Node: "charge", Values: []*refs.Snapshot{{Resource: "charge", Path: "id", Value: "ch_42"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The journal is reopened without closing the crashed journal
+	file, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer file.Close()
+
+	pending, err := file.Pending()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(pending) != 1 {
+		t.Fatalf("unexpected pending executions %d, expected 1", len(pending))
+	}
+
+	if _, has := pending[0].Completed["charge"]; !has {
+		t.Fatal("completed node has not been replayed")
+	}
+
+	if len(pending[0].Values) != 1 || pending[0].Values[0].Value != "ch_42" {
+		t.Fatalf("unexpected values %+v", pending[0].Values)
+	}
+}
+
+func TestSyncInterval(t *testing.T) {
+	path, cleanup := NewMockPath(t)
+	defer cleanup()
+
+	file, err := Open(path, WithSyncInterval(10*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer file.Close()
+
+	file.Append(&journal.Entry{Execution: "first", Event: journal.Started})
+
+	for attempt := 0; attempt < 100; attempt++ {
+		entries, err := Read(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(entries) == 1 {
+			return
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Fatal("entries have not been synced")
+}
+
+func TestCompactInterval(t *testing.T) {
+	path, cleanup := NewMockPath(t)
+	defer cleanup()
+
+	file, err := Open(path, WithSyncInterval(0), WithCompactInterval(10*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer file.Close()
+
+	file.Append(&journal.Entry{Execution: "first", Event: journal.Started})
+	file.Append(&journal.Entry{Execution: "first", Event: journal.Completed})
+	file.Append(&journal.Entry{Execution: "second", Event: journal.Started})
+
+	for attempt := 0; attempt < 100; attempt++ {
+		entries, err := Read(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(entries) == 1 {
+			err = file.Append(&journal.Entry{Execution: "second", Event: journal.NodeCompleted, Node: "charge"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			entries, err = Read(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(entries) != 2 {
+				t.Fatalf("unexpected entries %d after compaction, expected 2", len(entries))
+			}
+
+			return
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Fatal("completed executions have not been compacted")
+}
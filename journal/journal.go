@@ -0,0 +1,155 @@
+package journal
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/jexia/maestro/refs"
+)
+
+// Event represents a journal event type
+type Event string
+
+// Available journal events
+const (
+	// Started is recorded once a flow execution has been started
+	Started Event = "started"
+	// NodeStarted is recorded before a node call is executed
+	NodeStarted Event = "node.started"
+	// NodeCompleted is recorded once a node call has been completed
+	NodeCompleted Event = "node.completed"
+	// RollbackStarted is recorded once the rollback of a flow execution has been started
+	RollbackStarted Event = "rollback.started"
+	// NodeReverted is recorded once a node has been reverted
+	NodeReverted Event = "node.reverted"
+	// Completed is recorded once a flow execution or its rollback has been completed
+	Completed Event = "completed"
+)
+
+// Entry represents a single journal record
+type Entry struct {
+	Execution string
+	Flow      string
+	Event     Event
+	Node      string
+	Values    []*refs.Snapshot
+}
+
+// Execution represents the journaled state of a flow execution which has not been completed
+type Execution struct {
+	ID        string
+	Flow      string
+	Completed map[string]struct{}
+	Reverted  map[string]struct{}
+	Values    []*refs.Snapshot
+}
+
+// Journal records the progress of flow executions.
+// Pending executions could be reverted after a crash using the recorded nodes and reference values.
+type Journal interface {
+	// Append durably records the given entry
+	Append(entry *Entry) error
+	// Pending returns all executions which have not been completed
+	Pending() ([]*Execution, error)
+	// Close closes the journal
+	Close() error
+}
+
+// Replay replays the given entries and returns the executions which have not been completed.
+// Executions are returned in the order they have been started.
+func Replay(entries []*Entry) []*Execution {
+	executions := make(map[string]*Execution)
+	order := make([]string, 0)
+
+	for _, entry := range entries {
+		execution, has := executions[entry.Execution]
+		if !has {
+			execution = &Execution{
+				ID:        entry.Execution,
+				Flow:      entry.Flow,
+				Completed: make(map[string]struct{}),
+				Reverted:  make(map[string]struct{}),
+			}
+
+			executions[entry.Execution] = execution
+			order = append(order, entry.Execution)
+		}
+
+		execution.Values = append(execution.Values, entry.Values...)
+
+		switch entry.Event {
+		case NodeCompleted:
+			execution.Completed[entry.Node] = struct{}{}
+		case NodeReverted:
+			execution.Reverted[entry.Node] = struct{}{}
+		case Completed:
+			delete(executions, entry.Execution)
+		}
+	}
+
+	result := make([]*Execution, 0, len(executions))
+	for _, id := range order {
+		execution, has := executions[id]
+		if !has {
+			continue
+		}
+
+		result = append(result, execution)
+	}
+
+	return result
+}
+
+// NewID generates a new unique execution id
+func NewID() string {
+	bb := make([]byte, 16)
+	rand.Read(bb)
+	return hex.EncodeToString(bb)
+}
+
+// NewRecorder constructs a new recorder for the given flow execution
+func NewRecorder(journal Journal, flow string, execution string) *Recorder {
+	return &Recorder{
+		journal:   journal,
+		Flow:      flow,
+		Execution: execution,
+	}
+}
+
+// Recorder records the events of a single flow execution.
+// All methods are no-ops when called on a nil recorder.
+type Recorder struct {
+	journal   Journal
+	Flow      string
+	Execution string
+}
+
+// Record appends a new entry for the given event to the journal
+func (recorder *Recorder) Record(event Event, node string, values []*refs.Snapshot) error {
+	if recorder == nil {
+		return nil
+	}
+
+	return recorder.journal.Append(&Entry{
+		Execution: recorder.Execution,
+		Flow:      recorder.Flow,
+		Event:     event,
+		Node:      node,
+		Values:    values,
+	})
+}
+
+type recorderKey struct{}
+
+// WithRecorder returns a copy of the given context containing the given recorder
+func WithRecorder(ctx context.Context, recorder *Recorder) context.Context {
+	return context.WithValue(ctx, recorderKey{}, recorder)
+}
+
+// FromCtx returns the recorder stored inside the given context.
+// Nil is returned when no recorder has been stored.
+func FromCtx(ctx context.Context) *Recorder {
+	recorder, _ := ctx.Value(recorderKey{}).(*Recorder)
+	return recorder
+}
@@ -0,0 +1,109 @@
+package journal
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jexia/maestro/refs"
+)
+
+type memory struct {
+	entries []*Entry
+}
+
+func (journal *memory) Append(entry *Entry) error {
+	journal.entries = append(journal.entries, entry)
+	return nil
+}
+
+func (journal *memory) Pending() ([]*Execution, error) {
+	return Replay(journal.entries), nil
+}
+
+func (journal *memory) Close() error {
+	return nil
+}
+
+func TestReplay(t *testing.T) {
+	entries := []*Entry{
+		{Execution: "first", Flow: "checkout", Event: Started, Values: []*refs.Snapshot{{Resource: "input", Path: "id", Value: "1"}}},
+		{Execution: "second", Flow: "checkout", Event: Started},
+		{Execution: "first", Flow: "checkout", Event: NodeStarted, Node: "charge"},
+		{Execution: "first", Flow: "checkout", Event: NodeCompleted, Node: "charge", Values: []*refs.Snapshot{{Resource: "charge", Path: "id", Value: "2"}}},
+		{Execution: "first", Flow: "checkout", Event: NodeStarted, Node: "ship"},
+		{Execution: "first", Flow: "checkout", Event: NodeCompleted, Node: "ship"},
+		{Execution: "first", Flow: "checkout", Event: RollbackStarted},
+		{Execution: "first", Flow: "checkout", Event: NodeReverted, Node: "ship"},
+		{Execution: "second", Flow: "checkout", Event: Completed},
+	}
+
+	pending := Replay(entries)
+	if len(pending) != 1 {
+		t.Fatalf("unexpected pending executions %d, expected 1", len(pending))
+	}
+
+	execution := pending[0]
+	if execution.ID != "first" || execution.Flow != "checkout" {
+		t.Fatalf("unexpected execution %s of flow %s", execution.ID, execution.Flow)
+	}
+
+	if len(execution.Completed) != 2 {
+		t.Fatalf("unexpected completed nodes %d, expected 2", len(execution.Completed))
+	}
+
+	if _, has := execution.Reverted["ship"]; !has || len(execution.Reverted) != 1 {
+		t.Fatal("unexpected reverted nodes")
+	}
+
+	if len(execution.Values) != 2 {
+		t.Fatalf("unexpected values %d, expected 2", len(execution.Values))
+	}
+}
+
+func TestNilRecorder(t *testing.T) {
+	var recorder *Recorder
+
+	err := recorder.Record(Started, "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	journal := &memory{}
+	recorder := NewRecorder(journal, "checkout", NewID())
+
+	err := recorder.Record(NodeCompleted, "charge", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(journal.entries) != 1 {
+		t.Fatalf("unexpected entries %d, expected 1", len(journal.entries))
+	}
+
+	entry := journal.entries[0]
+	if entry.Execution != recorder.Execution || entry.Flow != "checkout" || entry.Node != "charge" {
+		t.Fatalf("unexpected entry %+v", entry)
+	}
+}
+
+func TestRecorderFromCtx(t *testing.T) {
+	ctx := context.Background()
+	if FromCtx(ctx) != nil {
+		t.Fatal("unexpected recorder inside empty context")
+	}
+
+	recorder := NewRecorder(&memory{}, "checkout", NewID())
+	ctx = WithRecorder(ctx, recorder)
+
+	if FromCtx(ctx) != recorder {
+		t.Fatal("unexpected recorder returned from context")
+	}
+}
+
+func TestNewID(t *testing.T) {
+	if NewID() == NewID() {
+		t.Fatal("generated ids are not unique")
+	}
+}
@@ -78,3 +78,15 @@ func FromCtx(ctx context.Context, module Module) *logrus.Logger {
 
 	return logger
 }
+
+// AddHook adds the given hook to the loggers of all modules
+func AddHook(ctx context.Context, hook logrus.Hook) {
+	for _, module := range Modules {
+		logger := FromCtx(ctx, module)
+		if logger == nil {
+			continue
+		}
+
+		logger.AddHook(hook)
+	}
+}
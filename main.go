@@ -16,6 +16,7 @@ type Client struct {
 	Endpoints []*transport.Endpoint
 	Manifest  *specs.Manifest
 	Listeners []transport.Listener
+	Managers  constructor.Managers
 	Options   constructor.Options
 }
 
@@ -24,6 +25,17 @@ func (client *Client) Serve() (result error) {
 	wg := sync.WaitGroup{}
 	wg.Add(len(client.Listeners))
 
+	if client.Options.Metrics != nil {
+		logger.FromCtx(client.ctx, logger.Core).Info("serving metrics")
+
+		go func() {
+			err := client.Options.Metrics.Serve()
+			if err != nil {
+				logger.FromCtx(client.ctx, logger.Core).WithField("err", err).Error("unable to serve metrics")
+			}
+		}()
+	}
+
 	for _, listener := range client.Listeners {
 		logger.FromCtx(client.ctx, logger.Core).WithField("listener", listener.Name()).Info("serving listener")
 
@@ -40,15 +52,30 @@ func (client *Client) Serve() (result error) {
 	return result
 }
 
-// Close gracefully closes the given client
+// Close gracefully closes the given client.
+// The journal is closed once all flow managers have completed their calls and rollbacks.
 func (client *Client) Close() {
 	for _, listener := range client.Listeners {
 		listener.Close()
 	}
 
-	for _, endpoint := range client.Endpoints {
-		endpoint.Flow.Wait()
+	for _, manager := range client.Managers {
+		manager.Wait()
+	}
+
+	if client.Options.Journal != nil {
+		client.Options.Journal.Close()
+	}
+
+	if client.Options.DeadLetter != nil {
+		client.Options.DeadLetter.Close()
 	}
+
+	if client.Options.Metrics != nil {
+		client.Options.Metrics.Close()
+	}
+
+	client.Options.Tracer.Close()
 }
 
 // New constructs a new Maestro instance
@@ -63,7 +90,7 @@ func New(opts ...constructor.Option) (*Client, error) {
 		return nil, err
 	}
 
-	endpoints, err := constructor.FlowManager(ctx, manifest, options)
+	endpoints, managers, err := constructor.FlowManager(ctx, manifest, options)
 	if err != nil {
 		return nil, err
 	}
@@ -73,6 +100,7 @@ func New(opts ...constructor.Option) (*Client, error) {
 		Endpoints: endpoints,
 		Manifest:  manifest,
 		Listeners: options.Listeners,
+		Managers:  managers,
 		Options:   options,
 	}
 
@@ -27,14 +27,7 @@ func (manager *Manager) Marshal(store *refs.Store) MD {
 
 	result := make(MD, len(manager.Params.Header))
 	for key, property := range manager.Params.Header {
-		value := property.Default
-
-		if property.Reference != nil {
-			ref := store.Load(property.Reference.Resource, property.Reference.Path)
-			if ref != nil {
-				value = ref.Value
-			}
-		}
+		value := store.Value(property)
 
 		if value == nil {
 			continue
@@ -0,0 +1,47 @@
+package metadata
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jexia/maestro/logger"
+	"github.com/jexia/maestro/refs"
+	"github.com/jexia/maestro/specs"
+)
+
+func TestManagerMarshal(t *testing.T) {
+	ctx := logger.WithValue(context.Background())
+
+	authorization, err := specs.ParseTemplate(ctx, "Authorization", nil, "Bearer {{ input.header:token }}")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	params := &specs.ParameterMap{
+		Header: specs.Header{
+			"Authorization": authorization,
+			"Accept":        {Path: "Accept", Default: "application/json"},
+			"Empty":         {Path: "Empty", Reference: &specs.PropertyReference{Resource: "input.header", Path: "empty"}},
+		},
+	}
+
+	store := refs.NewStore(1)
+	store.StoreValue("input.header", "token", "secret")
+
+	result := NewManager("caller", params).Marshal(store)
+
+	expected := MD{
+		"Authorization": "Bearer secret",
+		"Accept":        "application/json",
+	}
+
+	if len(result) != len(expected) {
+		t.Fatalf("unexpected metadata %+v, expected %+v", result, expected)
+	}
+
+	for key, value := range expected {
+		if result[key] != value {
+			t.Errorf("unexpected header %s value '%s', expected '%s'", key, result[key], value)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/jexia/maestro/flow"
+	"github.com/jexia/maestro/refs"
+	"github.com/jexia/maestro/transport"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Namespace represents the namespace of all collected metrics
+const Namespace = "maestro"
+
+// CodeOK represents the code of a successful execution
+const CodeOK = "ok"
+
+// Code returns the code label of the given error.
+// The error code is returned for failed executions.
+func Code(err error) string {
+	if err == nil {
+		return CodeOK
+	}
+
+	return transport.AsError(err).Code
+}
+
+// New constructs a new metrics collector exposing the collected metrics on the given address.
+// The metrics are exposed on the /metrics path. No server is started if the given address is empty.
+func New(addr string) *Metrics {
+	registry := prometheus.NewRegistry()
+	metrics := &Metrics{
+		Registry: registry,
+		FlowExecutions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "flow_executions_total",
+			Help:      "Total amount of flow executions",
+		}, []string{"flow", "code"}),
+		FlowDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Name:      "flow_duration_seconds",
+			Help:      "Duration of flow executions",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"flow"}),
+		NodeExecutions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "node_executions_total",
+			Help:      "Total amount of node executions",
+		}, []string{"flow", "node", "code"}),
+		NodeDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Name:      "node_duration_seconds",
+			Help:      "Duration of node executions",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"flow", "node"}),
+		Rollbacks: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "rollbacks_total",
+			Help:      "Total amount of triggered node rollbacks",
+		}, []string{"flow", "node"}),
+		RollbacksFailed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "rollbacks_failed_total",
+			Help:      "Total amount of failed node rollbacks",
+		}, []string{"flow", "node"}),
+		Calls: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "transport_calls_total",
+			Help:      "Total amount of service calls",
+		}, []string{"service", "method", "code"}),
+		CallDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Name:      "transport_call_duration_seconds",
+			Help:      "Duration of service calls",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"service", "method"}),
+		Requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "listener_requests_total",
+			Help:      "Total amount of requests handled by listeners",
+		}, []string{"listener", "endpoint", "code"}),
+		RequestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Name:      "listener_request_duration_seconds",
+			Help:      "Duration of requests handled by listeners",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"listener", "endpoint"}),
+	}
+
+	registry.MustRegister(
+		metrics.FlowExecutions,
+		metrics.FlowDuration,
+		metrics.NodeExecutions,
+		metrics.NodeDuration,
+		metrics.Rollbacks,
+		metrics.RollbacksFailed,
+		metrics.Calls,
+		metrics.CallDuration,
+		metrics.Requests,
+		metrics.RequestDuration,
+	)
+
+	if addr != "" {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", metrics.Handler())
+
+		metrics.server = &http.Server{
+			Addr:    addr,
+			Handler: mux,
+		}
+	}
+
+	return metrics
+}
+
+// Metrics represents the Prometheus metrics collected from flows, nodes, rollbacks, transport calls and listener requests.
+// Flow, node and rollback metrics are collected through flow hooks.
+type Metrics struct {
+	flow.NopHooks
+	Registry        *prometheus.Registry
+	FlowExecutions  *prometheus.CounterVec
+	FlowDuration    *prometheus.HistogramVec
+	NodeExecutions  *prometheus.CounterVec
+	NodeDuration    *prometheus.HistogramVec
+	Rollbacks       *prometheus.CounterVec
+	RollbacksFailed *prometheus.CounterVec
+	Calls           *prometheus.CounterVec
+	CallDuration    *prometheus.HistogramVec
+	Requests        *prometheus.CounterVec
+	RequestDuration *prometheus.HistogramVec
+	server          *http.Server
+}
+
+// Handler returns a HTTP handler exposing the collected metrics
+func (metrics *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(metrics.Registry, promhttp.HandlerOpts{})
+}
+
+// Serve opens the metrics server on the configured address.
+// Nil is returned once the server has been closed or if no address has been configured.
+func (metrics *Metrics) Serve() error {
+	if metrics.server == nil {
+		return nil
+	}
+
+	err := metrics.server.ListenAndServe()
+	if err == http.ErrServerClosed {
+		return nil
+	}
+
+	return err
+}
+
+// Close closes the metrics server
+func (metrics *Metrics) Close() error {
+	if metrics.server == nil {
+		return nil
+	}
+
+	return metrics.server.Close()
+}
+
+// AfterFlow collects the flow execution metrics
+func (metrics *Metrics) AfterFlow(ctx context.Context, flow string, duration time.Duration, err error, store *refs.Store) {
+	metrics.FlowExecutions.WithLabelValues(flow, Code(err)).Inc()
+	metrics.FlowDuration.WithLabelValues(flow).Observe(duration.Seconds())
+}
+
+// AfterNode collects the node execution metrics
+func (metrics *Metrics) AfterNode(ctx context.Context, flow string, node string, duration time.Duration, err error, store *refs.Store) {
+	metrics.NodeExecutions.WithLabelValues(flow, node, Code(err)).Inc()
+	metrics.NodeDuration.WithLabelValues(flow, node).Observe(duration.Seconds())
+}
+
+// AfterRollback collects the node rollback metrics
+func (metrics *Metrics) AfterRollback(ctx context.Context, flow string, node string, duration time.Duration, err error, store *refs.Store) {
+	metrics.Rollbacks.WithLabelValues(flow, node).Inc()
+
+	if err != nil {
+		metrics.RollbacksFailed.WithLabelValues(flow, node).Inc()
+	}
+}
@@ -0,0 +1,210 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jexia/maestro/refs"
+	"github.com/jexia/maestro/specs"
+	"github.com/jexia/maestro/transport"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+type method struct {
+	name string
+}
+
+func (method *method) GetName() string {
+	return method.name
+}
+
+func (method *method) References() []*specs.Property {
+	return nil
+}
+
+type call struct {
+	err error
+}
+
+func (call *call) SendMsg(ctx context.Context, writer transport.ResponseWriter, request *transport.Request, refs *refs.Store) error {
+	return call.err
+}
+
+func (call *call) GetMethods() []transport.Method {
+	return nil
+}
+
+func (call *call) GetMethod(name string) transport.Method {
+	return nil
+}
+
+func (call *call) Close() error {
+	return nil
+}
+
+type manager struct {
+	err error
+}
+
+func (manager *manager) NewStore() *refs.Store {
+	return refs.NewStore(0)
+}
+
+func (manager *manager) GetName() string {
+	return "echo"
+}
+
+func (manager *manager) Call(ctx context.Context, refs *refs.Store) error {
+	return manager.err
+}
+
+func (manager *manager) Wait() {}
+
+func TestCode(t *testing.T) {
+	tests := map[error]string{
+		nil:                      CodeOK,
+		context.DeadlineExceeded: transport.CodeTimeout,
+		&transport.Error{Code: transport.CodeUpstream}: transport.CodeUpstream,
+	}
+
+	for input, expected := range tests {
+		result := Code(input)
+		if result != expected {
+			t.Errorf("unexpected code %s, expected %s", result, expected)
+		}
+	}
+}
+
+func TestHooks(t *testing.T) {
+	ctx := context.Background()
+	metrics := New("")
+	failed := errors.New("unexpected error")
+
+	metrics.AfterFlow(ctx, "echo", time.Second, nil, nil)
+	metrics.AfterFlow(ctx, "echo", time.Second, failed, nil)
+	metrics.AfterNode(ctx, "echo", "first", time.Second, nil, nil)
+	metrics.AfterRollback(ctx, "echo", "first", time.Second, nil, nil)
+	metrics.AfterRollback(ctx, "echo", "first", time.Second, failed, nil)
+
+	tests := map[string]float64{
+		"flow success": testutil.ToFloat64(metrics.FlowExecutions.WithLabelValues("echo", CodeOK)),
+		"flow failed":  testutil.ToFloat64(metrics.FlowExecutions.WithLabelValues("echo", transport.CodeUnavailable)),
+		"node":         testutil.ToFloat64(metrics.NodeExecutions.WithLabelValues("echo", "first", CodeOK)),
+	}
+
+	for name, result := range tests {
+		if result != 1 {
+			t.Errorf("unexpected %s count %f, expected %d", name, result, 1)
+		}
+	}
+
+	if testutil.ToFloat64(metrics.Rollbacks.WithLabelValues("echo", "first")) != 2 {
+		t.Errorf("unexpected rollbacks count, expected %d", 2)
+	}
+
+	if testutil.ToFloat64(metrics.RollbacksFailed.WithLabelValues("echo", "first")) != 1 {
+		t.Errorf("unexpected failed rollbacks count, expected %d", 1)
+	}
+}
+
+func TestCall(t *testing.T) {
+	metrics := New("")
+	request := &transport.Request{Method: &method{name: "Get"}}
+
+	NewCall(&call{}, metrics, "com.service").SendMsg(context.Background(), nil, request, nil)
+	NewCall(&call{err: &transport.Error{Code: transport.CodeUpstream}}, metrics, "com.service").SendMsg(context.Background(), nil, request, nil)
+
+	if testutil.ToFloat64(metrics.Calls.WithLabelValues("com.service", "Get", CodeOK)) != 1 {
+		t.Errorf("unexpected successful calls count, expected %d", 1)
+	}
+
+	if testutil.ToFloat64(metrics.Calls.WithLabelValues("com.service", "Get", transport.CodeUpstream)) != 1 {
+		t.Errorf("unexpected failed calls count, expected %d", 1)
+	}
+}
+
+func TestFlow(t *testing.T) {
+	metrics := New("")
+
+	NewFlow(&manager{}, metrics, "http", "GET /echo").Call(context.Background(), nil)
+	NewFlow(&manager{err: context.DeadlineExceeded}, metrics, "http", "GET /echo").Call(context.Background(), nil)
+
+	if testutil.ToFloat64(metrics.Requests.WithLabelValues("http", "GET /echo", CodeOK)) != 1 {
+		t.Errorf("unexpected successful requests count, expected %d", 1)
+	}
+
+	if testutil.ToFloat64(metrics.Requests.WithLabelValues("http", "GET /echo", transport.CodeTimeout)) != 1 {
+		t.Errorf("unexpected failed requests count, expected %d", 1)
+	}
+}
+
+func TestFlowReject(t *testing.T) {
+	metrics := New("")
+	flow := NewFlow(&manager{}, metrics, "http", "POST /echo")
+
+	transport.Reject(context.Background(), flow, &transport.Error{Code: transport.CodeInvalidArgument})
+
+	if testutil.ToFloat64(metrics.Requests.WithLabelValues("http", "POST /echo", transport.CodeInvalidArgument)) != 1 {
+		t.Errorf("unexpected rejected requests count, expected %d", 1)
+	}
+}
+
+func TestEndpoint(t *testing.T) {
+	tests := map[string]specs.Options{
+		"GET /users/:id": {MethodOption: "get", EndpointOption: "/users/:id"},
+		"/users":         {EndpointOption: "/users"},
+		"echo":           {},
+	}
+
+	for expected, options := range tests {
+		t.Run(expected, func(t *testing.T) {
+			result := Endpoint(&transport.Endpoint{Flow: &manager{}, Options: options})
+			if result != expected {
+				t.Fatalf("unexpected endpoint %s, expected %s", result, expected)
+			}
+		})
+	}
+}
+
+func TestHandler(t *testing.T) {
+	metrics := New("")
+	metrics.AfterFlow(context.Background(), "echo", time.Second, nil, nil)
+
+	server := httptest.NewServer(metrics.Handler())
+	defer server.Close()
+
+	res, err := server.Client().Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer res.Body.Close()
+
+	bb, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(string(bb), `maestro_flow_executions_total{code="ok",flow="echo"} 1`) {
+		t.Fatalf("unexpected metrics output %s", bb)
+	}
+}
+
+func TestServeWithoutAddress(t *testing.T) {
+	metrics := New("")
+
+	err := metrics.Serve()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = metrics.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,115 @@
+package metrics
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/jexia/maestro/refs"
+	"github.com/jexia/maestro/transport"
+)
+
+const (
+	// EndpointOption represents the listener endpoint option key used as endpoint label
+	EndpointOption = "endpoint"
+	// MethodOption represents the listener method option key used as endpoint label
+	MethodOption = "method"
+)
+
+// NewCall wraps the given transport call, the calls to the given service are collected as metrics
+func NewCall(call transport.Call, metrics *Metrics, service string) transport.Call {
+	return &Call{
+		call:    call,
+		metrics: metrics,
+		service: service,
+	}
+}
+
+// Call represents a transport call whose calls are collected as metrics
+type Call struct {
+	call    transport.Call
+	metrics *Metrics
+	service string
+}
+
+// SendMsg calls the wrapped transport call and collects the call metrics
+func (call *Call) SendMsg(ctx context.Context, writer transport.ResponseWriter, request *transport.Request, refs *refs.Store) error {
+	method := ""
+	if request != nil && request.Method != nil {
+		method = request.Method.GetName()
+	}
+
+	start := time.Now()
+	err := call.call.SendMsg(ctx, writer, request, refs)
+
+	call.metrics.Calls.WithLabelValues(call.service, method, Code(err)).Inc()
+	call.metrics.CallDuration.WithLabelValues(call.service, method).Observe(time.Since(start).Seconds())
+
+	return err
+}
+
+// GetMethods returns the available methods within the wrapped transport call
+func (call *Call) GetMethods() []transport.Method {
+	return call.call.GetMethods()
+}
+
+// GetMethod attempts to return the method with the given name
+func (call *Call) GetMethod(name string) transport.Method {
+	return call.call.GetMethod(name)
+}
+
+// Close closes the wrapped transport call
+func (call *Call) Close() error {
+	return call.call.Close()
+}
+
+// Endpoint returns the endpoint label of the given listener endpoint.
+// The label is constructed from the method and endpoint options (ex: GET /users/:id),
+// the flow name is returned if the endpoint does not define a path.
+func Endpoint(endpoint *transport.Endpoint) string {
+	path := endpoint.Options[EndpointOption]
+	if path == "" {
+		return endpoint.Flow.GetName()
+	}
+
+	method := endpoint.Options[MethodOption]
+	if method == "" {
+		return path
+	}
+
+	return strings.ToUpper(method) + " " + path
+}
+
+// NewFlow wraps the given transport flow, the requests handled by the given listener endpoint are collected as metrics
+func NewFlow(flow transport.Flow, metrics *Metrics, listener string, endpoint string) transport.Flow {
+	return &Flow{
+		Flow:     flow,
+		metrics:  metrics,
+		listener: listener,
+		endpoint: endpoint,
+	}
+}
+
+// Flow represents a transport flow whose listener requests are collected as metrics
+type Flow struct {
+	transport.Flow
+	metrics  *Metrics
+	listener string
+	endpoint string
+}
+
+// Call calls the wrapped flow and collects the listener request metrics
+func (flow *Flow) Call(ctx context.Context, refs *refs.Store) error {
+	start := time.Now()
+	err := flow.Flow.Call(ctx, refs)
+
+	flow.metrics.Requests.WithLabelValues(flow.listener, flow.endpoint, Code(err)).Inc()
+	flow.metrics.RequestDuration.WithLabelValues(flow.listener, flow.endpoint).Observe(time.Since(start).Seconds())
+
+	return err
+}
+
+// Reject collects the listener request metrics of a request rejected before the wrapped flow has been called
+func (flow *Flow) Reject(ctx context.Context, err error) {
+	flow.metrics.Requests.WithLabelValues(flow.listener, flow.endpoint, Code(err)).Inc()
+}
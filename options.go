@@ -20,5 +20,26 @@ var WithSchema = constructor.WithSchema
 // WithLogLevel sets the log level for the given module
 var WithLogLevel = constructor.WithLogLevel
 
-// WithFunctions defines the custom defined functions to be used
+// WithFunctions appends the given custom defined functions to the functions to be used
 var WithFunctions = constructor.WithFunctions
+
+// WithJournal sets the journal used to record flow executions
+var WithJournal = constructor.WithJournal
+
+// WithDeadLetter sets the dead letter sink receiving the rollbacks which could not be compensated
+var WithDeadLetter = constructor.WithDeadLetter
+
+// WithTracing sets the exporter receiving the spans of traced flow executions
+var WithTracing = constructor.WithTracing
+
+// WithCache sets the cache constructor used to construct the caches of resources defining a cache policy
+var WithCache = constructor.WithCache
+
+// WithFlowHooks appends the given hooks called around the flow, node and rollback executions of all flows
+var WithFlowHooks = constructor.WithFlowHooks
+
+// WithMetrics sets the collector of the flow, node, rollback, transport call and listener request metrics
+var WithMetrics = constructor.WithMetrics
+
+// WithSecrets sets the secret provider used to resolve the secret references on startup
+var WithSecrets = constructor.WithSecrets
@@ -20,8 +20,8 @@ func (references References) MergeLeft(incoming ...References) {
 func ParameterReferences(params *specs.ParameterMap) References {
 	result := make(map[string]*specs.PropertyReference)
 	for _, prop := range params.Header {
-		if prop.Reference != nil {
-			result[prop.Reference.String()] = prop.Reference
+		for key, ref := range PropertyReferences(prop) {
+			result[key] = ref
 		}
 	}
 
@@ -40,6 +40,18 @@ func PropertyReferences(property *specs.Property) References {
 		result[property.Reference.String()] = property.Reference
 	}
 
+	for _, operand := range property.Expression.Properties() {
+		for key, ref := range PropertyReferences(operand) {
+			result[key] = ref
+		}
+	}
+
+	for _, argument := range property.Arguments {
+		for key, ref := range PropertyReferences(argument) {
+			result[key] = ref
+		}
+	}
+
 	if property.Nested == nil {
 		return result
 	}
@@ -1,9 +1,11 @@
 package refs
 
 import (
+	"reflect"
 	"sync"
 
 	"github.com/jexia/maestro/specs"
+	"github.com/jexia/maestro/specs/lookup"
 )
 
 // New constructs a new reference with the given path
@@ -48,10 +50,20 @@ func NewStore(size int) *Store {
 	}
 }
 
+// NewOverlay constructs a new store which falls back to the given stores when a reference has not been found.
+// All references are stored inside the newly constructed store, the given stores are left untouched.
+func NewOverlay(size int, fallbacks ...*Store) *Store {
+	return &Store{
+		values:    make(map[string]*Reference, size),
+		fallbacks: fallbacks,
+	}
+}
+
 // Store references
 type Store struct {
-	values map[string]*Reference
-	mutex  sync.Mutex
+	values    map[string]*Reference
+	fallbacks []*Store
+	mutex     sync.Mutex
 }
 
 // StoreReference stores the given resource, path and value inside the references store
@@ -62,17 +74,223 @@ func (store *Store) StoreReference(resource string, reference *Reference) {
 	store.mutex.Unlock()
 }
 
-// Load attempts to load the defined value for the given resource and path
+// Load attempts to load the defined value for the given resource and path.
+// The configured fallback stores are consulted when the reference is not available inside the given store.
+// Index and wildcard selectors inside the given path are resolved against the repeated references.
 func (store *Store) Load(resource string, path string) *Reference {
+	ref := store.load(resource, path)
+	if ref != nil || !lookup.HasSelectors(path) {
+		return ref
+	}
+
+	return store.Select(resource, path)
+}
+
+func (store *Store) load(resource string, path string) *Reference {
 	hash := resource + path
 	store.mutex.Lock()
 	ref, has := store.values[hash]
 	store.mutex.Unlock()
-	if !has {
+	if has {
+		return ref
+	}
+
+	for _, fallback := range store.fallbacks {
+		ref := fallback.load(resource, path)
+		if ref != nil {
+			return ref
+		}
+	}
+
+	return nil
+}
+
+// Select resolves the index and wildcard selectors inside the given path (ex: items[0].id, items[-1] or items[*].id).
+// A reference to the selected value is returned when a single item is selected.
+// Wildcard selections return a repeated reference holding the selected items, the selected values are flattened.
+// Nil is returned when the path is invalid or a selected item does not exist.
+func (store *Store) Select(resource string, path string) *Reference {
+	base, selectors, err := lookup.ParseSelectors(path)
+	if err != nil || len(selectors) == 0 {
 		return nil
 	}
 
-	return ref
+	stores := []*Store{store}
+	values := []interface{}{}
+	wildcard := false
+
+	for _, selector := range selectors {
+		wildcard = wildcard || selector.Wildcard
+		items := []*Store{}
+
+		for _, current := range stores {
+			ref := current.Load(resource, selector.Path)
+			if ref == nil {
+				continue
+			}
+
+			if ref.Repeated == nil {
+				values = append(values, SelectValues(ref.Value, selector)...)
+				continue
+			}
+
+			items = append(items, SelectStores(ref.Repeated, selector)...)
+		}
+
+		stores = items
+	}
+
+	// The selected items themselves are referenced (ex: items[0] or tags[*])
+	if base == selectors[len(selectors)-1].Path {
+		if !wildcard {
+			if len(values) == 1 {
+				return &Reference{Path: path, Value: values[0]}
+			}
+
+			if len(stores) == 1 {
+				return &Reference{Path: path}
+			}
+
+			return nil
+		}
+
+		return store.Collect(resource, path, stores, values)
+	}
+
+	if !wildcard {
+		if len(stores) != 1 {
+			return nil
+		}
+
+		return stores[0].Load(resource, base)
+	}
+
+	items := []*Store{}
+
+	for _, item := range stores {
+		ref := item.Load(resource, base)
+		if ref == nil {
+			continue
+		}
+
+		if ref.Repeated != nil {
+			items = append(items, ref.Repeated...)
+			continue
+		}
+
+		if ref.Value != nil {
+			values = append(values, ref.Value)
+		}
+	}
+
+	return store.Collect(resource, path, items, values)
+}
+
+// Collect constructs a repeated reference holding the given items and values.
+// Every value is stored inside its own item store on the given path.
+func (store *Store) Collect(resource string, path string, items []*Store, values []interface{}) *Reference {
+	result := New(path)
+	result.Repeated = items
+
+	if len(values) == 0 {
+		return result
+	}
+
+	result.Value = values
+
+	for _, value := range values {
+		item := NewStore(1)
+		item.StoreValue(resource, path, value)
+		result.Repeated = append(result.Repeated, item)
+	}
+
+	return result
+}
+
+// SelectStores returns the repeated items matching the given selector.
+// Negative indexes select items starting from the last item.
+func SelectStores(items []*Store, selector *lookup.Selector) []*Store {
+	if selector.Wildcard {
+		return items
+	}
+
+	index := selector.Index
+	if index < 0 {
+		index += len(items)
+	}
+
+	if index < 0 || index >= len(items) || items[index] == nil {
+		return nil
+	}
+
+	return []*Store{items[index]}
+}
+
+// SelectValues returns the repeated values matching the given selector.
+// Nil is returned if the given value is not a slice.
+func SelectValues(value interface{}, selector *lookup.Selector) []interface{} {
+	if value == nil {
+		return nil
+	}
+
+	slice := reflect.ValueOf(value)
+	if slice.Kind() != reflect.Slice && slice.Kind() != reflect.Array {
+		return nil
+	}
+
+	if selector.Wildcard {
+		result := make([]interface{}, slice.Len())
+		for index := range result {
+			result[index] = slice.Index(index).Interface()
+		}
+
+		return result
+	}
+
+	index := selector.Index
+	if index < 0 {
+		index += slice.Len()
+	}
+
+	if index < 0 || index >= slice.Len() {
+		return nil
+	}
+
+	return []interface{}{slice.Index(index).Interface()}
+}
+
+// Value returns the value of the given property.
+// Expressions are evaluated and functions are called with the values inside the store.
+// The repeated stores are returned for references to repeated values.
+// The default value is returned if the property does not reference a value or if the referenced value is not set.
+func (store *Store) Value(property *specs.Property) interface{} {
+	if property.Expression != nil {
+		return property.Expression.Evaluate(store.Value)
+	}
+
+	if property.Function != nil {
+		args := make([]interface{}, len(property.Arguments))
+		for index, argument := range property.Arguments {
+			args[index] = store.Value(argument)
+		}
+
+		return property.Function(args...)
+	}
+
+	if property.Reference == nil || store == nil {
+		return property.Default
+	}
+
+	ref := store.Load(property.Reference.Resource, property.Reference.Path)
+	if ref == nil {
+		return property.Default
+	}
+
+	if ref.Value == nil && ref.Repeated != nil {
+		return ref.Repeated
+	}
+
+	return ref.Value
 }
 
 // StoreValues stores the given values to the reference store
@@ -115,3 +333,78 @@ func (store *Store) NewRepeating(resource string, path string, reference *Refere
 		reference.Set(index, store)
 	}
 }
+
+// Snapshot represents a serializable copy of a stored reference
+type Snapshot struct {
+	Resource string        `json:"resource"`
+	Path     string        `json:"path"`
+	Value    interface{}   `json:"value,omitempty"`
+	Repeated [][]*Snapshot `json:"repeated,omitempty"`
+}
+
+// Snapshot returns a serializable copy of the references stored inside the given store.
+// Only references of the given resources are included, all references are included when no resources are given.
+// References available inside fallback stores are not included.
+func (store *Store) Snapshot(resources ...string) []*Snapshot {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	include := make(map[string]struct{}, len(resources))
+	for _, resource := range resources {
+		include[resource] = struct{}{}
+	}
+
+	result := make([]*Snapshot, 0, len(store.values))
+
+	for hash, reference := range store.values {
+		resource := hash[:len(hash)-len(reference.Path)]
+
+		if len(include) > 0 {
+			if _, has := include[resource]; !has {
+				continue
+			}
+		}
+
+		snapshot := &Snapshot{
+			Resource: resource,
+			Path:     reference.Path,
+			Value:    reference.Value,
+		}
+
+		if reference.Repeated != nil {
+			snapshot.Repeated = make([][]*Snapshot, len(reference.Repeated))
+			for index, item := range reference.Repeated {
+				if item == nil {
+					continue
+				}
+
+				snapshot.Repeated[index] = item.Snapshot()
+			}
+		}
+
+		result = append(result, snapshot)
+	}
+
+	return result
+}
+
+// Restore stores the given snapshots inside the given store.
+// Repeated items are restored as overlays falling back to the given store.
+func (store *Store) Restore(snapshots []*Snapshot) {
+	for _, snapshot := range snapshots {
+		reference := New(snapshot.Path)
+		reference.Value = snapshot.Value
+
+		if snapshot.Repeated != nil {
+			reference.Repeating(len(snapshot.Repeated))
+
+			for index, items := range snapshot.Repeated {
+				item := NewOverlay(len(items), store)
+				item.Restore(items)
+				reference.Set(index, item)
+			}
+		}
+
+		store.StoreReference(snapshot.Resource, reference)
+	}
+}
@@ -2,6 +2,7 @@ package refs
 
 import (
 	"encoding/json"
+	"reflect"
 	"testing"
 
 	"github.com/jexia/maestro/specs"
@@ -213,3 +214,232 @@ func TestStoreRepeatedValues(t *testing.T) {
 		t.Fatalf("unexpected value %+v, expected %+v", result.Value, value)
 	}
 }
+
+func TestOverlayStore(t *testing.T) {
+	resource := "input"
+
+	parent := NewStore(2)
+	parent.StoreValue(resource, "message", "hello world")
+	parent.StoreValue(resource, "id", "parent")
+
+	item := NewStore(1)
+	item.StoreValue(resource, "id", "item")
+
+	overlay := NewOverlay(1, item, parent)
+	overlay.StoreValue("response", "status", "ok")
+
+	tests := map[string]interface{}{
+		"message": "hello world",
+		"id":      "item",
+	}
+
+	for path, expected := range tests {
+		result := overlay.Load(resource, path)
+		if result == nil {
+			t.Fatalf("did not return reference %s", path)
+		}
+
+		if result.Value != expected {
+			t.Fatalf("unexpected value %+v, expected %+v", result.Value, expected)
+		}
+	}
+
+	if overlay.Load("response", "status") == nil {
+		t.Fatal("did not return the stored overlay reference")
+	}
+
+	if parent.Load("response", "status") != nil || item.Load("response", "status") != nil {
+		t.Fatal("overlay reference has been stored inside a fallback store")
+	}
+}
+
+func TestStoreValue(t *testing.T) {
+	store := NewStore(1)
+	store.StoreValue("input", "name", "john")
+
+	expression, err := specs.ParseExpression("", nil, "input:nickname ?? input:name + ' doe'")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[*specs.Property]interface{}{
+		{Default: "constant"}: "constant",
+		{Reference: &specs.PropertyReference{Resource: "input", Path: "name"}}:                     "john",
+		{Reference: &specs.PropertyReference{Resource: "input", Path: "unknown"}, Default: "jane"}: "jane",
+		{Expression: expression}: "john doe",
+		{
+			Function: func(args ...interface{}) interface{} {
+				return args[0].(string) + "!"
+			},
+			Arguments: []*specs.Property{
+				{Reference: &specs.PropertyReference{Resource: "input", Path: "name"}},
+			},
+		}: "john!",
+	}
+
+	for property, expected := range tests {
+		result := store.Value(property)
+		if result != expected {
+			t.Fatalf("unexpected value %+v, expected %+v", result, expected)
+		}
+	}
+}
+
+func NewSelectStore() *Store {
+	store := NewStore(2)
+	store.StoreValues("input", "", map[string]interface{}{
+		"tags": []interface{}{"a", "b", "c"},
+		"orders": []map[string]interface{}{
+			{
+				"id": int64(1),
+				"lines": []map[string]interface{}{
+					{"sku": "first"},
+					{"sku": "second"},
+				},
+			},
+			{
+				"id": int64(2),
+				"lines": []map[string]interface{}{
+					{"sku": "third"},
+				},
+			},
+		},
+	})
+
+	return store
+}
+
+func TestStoreSelect(t *testing.T) {
+	store := NewSelectStore()
+
+	tests := map[string]interface{}{
+		"orders[0].id":             int64(1),
+		"orders[-1].id":            int64(2),
+		"orders[0].lines[1].sku":   "second",
+		"orders[-1].lines[-1].sku": "third",
+		"tags[1]":                  "b",
+		"tags[-1]":                 "c",
+	}
+
+	for input, expected := range tests {
+		t.Run(input, func(t *testing.T) {
+			result := store.Load("input", input)
+			if result == nil {
+				t.Fatal("unexpected empty reference")
+			}
+
+			if result.Value != expected {
+				t.Fatalf("unexpected value %+v, expected %+v", result.Value, expected)
+			}
+		})
+	}
+}
+
+func TestStoreSelectWildcard(t *testing.T) {
+	store := NewSelectStore()
+
+	tests := map[string][]interface{}{
+		"orders[*].id":           {int64(1), int64(2)},
+		"orders[*].lines[*].sku": {"first", "second", "third"},
+		"orders[0].lines[*].sku": {"first", "second"},
+		"tags[*]":                {"a", "b", "c"},
+	}
+
+	for input, expected := range tests {
+		t.Run(input, func(t *testing.T) {
+			result := store.Load("input", input)
+			if result == nil {
+				t.Fatal("unexpected empty reference")
+			}
+
+			if !reflect.DeepEqual(result.Value, expected) {
+				t.Fatalf("unexpected value %+v, expected %+v", result.Value, expected)
+			}
+
+			if len(result.Repeated) != len(expected) {
+				t.Fatalf("unexpected repeated length %d, expected %d", len(result.Repeated), len(expected))
+			}
+
+			for index, item := range result.Repeated {
+				value := item.Value(&specs.Property{Reference: &specs.PropertyReference{Resource: "input", Path: input}})
+				if value != expected[index] {
+					t.Errorf("unexpected item value %+v, expected %+v", value, expected[index])
+				}
+			}
+		})
+	}
+}
+
+func TestStoreSelectItems(t *testing.T) {
+	store := NewSelectStore()
+
+	result := store.Load("input", "orders[*].lines")
+	if result == nil {
+		t.Fatal("unexpected empty reference")
+	}
+
+	if len(result.Repeated) != 3 {
+		t.Fatalf("unexpected repeated length %d, expected 3", len(result.Repeated))
+	}
+
+	sku := result.Repeated[2].Load("input", "orders.lines.sku")
+	if sku == nil || sku.Value != "third" {
+		t.Fatalf("unexpected item reference %+v", sku)
+	}
+
+	if store.Load("input", "orders[1]") == nil {
+		t.Fatal("unexpected empty item reference")
+	}
+
+	for _, input := range []string{"orders[2].id", "orders[-3].id", "tags[3]", "orders[a].id", "unknown[0]"} {
+		if store.Load("input", input) != nil {
+			t.Errorf("unexpected reference for %s", input)
+		}
+	}
+}
+
+func TestStoreSnapshot(t *testing.T) {
+	store := NewStore(3)
+	store.StoreValue("input", "message", "hello world")
+	store.StoreValue("first", "id", int64(42))
+	store.StoreValues("first", "", map[string]interface{}{
+		"items": []map[string]interface{}{
+			{"id": "item"},
+		},
+	})
+
+	snapshots := store.Snapshot("first")
+	if len(snapshots) != 2 {
+		t.Fatalf("unexpected snapshots length %d, expected 2", len(snapshots))
+	}
+
+	restored := NewStore(len(snapshots))
+	restored.Restore(snapshots)
+
+	if restored.Load("input", "message") != nil {
+		t.Fatal("excluded resource has been included inside the snapshot")
+	}
+
+	result := restored.Load("first", "id")
+	if result == nil {
+		t.Fatal("did not restore reference")
+	}
+
+	if result.Value != int64(42) {
+		t.Fatalf("unexpected value %+v, expected %+v", result.Value, int64(42))
+	}
+
+	result = restored.Load("first", "items")
+	if result == nil || len(result.Repeated) != 1 {
+		t.Fatal("did not restore repeated reference")
+	}
+
+	result = result.Repeated[0].Load("first", "items.id")
+	if result == nil {
+		t.Fatal("did not restore repeating reference")
+	}
+
+	if result.Value != "item" {
+		t.Fatalf("unexpected value %+v, expected %+v", result.Value, "item")
+	}
+}
@@ -87,6 +87,10 @@ func NewService(descriptor *desc.ServiceDescriptor) schema.Service {
 	ext, err := proto.GetExtension(descriptor.GetOptions(), annotations.E_Service)
 	if err == nil {
 		ext := ext.(*annotations.Service)
+		for key, value := range ext.GetOptions() {
+			options[key] = value
+		}
+
 		options[HostOption] = ext.GetHost()
 		options[TransportOption] = ext.GetTransport()
 		options[CodecOption] = ext.GetCodec()
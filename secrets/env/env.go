@@ -0,0 +1,40 @@
+package env
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"github.com/jexia/maestro/specs/trace"
+)
+
+// New constructs a new secret provider reading secrets from environment variables.
+// Secret keys are upper cased, dots and dashes are replaced with underscores and the given prefix is prepended (ex: stripe.key is read from PREFIX_STRIPE_KEY).
+func New(prefix string) *Provider {
+	return &Provider{
+		Prefix: prefix,
+	}
+}
+
+// Provider represents a secret provider reading secrets from environment variables
+type Provider struct {
+	Prefix string
+}
+
+// Variable returns the environment variable name of the given secret key
+func (provider *Provider) Variable(key string) string {
+	key = strings.NewReplacer(".", "_", "-", "_").Replace(key)
+	return provider.Prefix + strings.ToUpper(key)
+}
+
+// Secret returns the value of the environment variable of the given secret key
+func (provider *Provider) Secret(ctx context.Context, key string) (string, error) {
+	variable := provider.Variable(key)
+
+	value, has := os.LookupEnv(variable)
+	if !has {
+		return "", trace.New(trace.WithMessage("undefined environment variable '%s'", variable))
+	}
+
+	return value, nil
+}
@@ -0,0 +1,47 @@
+package env
+
+import (
+	"context"
+	"os"
+	"testing"
+)
+
+func TestVariable(t *testing.T) {
+	tests := map[string]string{
+		"stripe.key":      "MAESTRO_STRIPE_KEY",
+		"api-key":         "MAESTRO_API_KEY",
+		"tenant.id.value": "MAESTRO_TENANT_ID_VALUE",
+	}
+
+	provider := New("MAESTRO_")
+
+	for input, expected := range tests {
+		t.Run(input, func(t *testing.T) {
+			result := provider.Variable(input)
+			if result != expected {
+				t.Fatalf("unexpected variable %s, expected %s", result, expected)
+			}
+		})
+	}
+}
+
+func TestSecret(t *testing.T) {
+	os.Setenv("MAESTRO_TEST_STRIPE_KEY", "sk_live_secret")
+	defer os.Unsetenv("MAESTRO_TEST_STRIPE_KEY")
+
+	provider := New("MAESTRO_TEST_")
+
+	result, err := provider.Secret(context.Background(), "stripe.key")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result != "sk_live_secret" {
+		t.Fatalf("unexpected secret %s", result)
+	}
+
+	_, err = provider.Secret(context.Background(), "unknown")
+	if err == nil {
+		t.Fatal("unexpected pass")
+	}
+}
@@ -0,0 +1,41 @@
+package file
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/jexia/maestro/specs/trace"
+)
+
+// New constructs a new secret provider reading secrets from files inside the given directory.
+// Every secret is stored inside a file named after the secret key (ex: mounted Kubernetes or Docker secrets).
+func New(dir string) *Provider {
+	return &Provider{
+		Dir: dir,
+	}
+}
+
+// Provider represents a secret provider reading secrets from files on disk
+type Provider struct {
+	Dir string
+}
+
+// Secret returns the content of the file of the given secret key.
+// Trailing newlines are trimmed from the file content.
+func (provider *Provider) Secret(ctx context.Context, key string) (string, error) {
+	path := filepath.Join(provider.Dir, key)
+
+	rel, err := filepath.Rel(provider.Dir, path)
+	if err != nil || filepath.IsAbs(key) || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", trace.New(trace.WithMessage("invalid secret key '%s', secrets have to be located inside '%s'", key, provider.Dir))
+	}
+
+	bb, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimRight(string(bb), "\r\n"), nil
+}
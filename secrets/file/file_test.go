@@ -0,0 +1,60 @@
+package file
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSecret(t *testing.T) {
+	dir, err := ioutil.TempDir("", "secrets")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	err = ioutil.WriteFile(filepath.Join(dir, "stripe.key"), []byte("sk_live_secret\n"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	provider := New(dir)
+
+	result, err := provider.Secret(context.Background(), "stripe.key")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result != "sk_live_secret" {
+		t.Fatalf("unexpected secret %q", result)
+	}
+}
+
+func TestSecretFail(t *testing.T) {
+	dir, err := ioutil.TempDir("", "secrets")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	tests := []string{
+		"unknown",
+		"../passwd",
+		"nested/../../passwd",
+	}
+
+	provider := New(dir)
+
+	for _, input := range tests {
+		t.Run(input, func(t *testing.T) {
+			_, err := provider.Secret(context.Background(), input)
+			if err == nil {
+				t.Fatal("unexpected pass")
+			}
+		})
+	}
+}
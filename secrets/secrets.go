@@ -0,0 +1,403 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/jexia/maestro/logger"
+	"github.com/jexia/maestro/refs"
+	"github.com/jexia/maestro/schema"
+	"github.com/jexia/maestro/specs"
+	"github.com/jexia/maestro/specs/trace"
+	"github.com/jexia/maestro/specs/types"
+	"github.com/sirupsen/logrus"
+)
+
+// Redacted represents the value replacing secret values inside log entries
+const Redacted = "[REDACTED]"
+
+// SecretProvider represents a provider of secret values
+type SecretProvider interface {
+	Secret(ctx context.Context, key string) (string, error)
+}
+
+// NewResolver constructs a new resolver resolving environment variables and secrets.
+// Secrets are looked up through the given provider, references to secrets fail if no provider is given.
+func NewResolver(ctx context.Context, provider SecretProvider) *Resolver {
+	return &Resolver{
+		ctx:      ctx,
+		provider: provider,
+		secrets:  make(map[string]string),
+		Lookup:   os.LookupEnv,
+	}
+}
+
+// Resolver resolves the env and secret resource references to constant values.
+// The resolver implements a logrus hook which redacts all resolved secret values from log entries.
+// Only values resolved through the secret resource are redacted, environment variable values are not redacted.
+type Resolver struct {
+	ctx      context.Context
+	provider SecretProvider
+	mutex    sync.RWMutex
+	secrets  map[string]string
+	Lookup   func(key string) (string, bool)
+}
+
+// IsResource checks whether the given reference references a environment variable or secret
+func IsResource(reference *specs.PropertyReference) bool {
+	if reference == nil {
+		return false
+	}
+
+	return reference.Resource == specs.EnvResource || reference.Resource == specs.SecretResource
+}
+
+// Value returns the value of the given environment variable or secret reference.
+// Resolved secret values are remembered to be redacted, environment variable values are never redacted.
+func (resolver *Resolver) Value(reference *specs.PropertyReference) (string, error) {
+	if reference.Path == "" {
+		return "", trace.New(trace.WithMessage("undefined key in '%s' reference", reference.Resource))
+	}
+
+	if reference.Resource == specs.EnvResource {
+		value, has := resolver.Lookup(reference.Path)
+		if !has {
+			return "", trace.New(trace.WithMessage("undefined environment variable '%s'", reference.Path))
+		}
+
+		return value, nil
+	}
+
+	resolver.mutex.RLock()
+	value, has := resolver.secrets[reference.Path]
+	resolver.mutex.RUnlock()
+
+	if has {
+		return value, nil
+	}
+
+	if resolver.provider == nil {
+		return "", trace.New(trace.WithMessage("unable to resolve secret '%s', no secret provider has been configured", reference.Path))
+	}
+
+	logger.FromCtx(resolver.ctx, logger.Core).WithField("key", reference.Path).Debug("Resolving secret")
+
+	value, err := resolver.provider.Secret(resolver.ctx, reference.Path)
+	if err != nil {
+		return "", trace.New(trace.WithMessage("unable to resolve secret '%s': %s", reference.Path, err))
+	}
+
+	resolver.mutex.Lock()
+	resolver.secrets[reference.Path] = value
+	resolver.mutex.Unlock()
+
+	return value, nil
+}
+
+// Manifest resolves all environment variable and secret references inside the given manifest
+func (resolver *Resolver) Manifest(manifest *specs.Manifest) error {
+	logger.FromCtx(resolver.ctx, logger.Core).Info("Resolving environment variables and secrets")
+
+	for _, flow := range manifest.Flows {
+		for _, params := range []*specs.ParameterMap{flow.Input, flow.Output} {
+			err := resolver.ParameterMap(params)
+			if err != nil {
+				return err
+			}
+		}
+
+		if flow.OnError != nil {
+			err := resolver.ParameterMap(flow.OnError.Response)
+			if err != nil {
+				return err
+			}
+		}
+
+		err := resolver.Nodes(flow.Nodes)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, proxy := range manifest.Proxy {
+		err := resolver.Nodes(proxy.Nodes)
+		if err != nil {
+			return err
+		}
+
+		err = resolver.Call(proxy.Forward)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Nodes resolves all environment variable and secret references inside the given nodes
+func (resolver *Resolver) Nodes(nodes []*specs.Node) error {
+	for _, node := range nodes {
+		properties := []*specs.Property{}
+
+		if node.Condition != nil {
+			properties = append(properties, node.Condition.Left, node.Condition.Right)
+		}
+
+		if node.Foreach != nil {
+			properties = append(properties, node.Foreach.Property)
+		}
+
+		if node.Cache != nil {
+			properties = append(properties, node.Cache.Key...)
+		}
+
+		for _, property := range properties {
+			err := resolver.Property(property)
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, call := range []*specs.Call{node.Call, node.Rollback} {
+			err := resolver.Call(call)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Call resolves all environment variable and secret references inside the given call
+func (resolver *Resolver) Call(call *specs.Call) error {
+	if call == nil {
+		return nil
+	}
+
+	for _, params := range []*specs.ParameterMap{call.Request, call.Response, call.Error} {
+		err := resolver.ParameterMap(params)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ParameterMap resolves all environment variable and secret references inside the given parameter map
+func (resolver *Resolver) ParameterMap(params *specs.ParameterMap) error {
+	if params == nil {
+		return nil
+	}
+
+	for _, header := range params.Header {
+		err := resolver.Property(header)
+		if err != nil {
+			return err
+		}
+	}
+
+	return resolver.Property(params.Property)
+}
+
+// Property replaces all environment variable and secret references inside the given property with constant values.
+// Nested properties, expression operands and function arguments are resolved as well.
+func (resolver *Resolver) Property(property *specs.Property) error {
+	if property == nil {
+		return nil
+	}
+
+	if IsResource(property.Reference) {
+		value, err := resolver.Value(property.Reference)
+		if err != nil {
+			return trace.New(trace.WithMessage("%s in '%s'", err, property.Path))
+		}
+
+		property.Reference = nil
+		property.Default = value
+		property.Type = types.TypeString
+		property.Label = types.LabelOptional
+	}
+
+	for _, operand := range property.Expression.Properties() {
+		err := resolver.Property(operand)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, argument := range property.Arguments {
+		err := resolver.Property(argument)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, nested := range property.Nested {
+		err := resolver.Property(nested)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// String resolves the templates inside the given value.
+// Templates could only reference environment variables and secrets.
+func (resolver *Resolver) String(path string, functions specs.CustomDefinedFunctions, value string) (string, error) {
+	if !specs.ContainsTemplate(value) {
+		return value, nil
+	}
+
+	property, err := specs.ParseTemplate(resolver.ctx, path, functions, value)
+	if err != nil {
+		return "", err
+	}
+
+	err = resolver.Property(property)
+	if err != nil {
+		return "", err
+	}
+
+	for key := range refs.PropertyReferences(property) {
+		return "", trace.New(trace.WithMessage("unable to resolve '%s' in '%s', only environment variables and secrets could be referenced", key, path))
+	}
+
+	var store *refs.Store
+	result := store.Value(property)
+	if result == nil {
+		return "", nil
+	}
+
+	return fmt.Sprint(result), nil
+}
+
+// Schema resolves the templates inside the host and options of the services inside the given store.
+// Services containing templates are replaced with their resolved equivalent.
+func (resolver *Resolver) Schema(store *schema.Store, functions specs.CustomDefinedFunctions) error {
+	result := &collection{}
+
+	for _, service := range store.GetServices() {
+		if service == nil {
+			continue
+		}
+
+		host, err := resolver.String(service.GetName()+".host", functions, service.GetHost())
+		if err != nil {
+			return err
+		}
+
+		options := make(schema.Options, len(service.GetOptions()))
+		resolved := host != service.GetHost()
+
+		for key, value := range service.GetOptions() {
+			options[key], err = resolver.String(service.GetName()+".options."+key, functions, value)
+			if err != nil {
+				return err
+			}
+
+			resolved = resolved || options[key] != value
+		}
+
+		if !resolved {
+			continue
+		}
+
+		result.services = append(result.services, &Service{
+			Service: service,
+			host:    host,
+			options: options,
+		})
+	}
+
+	store.Add(result)
+	return nil
+}
+
+// Redact replaces all resolved secret values inside the given value.
+// Environment variable values are not redacted, sensitive values should be referenced as secret.
+func (resolver *Resolver) Redact(value string) string {
+	resolver.mutex.RLock()
+	defer resolver.mutex.RUnlock()
+
+	for _, secret := range resolver.secrets {
+		if secret == "" {
+			continue
+		}
+
+		value = strings.ReplaceAll(value, secret, Redacted)
+	}
+
+	return value
+}
+
+// Levels returns the log levels of the entries to be redacted
+func (resolver *Resolver) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire redacts all resolved secret values from the message and fields of the given log entry
+func (resolver *Resolver) Fire(entry *logrus.Entry) error {
+	entry.Message = resolver.Redact(entry.Message)
+
+	for key, value := range entry.Data {
+		str := fmt.Sprint(value)
+		redacted := resolver.Redact(str)
+
+		if str == redacted {
+			continue
+		}
+
+		entry.Data[key] = redacted
+	}
+
+	return nil
+}
+
+// Service represents a schema service with a resolved host and options
+type Service struct {
+	schema.Service
+	host    string
+	options schema.Options
+}
+
+// GetHost returns the resolved service host
+func (service *Service) GetHost() string {
+	return service.host
+}
+
+// GetOptions returns the resolved service options
+func (service *Service) GetOptions() schema.Options {
+	return service.options
+}
+
+type collection struct {
+	services []schema.Service
+}
+
+func (collection *collection) GetService(name string) schema.Service {
+	for _, service := range collection.services {
+		if service.GetName() == name {
+			return service
+		}
+	}
+
+	return nil
+}
+
+func (collection *collection) GetServices() []schema.Service {
+	return collection.services
+}
+
+func (collection *collection) GetMessage(name string) schema.Property {
+	return nil
+}
+
+func (collection *collection) GetMessages() []schema.Property {
+	return make([]schema.Property, 0)
+}
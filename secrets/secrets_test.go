@@ -0,0 +1,267 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jexia/maestro/logger"
+	"github.com/jexia/maestro/refs"
+	"github.com/jexia/maestro/schema"
+	"github.com/jexia/maestro/schema/mock"
+	"github.com/jexia/maestro/specs"
+	"github.com/jexia/maestro/specs/types"
+)
+
+type provider map[string]string
+
+func (provider provider) Secret(ctx context.Context, key string) (string, error) {
+	value, has := provider[key]
+	if !has {
+		return "", errors.New("not found")
+	}
+
+	return value, nil
+}
+
+func lookup(variables map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		value, has := variables[key]
+		return value, has
+	}
+}
+
+func NewMockResolver() *Resolver {
+	ctx := logger.WithValue(context.Background())
+	resolver := NewResolver(ctx, provider{"stripe.key": "sk_live_secret"})
+	resolver.Lookup = lookup(map[string]string{"HOST": "localhost", "TENANT": "jexia"})
+	return resolver
+}
+
+func TestResolverProperty(t *testing.T) {
+	ctx := logger.WithValue(context.Background())
+	functions := specs.CustomDefinedFunctions{
+		"upper": func(path string, args ...*specs.Property) (*specs.Property, error) {
+			return &specs.Property{
+				Path: path,
+				Type: types.TypeString,
+				Function: func(values ...interface{}) interface{} {
+					return strings.ToUpper(values[0].(string))
+				},
+			}, nil
+		},
+	}
+
+	tests := map[string]string{
+		"{{ env:TENANT }}":                   "jexia",
+		"{{ secret:stripe.key }}":            "sk_live_secret",
+		"Bearer {{ secret:stripe.key }}":     "Bearer sk_live_secret",
+		"{{ upper(env:TENANT) }}":            "JEXIA",
+		"{{ env:TENANT ?? 'default' }}":      "jexia",
+		"{{ env:HOST }}/{{ env:TENANT }}/v1": "localhost/jexia/v1",
+	}
+
+	for input, expected := range tests {
+		t.Run(input, func(t *testing.T) {
+			resolver := NewMockResolver()
+
+			property, err := specs.ParseTemplate(ctx, "header.Authorization", functions, input)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = resolver.Property(property)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			references := refs.PropertyReferences(property)
+			if len(references) != 0 {
+				t.Fatalf("unexpected references %+v", references)
+			}
+
+			var store *refs.Store
+			result := store.Value(property)
+			if result != expected {
+				t.Fatalf("unexpected result %+v, expected %s", result, expected)
+			}
+		})
+	}
+}
+
+func TestResolverNested(t *testing.T) {
+	resolver := NewMockResolver()
+	params := &specs.ParameterMap{
+		Header: specs.Header{
+			"Authorization": &specs.Property{Path: "Authorization", Reference: &specs.PropertyReference{Resource: specs.SecretResource, Path: "stripe.key"}},
+		},
+		Property: &specs.Property{
+			Nested: map[string]*specs.Property{
+				"tenant": {Path: "tenant", Reference: &specs.PropertyReference{Resource: specs.EnvResource, Path: "TENANT"}},
+				"name":   {Path: "name", Reference: &specs.PropertyReference{Resource: specs.InputResource, Path: "name"}},
+			},
+		},
+	}
+
+	err := resolver.ParameterMap(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	header := params.Header["Authorization"]
+	if header.Reference != nil || header.Default != "sk_live_secret" || header.Type != types.TypeString {
+		t.Errorf("unexpected header %+v", header)
+	}
+
+	tenant := params.Property.Nested["tenant"]
+	if tenant.Reference != nil || tenant.Default != "jexia" {
+		t.Errorf("unexpected tenant %+v", tenant)
+	}
+
+	name := params.Property.Nested["name"]
+	if name.Reference == nil {
+		t.Errorf("unexpected resolved input reference %+v", name)
+	}
+}
+
+func TestResolverFail(t *testing.T) {
+	tests := map[string]*specs.PropertyReference{
+		"undefined environment variable": {Resource: specs.EnvResource, Path: "UNKNOWN"},
+		"undefined secret":               {Resource: specs.SecretResource, Path: "unknown.key"},
+		"undefined key":                  {Resource: specs.SecretResource},
+	}
+
+	for name, reference := range tests {
+		t.Run(name, func(t *testing.T) {
+			resolver := NewMockResolver()
+			err := resolver.Property(&specs.Property{Path: "message", Reference: reference})
+			if err == nil {
+				t.Fatal("unexpected pass")
+			}
+		})
+	}
+}
+
+func TestResolverNoProvider(t *testing.T) {
+	resolver := NewResolver(logger.WithValue(context.Background()), nil)
+	err := resolver.Property(&specs.Property{Path: "message", Reference: &specs.PropertyReference{Resource: specs.SecretResource, Path: "stripe.key"}})
+	if err == nil {
+		t.Fatal("unexpected pass")
+	}
+}
+
+func TestResolverString(t *testing.T) {
+	tests := map[string]string{
+		"http://localhost:8080":                   "http://localhost:8080",
+		"http://{{ env:HOST }}:8080":              "http://localhost:8080",
+		"{{ secret:stripe.key }}":                 "sk_live_secret",
+		"https://{{ env:TENANT }}.{{ env:HOST }}": "https://jexia.localhost",
+		"http://{{ env:HOST":                      "http://{{ env:HOST",
+	}
+
+	for input, expected := range tests {
+		t.Run(input, func(t *testing.T) {
+			resolver := NewMockResolver()
+			result, err := resolver.String("service.host", nil, input)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if result != expected {
+				t.Fatalf("unexpected result %s, expected %s", result, expected)
+			}
+		})
+	}
+}
+
+func TestResolverStringFail(t *testing.T) {
+	tests := []string{
+		"http://{{ input:host }}",
+		"http://{{ env:UNKNOWN }}",
+	}
+
+	for _, input := range tests {
+		t.Run(input, func(t *testing.T) {
+			resolver := NewMockResolver()
+			_, err := resolver.String("service.host", nil, input)
+			if err == nil {
+				t.Fatal("unexpected pass")
+			}
+		})
+	}
+}
+
+func TestResolverSchema(t *testing.T) {
+	ctx := logger.WithValue(context.Background())
+	store := schema.NewStore(ctx)
+	store.Add(mock.NewCollection(mock.Collection{
+		Services: map[string]*mock.Service{
+			"payments": {
+				Host:    "http://{{ env:HOST }}",
+				Options: schema.Options{"api_key": "{{ secret:stripe.key }}"},
+			},
+			"users": {
+				Host: "http://users",
+			},
+		},
+	}))
+
+	resolver := NewMockResolver()
+	err := resolver.Schema(store, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	payments := store.GetService("payments")
+	if payments.GetHost() != "http://localhost" {
+		t.Errorf("unexpected host %s", payments.GetHost())
+	}
+
+	if payments.GetOptions()["api_key"] != "sk_live_secret" {
+		t.Errorf("unexpected options %+v", payments.GetOptions())
+	}
+
+	users := store.GetService("users")
+	if _, is := users.(*Service); is {
+		t.Error("unexpected resolved service without templates")
+	}
+}
+
+func TestRedact(t *testing.T) {
+	ctx := logger.WithValue(context.Background())
+	resolver := NewMockResolver()
+
+	_, err := resolver.Value(&specs.PropertyReference{Resource: specs.SecretResource, Path: "stripe.key"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Environment variable values are not redacted
+	_, err = resolver.Value(&specs.PropertyReference{Resource: specs.EnvResource, Path: "TENANT"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buffer := bytes.NewBuffer([]byte{})
+	log := logger.FromCtx(ctx, logger.Core)
+	log.SetOutput(buffer)
+
+	logger.AddHook(ctx, resolver)
+
+	log.WithField("header", map[string]string{"Authorization": "Bearer sk_live_secret"}).WithField("tenant", "jexia").Error("calling with sk_live_secret")
+
+	result := buffer.String()
+	if strings.Contains(result, "sk_live_secret") {
+		t.Fatalf("secret value has not been redacted: %s", result)
+	}
+
+	if strings.Count(result, Redacted) != 2 {
+		t.Fatalf("unexpected log entry: %s", result)
+	}
+
+	if !strings.Contains(result, "jexia") {
+		t.Fatalf("unexpected redacted field: %s", result)
+	}
+}
@@ -0,0 +1,91 @@
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/jexia/maestro/specs/trace"
+)
+
+// DefaultMount represents the default mount path of the key/value secrets engine
+const DefaultMount = "secret"
+
+// New constructs a new secret provider reading secrets from the Vault compatible key/value (version 2) HTTP API at the given address.
+// Secret keys are split on the last dot into the secret path and field (ex: stripe.key reads the field key of the secret stripe).
+func New(address string, token string, mount string) *Provider {
+	if mount == "" {
+		mount = DefaultMount
+	}
+
+	return &Provider{
+		client:  &http.Client{Timeout: 10 * time.Second},
+		Address: strings.TrimSuffix(address, "/"),
+		Token:   token,
+		Mount:   strings.Trim(mount, "/"),
+	}
+}
+
+// Provider represents a secret provider reading secrets from a Vault compatible HTTP API
+type Provider struct {
+	client  *http.Client
+	Address string
+	Token   string
+	Mount   string
+}
+
+type response struct {
+	Data struct {
+		Data map[string]interface{} `json:"data"`
+	} `json:"data"`
+}
+
+// Secret requests the secret path of the given key and returns the value of the field inside the secret
+func (provider *Provider) Secret(ctx context.Context, key string) (string, error) {
+	index := strings.LastIndex(key, ".")
+	if index <= 0 || index == len(key)-1 {
+		return "", trace.New(trace.WithMessage("invalid secret key '%s', expected a path and field (ex: path.field)", key))
+	}
+
+	path, field := key[:index], key[index+1:]
+	url := fmt.Sprintf("%s/v1/%s/data/%s", provider.Address, provider.Mount, path)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+
+	req.Header.Set("X-Vault-Token", provider.Token)
+
+	res, err := provider.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return "", trace.New(trace.WithMessage("unexpected status code %d while requesting secret '%s'", res.StatusCode, path))
+	}
+
+	result := response{}
+	err = json.NewDecoder(res.Body).Decode(&result)
+	if err != nil {
+		return "", err
+	}
+
+	value, has := result.Data.Data[field]
+	if !has {
+		return "", trace.New(trace.WithMessage("undefined field '%s' inside secret '%s'", field, path))
+	}
+
+	str, is := value.(string)
+	if !is {
+		return fmt.Sprint(value), nil
+	}
+
+	return str, nil
+}
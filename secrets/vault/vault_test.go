@@ -0,0 +1,81 @@
+package vault
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func NewMockServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		if r.URL.Path != "/v1/kv/data/payments/stripe" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Write([]byte(`{"data":{"data":{"key":"sk_live_secret","retries":3},"metadata":{"version":1}}}`))
+	}))
+}
+
+func TestSecret(t *testing.T) {
+	server := NewMockServer(t)
+	defer server.Close()
+
+	tests := map[string]string{
+		"payments/stripe.key":     "sk_live_secret",
+		"payments/stripe.retries": "3",
+	}
+
+	provider := New(server.URL, "token", "kv")
+
+	for input, expected := range tests {
+		t.Run(input, func(t *testing.T) {
+			result, err := provider.Secret(context.Background(), input)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if result != expected {
+				t.Fatalf("unexpected secret %s, expected %s", result, expected)
+			}
+		})
+	}
+}
+
+func TestSecretFail(t *testing.T) {
+	server := NewMockServer(t)
+	defer server.Close()
+
+	tests := map[string]*Provider{
+		"payments/stripe":         New(server.URL, "token", "kv"),
+		"payments/stripe.unknown": New(server.URL, "token", "kv"),
+		"payments/unknown.key":    New(server.URL, "token", "kv"),
+		"payments/stripe.key":     New(server.URL, "invalid", "kv"),
+	}
+
+	for input, provider := range tests {
+		t.Run(input, func(t *testing.T) {
+			_, err := provider.Secret(context.Background(), input)
+			if err == nil {
+				t.Fatal("unexpected pass")
+			}
+		})
+	}
+}
+
+func TestDefaultMount(t *testing.T) {
+	provider := New("http://localhost:8200/", "token", "")
+	if provider.Mount != DefaultMount {
+		t.Fatalf("unexpected mount %s", provider.Mount)
+	}
+
+	if provider.Address != "http://localhost:8200" {
+		t.Fatalf("unexpected address %s", provider.Address)
+	}
+}
@@ -26,6 +26,13 @@ func CheckManifestDuplicates(ctx context.Context, manifest *Manifest) error {
 		}
 	}
 
+	for _, proxy := range manifest.Proxy {
+		err := CheckReservedNodes(proxy.Name, proxy.Nodes)
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -42,5 +49,21 @@ func CheckFlowDuplicates(ctx context.Context, flow *Flow) error {
 		}
 	}
 
+	return CheckReservedNodes(flow.Name, flow.Nodes)
+}
+
+// ReservedResources represents the resources which could not be used as node names
+var ReservedResources = []string{EnvResource, SecretResource}
+
+// CheckReservedNodes checks whether the given nodes use a reserved resource as name
+func CheckReservedNodes(flow string, nodes []*Node) error {
+	for _, node := range nodes {
+		for _, reserved := range ReservedResources {
+			if node.Name == reserved {
+				return trace.New(trace.WithMessage("reserved resource name '%s' used as call in flow '%s'", node.Name, flow))
+			}
+		}
+	}
+
 	return nil
 }
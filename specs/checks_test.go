@@ -72,3 +72,34 @@ func TestDuplicateFlow(t *testing.T) {
 		}
 	}
 }
+
+func TestReservedNodes(t *testing.T) {
+	tests := []*Manifest{
+		{
+			Flows: []*Flow{
+				{
+					Name:  "first",
+					Nodes: []*Node{{Name: EnvResource}},
+				},
+			},
+		},
+		{
+			Proxy: []*Proxy{
+				{
+					Name:  "first",
+					Nodes: []*Node{{Name: SecretResource}},
+				},
+			},
+		},
+	}
+
+	for _, input := range tests {
+		ctx := context.Background()
+		ctx = logger.WithValue(ctx)
+
+		err := CheckManifestDuplicates(ctx, input)
+		if err == nil {
+			t.Fatal("unexpected pass", input)
+		}
+	}
+}
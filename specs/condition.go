@@ -0,0 +1,87 @@
+package specs
+
+import (
+	"context"
+
+	"github.com/jexia/maestro/logger"
+	"github.com/jexia/maestro/specs/trace"
+	"github.com/sirupsen/logrus"
+)
+
+// Available condition operators
+const (
+	OperatorEqual          = "=="
+	OperatorNotEqual       = "!="
+	OperatorGreater        = ">"
+	OperatorGreaterOrEqual = ">="
+	OperatorLess           = "<"
+	OperatorLessOrEqual    = "<="
+)
+
+// Operators represents all available condition operators.
+var Operators = []string{
+	OperatorEqual,
+	OperatorNotEqual,
+	OperatorGreaterOrEqual,
+	OperatorLessOrEqual,
+	OperatorGreater,
+	OperatorLess,
+}
+
+// Condition represents a expression which has to be met before a node is executed.
+// A condition is either a single boolean operand or a comparison of two operands.
+type Condition struct {
+	Expression string
+	Left       *Property
+	Operator   string
+	Right      *Property
+}
+
+// ParseCondition parses the given condition template.
+// Operands could reference properties, call custom defined functions or represent constant values.
+func ParseCondition(ctx context.Context, path string, functions CustomDefinedFunctions, value string) (*Condition, error) {
+	if !IsTemplate(value) {
+		return nil, trace.New(trace.WithMessage("invalid condition '%s' in '%s', expected a template", value, path))
+	}
+
+	content := GetTemplateContent(value)
+	logger.FromCtx(ctx, logger.Core).WithField("path", path).WithField("condition", content).Debug("Parsing condition template")
+
+	result := &Condition{
+		Expression: content,
+	}
+
+	expression, err := ParseExpression(path, functions, content)
+	if err != nil {
+		return nil, err
+	}
+
+	if !IsComparison(expression.Operator) {
+		result.Left = ExpressionProperty(path, expression)
+		return result, nil
+	}
+
+	result.Left = ExpressionProperty(path, expression.Operands[0])
+	result.Operator = expression.Operator
+	result.Right = ExpressionProperty(path, expression.Operands[1])
+
+	logger.FromCtx(ctx, logger.Core).WithFields(logrus.Fields{
+		"path":     path,
+		"left":     result.Left.Reference,
+		"operator": result.Operator,
+		"right":    result.Right.Reference,
+	}).Debug("Condition results in comparison")
+
+	return result, nil
+}
+
+// IsComparison checks whether the given operator represents a comparison
+func IsComparison(operator string) bool {
+	for _, available := range Operators {
+		if operator == available {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,92 @@
+package specs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jexia/maestro/logger"
+	"github.com/jexia/maestro/specs/types"
+)
+
+func TestParseCondition(t *testing.T) {
+	ctx := context.Background()
+	ctx = logger.WithValue(ctx)
+
+	type test struct {
+		operator string
+		left     Property
+		right    *Property
+	}
+
+	tests := map[string]test{
+		"{{ input:amount > 1000 }}": {
+			operator: OperatorGreater,
+			left:     Property{Path: "if", Reference: &PropertyReference{Resource: "input", Path: "amount"}},
+			right:    &Property{Path: "if", Type: types.TypeInt64, Default: int64(1000)},
+		},
+		"{{ input:ratio < 0.5 }}": {
+			operator: OperatorLess,
+			left:     Property{Path: "if", Reference: &PropertyReference{Resource: "input", Path: "ratio"}},
+			right:    &Property{Path: "if", Type: types.TypeDouble, Default: 0.5},
+		},
+		"{{ input:name == 'john' }}": {
+			operator: OperatorEqual,
+			left:     Property{Path: "if", Reference: &PropertyReference{Resource: "input", Path: "name"}},
+			right:    &Property{Path: "if", Type: types.TypeString, Default: "john"},
+		},
+		"{{ input:verified != true }}": {
+			operator: OperatorNotEqual,
+			left:     Property{Path: "if", Reference: &PropertyReference{Resource: "input", Path: "verified"}},
+			right:    &Property{Path: "if", Type: types.TypeBool, Default: true},
+		},
+		"{{ input:verified }}": {
+			left: Property{Path: "if", Reference: &PropertyReference{Resource: "input", Path: "verified"}},
+		},
+	}
+
+	for input, expected := range tests {
+		t.Run(input, func(t *testing.T) {
+			condition, err := ParseCondition(ctx, "if", nil, input)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if condition.Operator != expected.operator {
+				t.Errorf("unexpected operator '%s', expected '%s'", condition.Operator, expected.operator)
+			}
+
+			CompareProperties(t, *condition.Left, expected.left)
+
+			if expected.right == nil {
+				if condition.Right != nil {
+					t.Fatalf("unexpected right operand %+v", condition.Right)
+				}
+
+				return
+			}
+
+			CompareProperties(t, *condition.Right, *expected.right)
+		})
+	}
+}
+
+func TestParseConditionFail(t *testing.T) {
+	ctx := context.Background()
+	ctx = logger.WithValue(ctx)
+
+	tests := []string{
+		"input:amount > 1000",
+		"{{ input:amount > }}",
+		"{{ > 1000 }}",
+		"{{ }}",
+	}
+
+	for _, input := range tests {
+		t.Run(input, func(t *testing.T) {
+			_, err := ParseCondition(ctx, "if", nil, input)
+			if err == nil {
+				t.Fatal("expected condition to fail")
+			}
+		})
+	}
+}
@@ -17,6 +17,11 @@ func ResolveManifestDependencies(ctx context.Context, manifest *Manifest) error
 			return err
 		}
 
+		err = ResolveFlowCallDependencies(manifest, flow, make(map[string]FlowManager))
+		if err != nil {
+			return err
+		}
+
 		for _, call := range flow.Nodes {
 			err := ResolveCallDependencies(flow, call, make(map[string]*Node))
 			if err != nil {
@@ -31,6 +36,11 @@ func ResolveManifestDependencies(ctx context.Context, manifest *Manifest) error
 			return err
 		}
 
+		err = ResolveFlowCallDependencies(manifest, proxy, make(map[string]FlowManager))
+		if err != nil {
+			return err
+		}
+
 		for _, call := range proxy.Nodes {
 			err := ResolveCallDependencies(proxy, call, make(map[string]*Node))
 			if err != nil {
@@ -97,3 +107,34 @@ lookup:
 	delete(unresolved, node.GetName())
 	return nil
 }
+
+// ResolveFlowCallDependencies resolves the flows called inside the given flow manager and attempts to detect any circular calls
+func ResolveFlowCallDependencies(manifest *Manifest, manager FlowManager, unresolved map[string]FlowManager) error {
+	unresolved[manager.GetName()] = manager
+
+	for _, node := range manager.GetNodes() {
+		for _, call := range []*Call{node.Call, node.Rollback} {
+			if call == nil || call.GetService() != FlowService {
+				continue
+			}
+
+			_, unresolv := unresolved[call.GetMethod()]
+			if unresolv {
+				return fmt.Errorf("Circular flow call detected: %s.%s <-> %s", manager.GetName(), node.Name, call.GetMethod())
+			}
+
+			target := manifest.Flows.Get(call.GetMethod())
+			if target == nil {
+				continue
+			}
+
+			err := ResolveFlowCallDependencies(manifest, target, unresolved)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	delete(unresolved, manager.GetName())
+	return nil
+}
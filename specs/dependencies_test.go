@@ -259,3 +259,73 @@ func TestCallCircularDependenciesDetection(t *testing.T) {
 		}
 	}
 }
+
+func TestFlowCallCircularDependenciesDetection(t *testing.T) {
+	manifest := &Manifest{
+		Flows: []*Flow{
+			{
+				Name: "first",
+				Nodes: []*Node{
+					{
+						Name: "call",
+						Call: &Call{Service: FlowService, Method: "second"},
+					},
+				},
+			},
+			{
+				Name: "second",
+				Nodes: []*Node{
+					{
+						Name: "call",
+						Call: &Call{Service: FlowService, Method: "third"},
+					},
+				},
+			},
+			{
+				Name: "third",
+				Nodes: []*Node{
+					{
+						Name:     "call",
+						Rollback: &Call{Service: FlowService, Method: "first"},
+					},
+				},
+			},
+		},
+	}
+
+	for _, input := range manifest.Flows {
+		err := ResolveFlowCallDependencies(manifest, input, make(map[string]FlowManager))
+		if err == nil {
+			t.Fatalf("unexpected pass %s", input.Name)
+		}
+	}
+}
+
+func TestResolveFlowCallDependencies(t *testing.T) {
+	manifest := &Manifest{
+		Flows: []*Flow{
+			{
+				Name: "first",
+				Nodes: []*Node{
+					{
+						Name: "call",
+						Call: &Call{Service: FlowService, Method: "second"},
+					},
+					{
+						Name:     "repeated",
+						Call:     &Call{Service: FlowService, Method: "second"},
+						Rollback: &Call{Service: FlowService, Method: "second"},
+					},
+				},
+			},
+			{
+				Name: "second",
+			},
+		},
+	}
+
+	err := ResolveFlowCallDependencies(manifest, manifest.Flows[0], make(map[string]FlowManager))
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,769 @@
+package specs
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/jexia/maestro/specs/trace"
+	"github.com/jexia/maestro/specs/types"
+)
+
+// Available expression operators
+const (
+	OperatorAdd      = "+"
+	OperatorSubtract = "-"
+	OperatorMultiply = "*"
+	OperatorDivide   = "/"
+	OperatorModulo   = "%"
+	OperatorAnd      = "&&"
+	OperatorOr       = "||"
+	OperatorNot      = "!"
+	OperatorCoalesce = "??"
+	OperatorTernary  = "?"
+	OperatorElse     = ":"
+
+	// OperatorInterpolate concatenates the string representations of all operands.
+	// Interpolations are constructed out of values mixing literal text and templates.
+	OperatorInterpolate = "interpolate"
+)
+
+// ExpressionOperators represents all available expression operators.
+// Operators consisting of multiple characters are defined first to avoid partial matches.
+var ExpressionOperators = []string{
+	OperatorCoalesce,
+	OperatorAnd,
+	OperatorOr,
+	OperatorEqual,
+	OperatorNotEqual,
+	OperatorGreaterOrEqual,
+	OperatorLessOrEqual,
+	OperatorGreater,
+	OperatorLess,
+	OperatorAdd,
+	OperatorSubtract,
+	OperatorMultiply,
+	OperatorDivide,
+	OperatorModulo,
+	OperatorNot,
+	OperatorTernary,
+	OperatorElse,
+	"(",
+	")",
+}
+
+// precedence represents the binary operators ordered from the lowest to the highest precedence
+var precedence = [][]string{
+	{OperatorCoalesce},
+	{OperatorOr},
+	{OperatorAnd},
+	{OperatorEqual, OperatorNotEqual},
+	{OperatorGreater, OperatorGreaterOrEqual, OperatorLess, OperatorLessOrEqual},
+	{OperatorAdd, OperatorSubtract},
+	{OperatorMultiply, OperatorDivide, OperatorModulo},
+}
+
+// Expression represents a template expression.
+// A expression is either a single operand (constant value, reference or function) or a operator applied to its operands.
+// The ternary operator is applied to three operands: the condition, the value if true and the value if false.
+type Expression struct {
+	Raw      string
+	Operator string
+	Operands []*Expression
+	Property *Property
+	Type     types.Type
+}
+
+// Properties returns all operand properties inside the given expression
+func (expression *Expression) Properties() []*Property {
+	if expression == nil {
+		return nil
+	}
+
+	if expression.Property != nil {
+		return []*Property{expression.Property}
+	}
+
+	result := []*Property{}
+	for _, operand := range expression.Operands {
+		result = append(result, operand.Properties()...)
+	}
+
+	return result
+}
+
+// Clone returns a clone of the given expression
+func (expression *Expression) Clone() *Expression {
+	if expression == nil {
+		return nil
+	}
+
+	result := &Expression{
+		Raw:      expression.Raw,
+		Operator: expression.Operator,
+		Type:     expression.Type,
+	}
+
+	if expression.Property != nil {
+		result.Property = expression.Property.Clone(nil, expression.Property.Name, expression.Property.Path)
+	}
+
+	if expression.Operands != nil {
+		result.Operands = make([]*Expression, len(expression.Operands))
+		for index, operand := range expression.Operands {
+			result.Operands[index] = operand.Clone()
+		}
+	}
+
+	return result
+}
+
+// Evaluate evaluates the given expression.
+// The values of the operand properties are resolved through the given resolver.
+// Nil is returned when the operation could not be applied to the given operand values.
+func (expression *Expression) Evaluate(resolve func(*Property) interface{}) interface{} {
+	if expression.Property != nil {
+		return resolve(expression.Property)
+	}
+
+	operand := func(index int) interface{} {
+		return expression.Operands[index].Evaluate(resolve)
+	}
+
+	switch expression.Operator {
+	case OperatorInterpolate:
+		result := strings.Builder{}
+
+		for index := range expression.Operands {
+			value := operand(index)
+			if value == nil {
+				continue
+			}
+
+			result.WriteString(fmt.Sprint(value))
+		}
+
+		return result.String()
+	case OperatorTernary:
+		if condition, _ := operand(0).(bool); condition {
+			return operand(1)
+		}
+
+		return operand(2)
+	case OperatorCoalesce:
+		if value := operand(0); value != nil {
+			return value
+		}
+
+		return operand(1)
+	case OperatorAnd:
+		if left, _ := operand(0).(bool); !left {
+			return false
+		}
+
+		right, _ := operand(1).(bool)
+		return right
+	case OperatorOr:
+		if left, _ := operand(0).(bool); left {
+			return true
+		}
+
+		right, _ := operand(1).(bool)
+		return right
+	case OperatorNot:
+		value, _ := operand(0).(bool)
+		return !value
+	}
+
+	if IsComparison(expression.Operator) {
+		return Compare(expression.Operator, operand(0), operand(1))
+	}
+
+	if len(expression.Operands) == 1 {
+		return ConvertNumeric(Arithmetic(OperatorSubtract, int64(0), operand(0)), expression.Type)
+	}
+
+	left := operand(0)
+	right := operand(1)
+
+	if lstr, is := left.(string); is && expression.Operator == OperatorAdd {
+		rstr, is := right.(string)
+		if !is {
+			return nil
+		}
+
+		return lstr + rstr
+	}
+
+	return ConvertNumeric(Arithmetic(expression.Operator, left, right), expression.Type)
+}
+
+// Arithmetic applies the given arithmetic operator to the given numeric values.
+// Integer operands result in a int64 value, all other numeric operands result in a float64 value.
+// Nil is returned when one of the given values is not numeric or when dividing by zero.
+func Arithmetic(operator string, left interface{}, right interface{}) interface{} {
+	lint, lis := Integer(left)
+	rint, ris := Integer(right)
+
+	if lis && ris {
+		switch operator {
+		case OperatorAdd:
+			return lint + rint
+		case OperatorSubtract:
+			return lint - rint
+		case OperatorMultiply:
+			return lint * rint
+		case OperatorDivide:
+			if rint == 0 {
+				return nil
+			}
+
+			return lint / rint
+		case OperatorModulo:
+			if rint == 0 {
+				return nil
+			}
+
+			return lint % rint
+		}
+
+		return nil
+	}
+
+	lnum, lis := Numeric(left)
+	rnum, ris := Numeric(right)
+
+	if !lis || !ris {
+		return nil
+	}
+
+	switch operator {
+	case OperatorAdd:
+		return lnum + rnum
+	case OperatorSubtract:
+		return lnum - rnum
+	case OperatorMultiply:
+		return lnum * rnum
+	case OperatorDivide:
+		if rnum == 0 {
+			return nil
+		}
+
+		return lnum / rnum
+	case OperatorModulo:
+		if rnum == 0 {
+			return nil
+		}
+
+		return math.Mod(lnum, rnum)
+	}
+
+	return nil
+}
+
+// Compare compares the given values using the given operator.
+// Numeric values are compared as floats, strings are compared lexicographically.
+func Compare(operator string, left interface{}, right interface{}) bool {
+	if lnum, is := Numeric(left); is {
+		rnum, is := Numeric(right)
+		if !is {
+			return operator == OperatorNotEqual
+		}
+
+		switch operator {
+		case OperatorEqual:
+			return lnum == rnum
+		case OperatorNotEqual:
+			return lnum != rnum
+		case OperatorGreater:
+			return lnum > rnum
+		case OperatorGreaterOrEqual:
+			return lnum >= rnum
+		case OperatorLess:
+			return lnum < rnum
+		case OperatorLessOrEqual:
+			return lnum <= rnum
+		}
+
+		return false
+	}
+
+	if lstr, is := left.(string); is {
+		rstr, is := right.(string)
+		if !is {
+			return operator == OperatorNotEqual
+		}
+
+		switch operator {
+		case OperatorEqual:
+			return lstr == rstr
+		case OperatorNotEqual:
+			return lstr != rstr
+		case OperatorGreater:
+			return lstr > rstr
+		case OperatorGreaterOrEqual:
+			return lstr >= rstr
+		case OperatorLess:
+			return lstr < rstr
+		case OperatorLessOrEqual:
+			return lstr <= rstr
+		}
+
+		return false
+	}
+
+	switch operator {
+	case OperatorEqual:
+		return left == right
+	case OperatorNotEqual:
+		return left != right
+	}
+
+	return false
+}
+
+// Numeric attempts to convert the given value to a float
+func Numeric(value interface{}) (float64, bool) {
+	switch typed := value.(type) {
+	case float64:
+		return typed, true
+	case float32:
+		return float64(typed), true
+	case int:
+		return float64(typed), true
+	case int32:
+		return float64(typed), true
+	case int64:
+		return float64(typed), true
+	case uint32:
+		return float64(typed), true
+	case uint64:
+		return float64(typed), true
+	}
+
+	return 0, false
+}
+
+// Integer attempts to convert the given value to a int64.
+// False is returned for unsigned values exceeding the int64 range.
+func Integer(value interface{}) (int64, bool) {
+	switch typed := value.(type) {
+	case int:
+		return int64(typed), true
+	case int32:
+		return int64(typed), true
+	case int64:
+		return typed, true
+	case uint32:
+		return int64(typed), true
+	case uint64:
+		if typed > math.MaxInt64 {
+			return 0, false
+		}
+
+		return int64(typed), true
+	}
+
+	return 0, false
+}
+
+// ConvertNumeric converts the given numeric value to the Go type representing the given type.
+// The value is returned untouched when the given type is not numeric or when the value is not numeric.
+func ConvertNumeric(value interface{}, typed types.Type) interface{} {
+	num, is := Numeric(value)
+	if !is {
+		return value
+	}
+
+	if unsigned, is := value.(uint64); is && (typed == types.TypeUint64 || typed == types.TypeFixed64) {
+		return unsigned
+	}
+
+	integer, is := Integer(value)
+	if !is {
+		integer = int64(num)
+	}
+
+	switch typed {
+	case types.TypeDouble:
+		return num
+	case types.TypeFloat:
+		return float32(num)
+	case types.TypeInt64, types.TypeSint64, types.TypeSfixed64:
+		return integer
+	case types.TypeUint64, types.TypeFixed64:
+		return uint64(integer)
+	case types.TypeInt32, types.TypeSint32, types.TypeSfixed32:
+		return int32(integer)
+	case types.TypeUint32, types.TypeFixed32:
+		return uint32(integer)
+	}
+
+	return value
+}
+
+// ParseExpression parses the given template content as expression.
+// Operands are separated by operators, references containing a dash require the subtract operator to be surrounded by spaces.
+func ParseExpression(path string, functions CustomDefinedFunctions, content string) (*Expression, error) {
+	tokens, err := tokenize(content)
+	if err != nil {
+		return nil, trace.New(trace.WithMessage("invalid expression '%s' in '%s', %s", content, path, err))
+	}
+
+	parser := &parser{
+		content:   content,
+		path:      path,
+		functions: functions,
+		tokens:    tokens,
+	}
+
+	expression, err := parser.ternary()
+	if err != nil {
+		return nil, trace.New(trace.WithMessage("invalid expression '%s' in '%s', %s", content, path, err))
+	}
+
+	if parser.position < len(tokens) {
+		return nil, trace.New(trace.WithMessage("invalid expression '%s' in '%s', unexpected '%s'", content, path, tokens[parser.position].value))
+	}
+
+	return expression, nil
+}
+
+// ExpressionProperty returns the given expression as property.
+// The operand property is returned for expressions containing a single operand.
+func ExpressionProperty(path string, expression *Expression) *Property {
+	if expression.Property != nil {
+		return expression.Property
+	}
+
+	return &Property{
+		Path:       path,
+		Expression: expression,
+	}
+}
+
+// ParseOperand parses the given expression operand to a property.
+// Quoted strings, booleans and numbers are parsed as constant values.
+func ParseOperand(path string, functions CustomDefinedFunctions, operand string) (*Property, error) {
+	result := &Property{
+		Path: path,
+	}
+
+	if len(operand) > 1 && (operand[0] == '"' || operand[0] == '\'') && operand[len(operand)-1] == operand[0] {
+		result.Type = types.TypeString
+		result.Default = operand[1 : len(operand)-1]
+		return result, nil
+	}
+
+	if operand == "true" || operand == "false" {
+		result.Type = types.TypeBool
+		result.Default = operand == "true"
+		return result, nil
+	}
+
+	if value, err := strconv.ParseInt(operand, 10, 64); err == nil {
+		result.Type = types.TypeInt64
+		result.Default = value
+		return result, nil
+	}
+
+	if value, err := strconv.ParseFloat(operand, 64); err == nil {
+		result.Type = types.TypeDouble
+		result.Default = value
+		return result, nil
+	}
+
+	if FunctionPattern.MatchString(operand) {
+		return ParseFunction(path, functions, operand)
+	}
+
+	return ParseReference(path, operand), nil
+}
+
+const (
+	tokenOperand = iota
+	tokenOperator
+)
+
+type token struct {
+	kind  int
+	value string
+	start int
+	end   int
+}
+
+// tokenize splits the given expression content into operand and operator tokens
+func tokenize(content string) ([]token, error) {
+	tokens := []token{}
+
+	for index := 0; index < len(content); {
+		char := rune(content[index])
+
+		switch {
+		case unicode.IsSpace(char):
+			index++
+		case char == '"' || char == '\'':
+			end := strings.IndexRune(content[index+1:], char)
+			if end < 0 {
+				return nil, trace.New(trace.WithMessage("unterminated string"))
+			}
+
+			end += index + 2
+			tokens = append(tokens, token{kind: tokenOperand, value: content[index:end], start: index, end: end})
+			index = end
+		case unicode.IsDigit(char) || (char == '.' && index+1 < len(content) && unicode.IsDigit(rune(content[index+1]))):
+			end := index
+			for end < len(content) && (unicode.IsDigit(rune(content[end])) || content[end] == '.') {
+				end++
+			}
+
+			tokens = append(tokens, token{kind: tokenOperand, value: content[index:end], start: index, end: end})
+			index = end
+		case unicode.IsLetter(char) || char == '_':
+			end := index
+			for end < len(content) {
+				// Index selectors (ex: items[0] or items[*]) are part of the referenced path
+				if content[end] == '[' {
+					closing := strings.IndexByte(content[end:], ']')
+					if closing < 0 {
+						return nil, trace.New(trace.WithMessage("unterminated index"))
+					}
+
+					end += closing + 1
+					continue
+				}
+
+				if !IsOperandCharacter(rune(content[end])) {
+					break
+				}
+
+				end++
+			}
+
+			if end < len(content) && content[end] == '(' {
+				closing, err := ClosingParenthesis(content, end)
+				if err != nil {
+					return nil, err
+				}
+
+				end = closing + 1
+			}
+
+			tokens = append(tokens, token{kind: tokenOperand, value: content[index:end], start: index, end: end})
+			index = end
+		default:
+			operator := ""
+			for _, available := range ExpressionOperators {
+				if strings.HasPrefix(content[index:], available) {
+					operator = available
+					break
+				}
+			}
+
+			if operator == "" {
+				return nil, trace.New(trace.WithMessage("unexpected character '%c'", char))
+			}
+
+			tokens = append(tokens, token{kind: tokenOperator, value: operator, start: index, end: index + len(operator)})
+			index += len(operator)
+		}
+	}
+
+	return tokens, nil
+}
+
+// IsOperandCharacter checks whether the given character could be part of a reference or function name
+func IsOperandCharacter(char rune) bool {
+	switch char {
+	case '_', '.', ':', '-':
+		return true
+	}
+
+	return unicode.IsLetter(char) || unicode.IsDigit(char)
+}
+
+// ClosingParenthesis returns the index of the parenthesis closing the parenthesis at the given position.
+// Parentheses defined inside quoted strings are ignored.
+func ClosingParenthesis(content string, position int) (int, error) {
+	var quote rune
+	depth := 0
+
+	for index, char := range content[position:] {
+		if quote != 0 {
+			if char == quote {
+				quote = 0
+			}
+
+			continue
+		}
+
+		switch char {
+		case '"', '\'':
+			quote = char
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return position + index, nil
+			}
+		}
+	}
+
+	return 0, trace.New(trace.WithMessage("unterminated function call"))
+}
+
+// parser represents a recursive descent parser constructing a expression out of the given tokens
+type parser struct {
+	content   string
+	path      string
+	functions CustomDefinedFunctions
+	tokens    []token
+	position  int
+}
+
+// raw returns the expression content from the token at the given position until the last consumed token
+func (parser *parser) raw(from int) string {
+	return parser.content[parser.tokens[from].start:parser.tokens[parser.position-1].end]
+}
+
+// accept consumes the next token if it represents one of the given operators
+func (parser *parser) accept(operators ...string) string {
+	if parser.position >= len(parser.tokens) {
+		return ""
+	}
+
+	token := parser.tokens[parser.position]
+	if token.kind != tokenOperator {
+		return ""
+	}
+
+	for _, operator := range operators {
+		if token.value == operator {
+			parser.position++
+			return operator
+		}
+	}
+
+	return ""
+}
+
+func (parser *parser) ternary() (*Expression, error) {
+	from := parser.position
+	condition, err := parser.binary(0)
+	if err != nil {
+		return nil, err
+	}
+
+	if parser.accept(OperatorTernary) == "" {
+		return condition, nil
+	}
+
+	positive, err := parser.ternary()
+	if err != nil {
+		return nil, err
+	}
+
+	if parser.accept(OperatorElse) == "" {
+		return nil, trace.New(trace.WithMessage("expected '%s' inside ternary operation", OperatorElse))
+	}
+
+	negative, err := parser.ternary()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Expression{Raw: parser.raw(from), Operator: OperatorTernary, Operands: []*Expression{condition, positive, negative}}, nil
+}
+
+func (parser *parser) binary(level int) (*Expression, error) {
+	if level == len(precedence) {
+		return parser.unary()
+	}
+
+	from := parser.position
+	left, err := parser.binary(level + 1)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		operator := parser.accept(precedence[level]...)
+		if operator == "" {
+			return left, nil
+		}
+
+		right, err := parser.binary(level + 1)
+		if err != nil {
+			return nil, err
+		}
+
+		left = &Expression{Raw: parser.raw(from), Operator: operator, Operands: []*Expression{left, right}}
+	}
+}
+
+func (parser *parser) unary() (*Expression, error) {
+	from := parser.position
+	operator := parser.accept(OperatorNot, OperatorSubtract)
+	if operator == "" {
+		return parser.primary()
+	}
+
+	operand, err := parser.unary()
+	if err != nil {
+		return nil, err
+	}
+
+	// Negative numeric constants are folded into a single constant operand
+	if operator == OperatorSubtract && operand.Property != nil && operand.Property.Reference == nil {
+		switch value := operand.Property.Default.(type) {
+		case int64:
+			operand.Property.Default = -value
+			operand.Raw = parser.raw(from)
+			return operand, nil
+		case float64:
+			operand.Property.Default = -value
+			operand.Raw = parser.raw(from)
+			return operand, nil
+		}
+	}
+
+	return &Expression{Raw: parser.raw(from), Operator: operator, Operands: []*Expression{operand}}, nil
+}
+
+func (parser *parser) primary() (*Expression, error) {
+	if parser.position >= len(parser.tokens) {
+		return nil, trace.New(trace.WithMessage("expected a operand"))
+	}
+
+	from := parser.position
+	if parser.accept("(") != "" {
+		expression, err := parser.ternary()
+		if err != nil {
+			return nil, err
+		}
+
+		if parser.accept(")") == "" {
+			return nil, trace.New(trace.WithMessage("expected a closing parenthesis"))
+		}
+
+		expression.Raw = parser.raw(from)
+		return expression, nil
+	}
+
+	token := parser.tokens[parser.position]
+	if token.kind != tokenOperand {
+		return nil, trace.New(trace.WithMessage("expected a operand, got '%s'", token.value))
+	}
+
+	parser.position++
+
+	property, err := ParseOperand(parser.path, parser.functions, token.value)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Expression{Raw: token.value, Property: property, Type: property.Type}, nil
+}
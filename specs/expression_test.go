@@ -0,0 +1,258 @@
+package specs
+
+import (
+	"math"
+	"testing"
+
+	"github.com/jexia/maestro/specs/types"
+)
+
+func TestCompare(t *testing.T) {
+	type test struct {
+		operator string
+		left     interface{}
+		right    interface{}
+		expected bool
+	}
+
+	tests := map[string]test{
+		"int greater":        {OperatorGreater, int64(1500), int64(1000), true},
+		"mixed numeric":      {OperatorGreaterOrEqual, int32(10), float64(10), true},
+		"int less":           {OperatorLess, int64(1500), int64(1000), false},
+		"uint less or equal": {OperatorLessOrEqual, uint32(5), int64(5), true},
+		"string equal":       {OperatorEqual, "john", "john", true},
+		"string not equal":   {OperatorNotEqual, "john", "jane", true},
+		"bool equal":         {OperatorEqual, true, true, true},
+		"bool not equal":     {OperatorNotEqual, true, false, true},
+		"bool greater":       {OperatorGreater, true, false, false},
+		"nil equal":          {OperatorEqual, nil, int64(1), false},
+		"mismatch not equal": {OperatorNotEqual, "1", int64(1), true},
+		"mismatch greater":   {OperatorGreater, int64(2), "1", false},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			result := Compare(test.operator, test.left, test.right)
+			if result != test.expected {
+				t.Fatalf("unexpected result %t, expected %t", result, test.expected)
+			}
+		})
+	}
+}
+
+func TestParseExpression(t *testing.T) {
+	tests := map[string]string{
+		"input:nickname ?? input:name":           OperatorCoalesce,
+		"input:amount * 2 + 1":                   OperatorAdd,
+		"input:amount * (2 + 1)":                 OperatorMultiply,
+		"input:amount > 10 && input:verified":    OperatorAnd,
+		"!input:verified || input:admin":         OperatorOr,
+		"input:verified ? 'yes' : 'no'":          OperatorTernary,
+		"input:first + ' ' + input:last":         OperatorAdd,
+		"input:amount - -1":                      OperatorSubtract,
+		"input:name == 'a > b'":                  OperatorEqual,
+		"(input:amount % 2) == 0 ? input:a : 10": OperatorTernary,
+	}
+
+	for input, expected := range tests {
+		t.Run(input, func(t *testing.T) {
+			expression, err := ParseExpression("", nil, input)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if expression.Operator != expected {
+				t.Fatalf("unexpected operator '%s', expected '%s'", expression.Operator, expected)
+			}
+
+			if expression.Raw != input {
+				t.Fatalf("unexpected raw expression '%s', expected '%s'", expression.Raw, input)
+			}
+		})
+	}
+}
+
+func TestParseExpressionOperand(t *testing.T) {
+	tests := map[string]Property{
+		"input:message":       {Reference: &PropertyReference{Resource: "input", Path: "message"}},
+		"input:items[-1].id":  {Reference: &PropertyReference{Resource: "input", Path: "items[-1].id"}},
+		"input:items[*].name": {Reference: &PropertyReference{Resource: "input", Path: "items[*].name"}},
+		"'message'":           {Type: types.TypeString, Default: "message"},
+		"\"message\"":         {Type: types.TypeString, Default: "message"},
+		"true":                {Type: types.TypeBool, Default: true},
+		"10":                  {Type: types.TypeInt64, Default: int64(10)},
+		"-10":                 {Type: types.TypeInt64, Default: int64(-10)},
+		"0.5":                 {Type: types.TypeDouble, Default: 0.5},
+		"(10)":                {Type: types.TypeInt64, Default: int64(10)},
+	}
+
+	for input, expected := range tests {
+		t.Run(input, func(t *testing.T) {
+			expression, err := ParseExpression("", nil, input)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if expression.Property == nil {
+				t.Fatal("expected a single operand")
+			}
+
+			CompareProperties(t, *expression.Property, expected)
+		})
+	}
+}
+
+func TestParseExpressionFail(t *testing.T) {
+	tests := []string{
+		"",
+		"input:amount +",
+		"* 10",
+		"(input:amount + 1",
+		"input:amount + 1)",
+		"input:verified ? 1",
+		"input:name == 'john",
+		"input:amount # 1",
+		"input:amount input:name",
+		"input:items[0",
+	}
+
+	for _, input := range tests {
+		t.Run(input, func(t *testing.T) {
+			_, err := ParseExpression("", nil, input)
+			if err == nil {
+				t.Fatal("expected expression to fail")
+			}
+		})
+	}
+}
+
+func TestEvaluateExpression(t *testing.T) {
+	values := map[string]interface{}{
+		"amount":   int32(10),
+		"ratio":    0.5,
+		"name":     "john",
+		"verified": true,
+	}
+
+	resolve := func(property *Property) interface{} {
+		if property.Reference == nil {
+			return property.Default
+		}
+
+		return values[property.Reference.Path]
+	}
+
+	type test struct {
+		typed    types.Type
+		expected interface{}
+	}
+
+	tests := map[string]test{
+		"input:amount * 2 + 1":                    {types.TypeInt32, int32(21)},
+		"input:amount / 4":                        {types.TypeInt32, int32(2)},
+		"input:amount % 4":                        {types.TypeInt32, int32(2)},
+		"input:amount / 0":                        {types.TypeInt32, nil},
+		"input:ratio * 3":                         {types.TypeDouble, 1.5},
+		"-input:amount":                           {types.TypeInt32, int32(-10)},
+		"input:name + ' doe'":                     {types.TypeString, "john doe"},
+		"input:nickname ?? input:name":            {types.TypeString, "john"},
+		"input:name ?? 'unknown'":                 {types.TypeString, "john"},
+		"input:amount > 5 && input:verified":      {types.TypeBool, true},
+		"input:amount > 50 || !input:verified":    {types.TypeBool, false},
+		"input:verified ? 'verified' : 'unknown'": {types.TypeString, "verified"},
+		"input:amount >= 10 ? input:amount : 10":  {types.TypeInt32, int32(10)},
+		"input:name == 'john' && input:ratio < 1": {types.TypeBool, true},
+		"input:unknown + 1":                       {types.TypeInt32, nil},
+		"input:name + input:amount":               {types.TypeString, nil},
+		"(input:amount + 2) * (input:amount - 2)": {types.TypeInt32, int32(96)},
+	}
+
+	for input, test := range tests {
+		t.Run(input, func(t *testing.T) {
+			expression, err := ParseExpression("", nil, input)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expression.Type = test.typed
+
+			result := expression.Evaluate(resolve)
+			if result != test.expected {
+				t.Fatalf("unexpected result %v (%T), expected %v (%T)", result, result, test.expected, test.expected)
+			}
+		})
+	}
+}
+
+func TestExpressionProperties(t *testing.T) {
+	expression, err := ParseExpression("", nil, "input:a ?? input:b ?? 'c'")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	properties := expression.Properties()
+	if len(properties) != 3 {
+		t.Fatalf("unexpected properties %d, expected %d", len(properties), 3)
+	}
+
+	clone := expression.Clone()
+	if len(clone.Properties()) != 3 || clone.Properties()[0] == properties[0] {
+		t.Fatal("unexpected clone, expected a deep copy of the expression operands")
+	}
+}
+
+func TestConvertNumeric(t *testing.T) {
+	tests := map[types.Type]interface{}{
+		types.TypeDouble: float64(10),
+		types.TypeFloat:  float32(10),
+		types.TypeInt64:  int64(10),
+		types.TypeUint64: uint64(10),
+		types.TypeInt32:  int32(10),
+		types.TypeUint32: uint32(10),
+		types.TypeString: int64(10),
+	}
+
+	for typed, expected := range tests {
+		result := ConvertNumeric(int64(10), typed)
+		if result != expected {
+			t.Errorf("unexpected result %v (%T) for %s, expected %v (%T)", result, result, typed, expected, expected)
+		}
+	}
+}
+
+func TestInteger(t *testing.T) {
+	type test struct {
+		value    interface{}
+		expected int64
+		is       bool
+	}
+
+	tests := map[string]test{
+		"int32":           {int32(10), 10, true},
+		"uint64":          {uint64(10), 10, true},
+		"uint64 max int":  {uint64(math.MaxInt64), math.MaxInt64, true},
+		"uint64 overflow": {uint64(math.MaxUint64), 0, false},
+		"float":           {float64(10), 0, false},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			result, is := Integer(test.value)
+			if is != test.is || result != test.expected {
+				t.Fatalf("unexpected result %d (%t), expected %d (%t)", result, is, test.expected, test.is)
+			}
+		})
+	}
+}
+
+func TestArithmeticUnsignedOverflow(t *testing.T) {
+	result := Arithmetic(OperatorAdd, uint64(math.MaxUint64), int64(1))
+	if result != float64(math.MaxUint64)+1 {
+		t.Fatalf("unexpected result %v (%T), expected a float64 fallback", result, result)
+	}
+
+	converted := ConvertNumeric(uint64(math.MaxUint64), types.TypeUint64)
+	if converted != uint64(math.MaxUint64) {
+		t.Fatalf("unexpected converted value %v, expected %d", converted, uint64(math.MaxUint64))
+	}
+}
@@ -1,11 +1,24 @@
 package lookup
 
 import (
+	"strconv"
+	"strings"
+
 	"github.com/jexia/maestro/specs"
+	"github.com/jexia/maestro/specs/trace"
+	"github.com/jexia/maestro/specs/types"
 )
 
-// SelfRef represents the syntax used to reference the entire object
-const SelfRef = "."
+const (
+	// SelfRef represents the syntax used to reference the entire object
+	SelfRef = "."
+	// IndexOpen represents the opening tag of a index selector
+	IndexOpen = "["
+	// IndexClose represents the closing tag of a index selector
+	IndexClose = "]"
+	// Wildcard represents the index selecting all items of a repeated property
+	Wildcard = "*"
+)
 
 // ReferenceMap holds the resource references and their representing parameter map
 type ReferenceMap map[string]PathLookup
@@ -66,6 +79,7 @@ func GetNextResource(flow specs.FlowManager, breakpoint string) string {
 
 // GetAvailableResources fetches the available resources able to be referenced
 // until the given breakpoint (call.Name) has been reached.
+// All resources including the flow error are available when the error resource is given as breakpoint.
 func GetAvailableResources(flow specs.FlowManager, breakpoint string) map[string]ReferenceMap {
 	references := make(map[string]ReferenceMap, len(flow.GetNodes())+1)
 
@@ -91,9 +105,26 @@ func GetAvailableResources(flow specs.FlowManager, breakpoint string) map[string
 
 		if node.Call != nil {
 			if node.Call.Response != nil {
+				if node.Foreach != nil {
+					references[node.Name][specs.ResourceResponse] = RepeatedLookup(node.Call.Response.Property)
+					continue
+				}
+
 				references[node.Name][specs.ResourceResponse] = ParameterMapLookup(node.Call.Response.Property)
 				references[node.Name][specs.ResourceHeader] = HeaderLookup(node.Call.Response.Header)
 			}
+
+			references[node.Name][specs.ResourceStatus] = StatusLookup()
+
+			if node.Call.Error != nil {
+				references[node.Name][specs.ResourceError] = ParameterMapLookup(node.Call.Error.Property)
+			}
+		}
+	}
+
+	if breakpoint == specs.ErrorResource {
+		references[specs.ErrorResource] = ReferenceMap{
+			specs.ResourceResponse: ErrorLookup(),
 		}
 	}
 
@@ -125,7 +156,129 @@ func GetReference(path string, prop string, references ReferenceMap) *specs.Prop
 		return nil
 	}
 
-	return lookup(path)
+	return SelectorLookup(lookup)(path)
+}
+
+// Selector represents a index or wildcard selector of a repeated property inside a reference path
+type Selector struct {
+	Path     string
+	Index    int
+	Wildcard bool
+}
+
+// HasSelectors checks whether the given path contains index or wildcard selectors
+func HasSelectors(path string) bool {
+	return strings.Contains(path, IndexOpen)
+}
+
+// ParseSelectors parses the index (ex: items[0] or items[-1]) and wildcard (ex: items[*]) selectors inside the given path.
+// The path without selectors is returned together with the selectors in the order in which they are defined.
+// The path of each selector represents the path of the selected repeated property (ex: orders.lines).
+func ParseSelectors(path string) (string, []*Selector, error) {
+	base := strings.Builder{}
+	selectors := []*Selector{}
+
+	for index := 0; index < len(path); index++ {
+		if path[index] != IndexOpen[0] {
+			if path[index] == IndexClose[0] {
+				return "", nil, trace.New(trace.WithMessage("unexpected '%s' in '%s'", IndexClose, path))
+			}
+
+			base.WriteByte(path[index])
+			continue
+		}
+
+		current := base.String()
+		if current == "" || strings.HasSuffix(current, specs.PathDelimiter) {
+			return "", nil, trace.New(trace.WithMessage("unexpected index in '%s', indexes could only follow a property", path))
+		}
+
+		closing := strings.Index(path[index:], IndexClose)
+		if closing < 0 {
+			return "", nil, trace.New(trace.WithMessage("unterminated index in '%s'", path))
+		}
+
+		value := path[index+1 : index+closing]
+		selector := &Selector{
+			Path: current,
+		}
+
+		if value == Wildcard {
+			selector.Wildcard = true
+		} else {
+			position, err := strconv.Atoi(value)
+			if err != nil {
+				return "", nil, trace.New(trace.WithMessage("invalid index '%s' in '%s', expected a integer or '%s'", value, path, Wildcard))
+			}
+
+			selector.Index = position
+		}
+
+		selectors = append(selectors, selector)
+		index += closing
+
+		if index+1 < len(path) && string(path[index+1]) != specs.PathDelimiter {
+			return "", nil, trace.New(trace.WithMessage("unexpected '%c' in '%s', indexes could only be followed by a path", path[index+1], path))
+		}
+	}
+
+	return base.String(), selectors, nil
+}
+
+// StripSelectors returns the given path without any index or wildcard selectors
+func StripSelectors(path string) string {
+	if !HasSelectors(path) {
+		return path
+	}
+
+	base, _, err := ParseSelectors(path)
+	if err != nil {
+		return path
+	}
+
+	return base
+}
+
+// SelectorLookup resolves the index and wildcard selectors inside the given path before looking up the property.
+// All selected properties have to be repeated. A single item is selected through a (negative) index
+// while a wildcard selects the given property of all items and results in a repeated property.
+func SelectorLookup(lookup PathLookup) PathLookup {
+	return func(path string) *specs.Property {
+		if !HasSelectors(path) {
+			return lookup(path)
+		}
+
+		base, selectors, err := ParseSelectors(path)
+		if err != nil {
+			return nil
+		}
+
+		wildcard := false
+
+		for _, selector := range selectors {
+			repeated := lookup(selector.Path)
+			if repeated == nil || repeated.Label != types.LabelRepeated {
+				return nil
+			}
+
+			wildcard = wildcard || selector.Wildcard
+		}
+
+		property := lookup(base)
+		if property == nil {
+			return nil
+		}
+
+		result := *property
+
+		if wildcard {
+			result.Label = types.LabelRepeated
+		} else if base == selectors[len(selectors)-1].Path {
+			result.Label = types.LabelOptional
+		}
+
+		return &result
+	}
 }
 
 // HeaderLookup attempts to lookup the given path inside the header
@@ -141,6 +294,48 @@ func HeaderLookup(header specs.Header) PathLookup {
 	}
 }
 
+// StatusLookup returns the status code property of a resource.
+// The status code could only be referenced as a whole.
+func StatusLookup() PathLookup {
+	return func(path string) *specs.Property {
+		if path != "" {
+			return nil
+		}
+
+		return &specs.Property{
+			Path:  path,
+			Type:  types.TypeInt32,
+			Label: types.LabelOptional,
+		}
+	}
+}
+
+// ErrorProperties represents the properties of a flow error available inside the error resource
+var ErrorProperties = map[string]types.Type{
+	"code":      types.TypeString,
+	"message":   types.TypeString,
+	"node":      types.TypeString,
+	"status":    types.TypeInt32,
+	"retryable": types.TypeBool,
+}
+
+// ErrorLookup attempts to lookup the given path inside the flow error properties
+func ErrorLookup() PathLookup {
+	return func(path string) *specs.Property {
+		typed, has := ErrorProperties[path]
+		if !has {
+			return nil
+		}
+
+		return &specs.Property{
+			Name:  path,
+			Path:  path,
+			Type:  typed,
+			Label: types.LabelOptional,
+		}
+	}
+}
+
 // ParameterMapLookup attempts to lookup the given path inside the params collection
 func ParameterMapLookup(param *specs.Property) PathLookup {
 	return func(path string) *specs.Property {
@@ -166,3 +361,18 @@ func ParameterMapLookup(param *specs.Property) PathLookup {
 		return nil
 	}
 }
+
+// RepeatedLookup returns the given param as a repeated property when the entire object is referenced.
+// The nested properties of a repeated object could not be referenced individually.
+func RepeatedLookup(param *specs.Property) PathLookup {
+	return func(path string) *specs.Property {
+		if path != SelfRef {
+			return nil
+		}
+
+		result := *param
+		result.Label = types.LabelRepeated
+
+		return &result
+	}
+}
@@ -351,6 +351,13 @@ func TestGetAvailableResources(t *testing.T) {
 			result := GetAvailableResources(flow, "output")
 			return expected, result
 		},
+		"error": func() ([]string, map[string]ReferenceMap) {
+			flow := NewMockFlow("first")
+			expected := []string{"input", "first", "second", "third", "error"}
+
+			result := GetAvailableResources(flow, "error")
+			return expected, result
+		},
 	}
 
 	for key, test := range tests {
@@ -370,6 +377,27 @@ func TestGetAvailableResources(t *testing.T) {
 	}
 }
 
+func TestErrorLookup(t *testing.T) {
+	lookup := ErrorLookup()
+
+	for path, expected := range ErrorProperties {
+		t.Run(path, func(t *testing.T) {
+			result := lookup(path)
+			if result == nil {
+				t.Fatal("unexpected empty result")
+			}
+
+			if result.Type != expected {
+				t.Fatalf("unexpected type %s, expected %s", result.Type, expected)
+			}
+		})
+	}
+
+	if lookup("unknown") != nil {
+		t.Fatal("unexpected result for unknown error property")
+	}
+}
+
 func TestSkipMissingParameters(t *testing.T) {
 	flow := NewMockFlow("first")
 
@@ -470,3 +498,177 @@ func TestGetResourceReference(t *testing.T) {
 		})
 	}
 }
+
+func TestRepeatedLookup(t *testing.T) {
+	param := &specs.Property{
+		Path:  "",
+		Type:  types.TypeMessage,
+		Label: types.LabelOptional,
+		Nested: map[string]*specs.Property{
+			"message": {Name: "message", Path: "message", Type: types.TypeString, Label: types.LabelOptional},
+		},
+	}
+
+	result := RepeatedLookup(param)(SelfRef)
+	if result == nil {
+		t.Fatal("unexpected empty result")
+	}
+
+	if result.Label != types.LabelRepeated {
+		t.Fatalf("unexpected label %s, expected %s", result.Label, types.LabelRepeated)
+	}
+
+	if param.Label != types.LabelOptional {
+		t.Fatal("the given param has been modified")
+	}
+
+	if RepeatedLookup(param)("message") != nil {
+		t.Fatal("unexpected nested property result")
+	}
+}
+
+func TestParseSelectors(t *testing.T) {
+	type expected struct {
+		base      string
+		selectors []*Selector
+	}
+
+	tests := map[string]expected{
+		"items[0]": {
+			base:      "items",
+			selectors: []*Selector{{Path: "items", Index: 0}},
+		},
+		"items[-1].id": {
+			base:      "items.id",
+			selectors: []*Selector{{Path: "items", Index: -1}},
+		},
+		"items[*].id": {
+			base:      "items.id",
+			selectors: []*Selector{{Path: "items", Wildcard: true}},
+		},
+		"orders[1].lines[*].sku": {
+			base: "orders.lines.sku",
+			selectors: []*Selector{
+				{Path: "orders", Index: 1},
+				{Path: "orders.lines", Wildcard: true},
+			},
+		},
+	}
+
+	for input, expected := range tests {
+		t.Run(input, func(t *testing.T) {
+			base, selectors, err := ParseSelectors(input)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if base != expected.base {
+				t.Fatalf("unexpected base %s, expected %s", base, expected.base)
+			}
+
+			if len(selectors) != len(expected.selectors) {
+				t.Fatalf("unexpected selectors %+v, expected %+v", selectors, expected.selectors)
+			}
+
+			for index, selector := range selectors {
+				if *selector != *expected.selectors[index] {
+					t.Errorf("unexpected selector %+v, expected %+v", selector, expected.selectors[index])
+				}
+			}
+		})
+	}
+}
+
+func TestParseSelectorsFail(t *testing.T) {
+	tests := []string{
+		"[0]",
+		"items.[0]",
+		"items[0",
+		"items[]",
+		"items[a]",
+		"items[0]id",
+		"items[0][1]",
+		"items]",
+	}
+
+	for _, input := range tests {
+		t.Run(input, func(t *testing.T) {
+			_, _, err := ParseSelectors(input)
+			if err == nil {
+				t.Fatal("unexpected pass")
+			}
+		})
+	}
+}
+
+func TestStripSelectors(t *testing.T) {
+	tests := map[string]string{
+		"items":                  "items",
+		"items[0]":               "items",
+		"orders[-1].lines[*].id": "orders.lines.id",
+	}
+
+	for input, expected := range tests {
+		result := StripSelectors(input)
+		if result != expected {
+			t.Errorf("unexpected result %s, expected %s", result, expected)
+		}
+	}
+}
+
+func TestSelectorLookup(t *testing.T) {
+	param := &specs.Property{
+		Path:  "",
+		Type:  types.TypeMessage,
+		Label: types.LabelOptional,
+		Nested: map[string]*specs.Property{
+			"name": {Name: "name", Path: "name", Type: types.TypeString, Label: types.LabelOptional},
+			"items": {
+				Name:  "items",
+				Path:  "items",
+				Type:  types.TypeMessage,
+				Label: types.LabelRepeated,
+				Nested: map[string]*specs.Property{
+					"id": {Name: "id", Path: "items.id", Type: types.TypeInt32, Label: types.LabelOptional},
+				},
+			},
+		},
+	}
+
+	type expected struct {
+		path  string
+		typed types.Type
+		label types.Label
+	}
+
+	tests := map[string]expected{
+		"items[0]":    {path: "items", typed: types.TypeMessage, label: types.LabelOptional},
+		"items[-1]":   {path: "items", typed: types.TypeMessage, label: types.LabelOptional},
+		"items[*]":    {path: "items", typed: types.TypeMessage, label: types.LabelRepeated},
+		"items[0].id": {path: "items.id", typed: types.TypeInt32, label: types.LabelOptional},
+		"items[*].id": {path: "items.id", typed: types.TypeInt32, label: types.LabelRepeated},
+	}
+
+	for input, expected := range tests {
+		t.Run(input, func(t *testing.T) {
+			result := SelectorLookup(ParameterMapLookup(param))(input)
+			if result == nil {
+				t.Fatal("unexpected empty result")
+			}
+
+			if result.Path != expected.path || result.Type != expected.typed || result.Label != expected.label {
+				t.Fatalf("unexpected result %+v, expected %+v", result, expected)
+			}
+		})
+	}
+
+	if param.Nested["items"].Label != types.LabelRepeated {
+		t.Fatal("the given param has been modified")
+	}
+
+	for _, input := range []string{"name[0]", "items[0].unknown", "items[a]"} {
+		if SelectorLookup(ParameterMapLookup(param))(input) != nil {
+			t.Errorf("unexpected result for %s", input)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package specs
 
 import (
 	"context"
+	"time"
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/jexia/maestro/schema"
@@ -19,6 +20,9 @@ type FlowManager interface {
 	GetInput() *ParameterMap
 	GetOutput() *ParameterMap
 	GetForward() *Call
+	GetTimeout() time.Duration
+	GetBulkhead() *Bulkhead
+	GetOnError() *OnError
 }
 
 // Flows represents a collection of flows
@@ -103,9 +107,12 @@ func (manifest *Manifest) Merge(incoming *Manifest) {
 type Flow struct {
 	Name      string
 	DependsOn map[string]*Flow
+	Timeout   time.Duration
+	Bulkhead  *Bulkhead
 	Input     *ParameterMap
 	Nodes     []*Node
 	Output    *ParameterMap
+	OnError   *OnError
 }
 
 // GetName returns the flow name
@@ -138,6 +145,47 @@ func (flow *Flow) GetForward() *Call {
 	return nil
 }
 
+// GetTimeout returns the maximum duration of a single flow execution
+func (flow *Flow) GetTimeout() time.Duration {
+	return flow.Timeout
+}
+
+// GetBulkhead returns the concurrency limits of the given flow
+func (flow *Flow) GetBulkhead() *Bulkhead {
+	return flow.Bulkhead
+}
+
+// GetOnError returns the error response of the given flow
+func (flow *Flow) GetOnError() *OnError {
+	return flow.OnError
+}
+
+// OnError represents the error response returned to the caller once a flow failed.
+// The error response is able to reference the failing node, error code and message through the error resource.
+// The status code of the error response could be mapped per error code.
+type OnError struct {
+	Response *ParameterMap
+	Status   map[string]int
+}
+
+// GetStatus returns the mapped status code for the given error code.
+// Zero is returned if no status code has been mapped.
+func (onError *OnError) GetStatus(code string) int {
+	if onError == nil {
+		return 0
+	}
+
+	return onError.Status[code]
+}
+
+// Bulkhead represents the concurrency limits of a flow.
+// Executions exceeding the maximum concurrency are queued until the queue is full or the queue timeout has been exceeded.
+type Bulkhead struct {
+	MaxConcurrency int
+	MaxQueue       int
+	QueueTimeout   time.Duration
+}
+
 // Endpoint exposes a flow. Endpoints are not parsed by Maestro and have custom implementations in each caller.
 // The name of the endpoint represents the flow which should be executed.
 type Endpoint struct {
@@ -161,6 +209,7 @@ type PrepareCustomFunction func(path string, args ...*Property) (*Property, erro
 
 // HandleCustomFunction executes the function and passes the expected types as interface{}.
 // The expected property type should always be returned.
+// The values of the property arguments are passed in the order in which they are defined.
 type HandleCustomFunction func(args ...interface{}) interface{}
 
 // PropertyReference represents a mustach template reference
@@ -186,30 +235,42 @@ func (reference *PropertyReference) Clone() *PropertyReference {
 // Property represents a value property.
 // A value property could contain a constant value or a value reference.
 type Property struct {
-	Name      string
-	Path      string
-	Default   interface{}
-	Type      types.Type
-	Label     types.Label
-	Reference *PropertyReference
-	Nested    map[string]*Property
-	Expr      hcl.Expression // TODO: marked for removal
-	Function  HandleCustomFunction
-	Desciptor schema.Property
+	Name       string
+	Path       string
+	Default    interface{}
+	Type       types.Type
+	Label      types.Label
+	Reference  *PropertyReference
+	Expression *Expression
+	Nested     map[string]*Property
+	Expr       hcl.Expression // TODO: marked for removal
+	Function   HandleCustomFunction
+	Prepare    PrepareCustomFunction
+	Arguments  []*Property
+	Desciptor  schema.Property
 }
 
 // Clone returns a clone of the property
 func (property *Property) Clone(reference *PropertyReference, name string, path string) *Property {
 	result := &Property{
-		Name:      name,
-		Path:      path,
-		Reference: reference,
-		Default:   property.Default,
-		Type:      property.Type,
-		Label:     property.Label,
-		Expr:      property.Expr,
-		Function:  property.Function,
-		Desciptor: property.Desciptor,
+		Name:       name,
+		Path:       path,
+		Reference:  reference,
+		Expression: property.Expression.Clone(),
+		Default:    property.Default,
+		Type:       property.Type,
+		Label:      property.Label,
+		Expr:       property.Expr,
+		Function:   property.Function,
+		Prepare:    property.Prepare,
+		Desciptor:  property.Desciptor,
+	}
+
+	if property.Arguments != nil {
+		result.Arguments = make([]*Property, len(property.Arguments))
+		for index, argument := range property.Arguments {
+			result.Arguments[index] = argument.Clone(nil, argument.Name, argument.Path)
+		}
 	}
 
 	if property.Reference != nil {
@@ -226,7 +287,7 @@ func (property *Property) Clone(reference *PropertyReference, name string, path
 			for key, nested := range property.Nested {
 				ref := &PropertyReference{
 					Resource: result.Reference.Resource,
-					Path:     JoinPath(result.Path, key),
+					Path:     JoinPath(result.Reference.Path, key),
 				}
 
 				result.Nested[key] = nested.Clone(ref, key, JoinPath(path, key))
@@ -245,18 +306,33 @@ type ParameterMap struct {
 	Property *Property
 }
 
+// NodeTypeAsync represents the node type of nodes which are dispatched without blocking the flow execution.
+// Async nodes could not be referenced by other resources and their errors do not trigger a rollback.
+const NodeTypeAsync = "async"
+
+// OnErrorContinue represents the node error mode of nodes whose failure does not abort the flow.
+// Failed nodes are skipped, references to their resources fall back to their default values.
+const OnErrorContinue = "continue"
+
 // Node represents a point inside a given flow where a request or rollback could be preformed.
 // Nodes could be executed synchronously or asynchronously.
 // All calls are referencing a service method, the service should match the alias defined inside the service.
 // The request and response proto messages are used for type definitions.
 // A call could contain the request headers, request body, rollback, and the execution type.
 type Node struct {
-	Name       string
-	DependsOn  map[string]*Node
-	Type       string
-	Call       *Call
-	Rollback   *Call
-	Descriptor schema.Method
+	Name          string
+	DependsOn     map[string]*Node
+	Type          string
+	OnError       string
+	Timeout       time.Duration
+	Condition     *Condition
+	Foreach       *Foreach
+	Call          *Call
+	Rollback      *Call
+	Retry         *Retry
+	RollbackRetry *Retry
+	Cache         *Cache
+	Descriptor    schema.Method
 }
 
 // GetName returns the call name
@@ -264,17 +340,81 @@ func (call *Node) GetName() string {
 	return call.Name
 }
 
+// IsOptional returns whether the failure of the node does not abort the flow
+func (call *Node) IsOptional() bool {
+	return call.OnError == OnErrorContinue
+}
+
+// IsAsync returns whether the node is dispatched without blocking the flow execution
+func (call *Node) IsAsync() bool {
+	return call.Type == NodeTypeAsync
+}
+
 // GetDescriptor returns the call descriptor
 func (call *Node) GetDescriptor() schema.Method {
 	return call.Descriptor
 }
 
+// Foreach represents a repeated property over which the node call is executed for each item.
+// Items are called concurrently, the amount of concurrent calls is bounded by the configured parallelism.
+// Items are called one at a time when the parallelism is zero or negative.
+type Foreach struct {
+	Property *Property
+	Parallel int
+}
+
+// Cache represents the caching policy of a node call.
+// Decoded responses are cached for the configured TTL using the values of the key properties as cache key.
+// The references inside the call request are used as cache key when no key properties have been defined.
+type Cache struct {
+	TTL        time.Duration
+	Key        []*Property
+	MaxEntries int
+}
+
+const (
+	// BackoffConstant waits the configured delay in between each attempt
+	BackoffConstant = "constant"
+	// BackoffLinear increases the delay linearly with each attempt
+	BackoffLinear = "linear"
+	// BackoffExponential doubles the delay with each attempt
+	BackoffExponential = "exponential"
+
+	// RetryAll retries any thrown error
+	RetryAll = "all"
+	// RetryTimeout retries errors caused by a exceeded deadline or timeout
+	RetryTimeout = "timeout"
+	// RetryConnection retries errors caused by a failing connection
+	RetryConnection = "connection"
+	// RetryUpstream retries upstream errors which have been marked as retryable by the transport (ex: HTTP 503)
+	RetryUpstream = "upstream"
+)
+
+// Retry represents the retry policy of a node call.
+// Failed calls are retried until the maximum amount of attempts has been reached.
+// Only errors matching one of the retryable error classes are retried.
+type Retry struct {
+	Attempts int
+	Backoff  string
+	Delay    time.Duration
+	MaxDelay time.Duration
+	Jitter   bool
+	Errors   []string
+}
+
+// ErrorSchemaOption represents the service or method option key defining the schema object used to decode error responses
+const ErrorSchemaOption = "error_schema"
+
+// FlowService represents the reserved service name used to call other flows in-process
+const FlowService = "flow"
+
 // Call represents a call which is executed during runtime
 type Call struct {
 	Service    string
 	Method     string
 	Request    *ParameterMap
 	Response   *ParameterMap
+	Error      *ParameterMap
 	Descriptor schema.Method
 }
 
@@ -288,6 +428,11 @@ func (call *Call) GetResponse() *ParameterMap {
 	return call.Response
 }
 
+// GetError returns the call error parameter map
+func (call *Call) GetError() *ParameterMap {
+	return call.Error
+}
+
 // GetService returns the call service
 func (call *Call) GetService() string {
 	return call.Service
@@ -318,6 +463,8 @@ func (call *Call) SetDescriptor(descriptor schema.Method) {
 type Proxy struct {
 	Name      string
 	DependsOn map[string]*Flow
+	Timeout   time.Duration
+	Bulkhead  *Bulkhead
 	Nodes     []*Node
 	Forward   *Call
 }
@@ -351,3 +498,18 @@ func (proxy *Proxy) GetOutput() *ParameterMap {
 func (proxy *Proxy) GetForward() *Call {
 	return proxy.Forward
 }
+
+// GetTimeout returns the maximum duration of a single proxy execution
+func (proxy *Proxy) GetTimeout() time.Duration {
+	return proxy.Timeout
+}
+
+// GetBulkhead returns the concurrency limits of the given proxy
+func (proxy *Proxy) GetBulkhead() *Bulkhead {
+	return proxy.Bulkhead
+}
+
+// GetOnError returns the error response of the given proxy
+func (proxy *Proxy) GetOnError() *OnError {
+	return nil
+}
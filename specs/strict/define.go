@@ -39,6 +39,27 @@ func DefineProxy(ctx context.Context, schema schema.Collection, manifest *specs.
 	logger.FromCtx(ctx, logger.Core).WithField("proxy", proxy.GetName()).Info("Defining proxy flow types")
 
 	for _, node := range proxy.Nodes {
+		if node.Foreach != nil {
+			err = DefineForeach(ctx, node, node.Foreach, proxy)
+			if err != nil {
+				return err
+			}
+		}
+
+		if node.Condition != nil {
+			err = DefineCondition(ctx, node, node.Condition, proxy)
+			if err != nil {
+				return err
+			}
+		}
+
+		if node.Cache != nil {
+			err = DefineCache(ctx, node, node.Cache, proxy)
+			if err != nil {
+				return err
+			}
+		}
+
 		if node.Call != nil {
 			err = DefineCall(ctx, schema, manifest, node, node.Call, proxy)
 			if err != nil {
@@ -77,6 +98,27 @@ func DefineFlow(ctx context.Context, schema schema.Collection, manifest *specs.M
 	}
 
 	for _, node := range flow.Nodes {
+		if node.Foreach != nil {
+			err = DefineForeach(ctx, node, node.Foreach, flow)
+			if err != nil {
+				return err
+			}
+		}
+
+		if node.Condition != nil {
+			err = DefineCondition(ctx, node, node.Condition, flow)
+			if err != nil {
+				return err
+			}
+		}
+
+		if node.Cache != nil {
+			err = DefineCache(ctx, node, node.Cache, flow)
+			if err != nil {
+				return err
+			}
+		}
+
 		if node.Call != nil {
 			err = DefineCall(ctx, schema, manifest, node, node.Call, flow)
 			if err != nil {
@@ -114,9 +156,50 @@ func DefineFlow(ctx context.Context, schema schema.Collection, manifest *specs.M
 		}
 	}
 
+	if flow.OnError != nil {
+		err = DefineOnError(ctx, schema, flow.OnError, flow)
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+// DefineOnError defines and checks the types of the given flow error response.
+// The error response is able to reference the error resource and all resources inside the flow.
+func DefineOnError(ctx context.Context, schema schema.Collection, onError *specs.OnError, flow specs.FlowManager) (err error) {
+	logger.FromCtx(ctx, logger.Core).WithField("flow", flow.GetName()).Info("Defining error response types")
+
+	for code := range onError.Status {
+		if !transport.IsCode(code) {
+			return trace.New(trace.WithMessage("unknown error code '%s' in flow '%s' on_error status codes", code, flow.GetName()))
+		}
+	}
+
+	// The error resource is used as breakpoint to allow references to the flow error
+	breakpoint := &specs.Node{
+		Name: specs.ErrorResource,
+	}
+
+	err = DefineParameterMap(ctx, breakpoint, onError.Response, flow)
+	if err != nil {
+		return err
+	}
+
+	message, err := GetObjectSchema(schema, onError.Response)
+	if err != nil {
+		return err
+	}
+
+	err = CheckHeader(onError.Response.Header, flow)
+	if err != nil {
+		return err
+	}
+
+	return CheckTypes(onError.Response.Property, message, flow)
+}
+
 // GetObjectSchema attempts to fetch the defined schema object for the given parameter map
 func GetObjectSchema(schema schema.Collection, params *specs.ParameterMap) (schema.Property, error) {
 	prop := schema.GetMessage(params.Schema)
@@ -129,6 +212,10 @@ func GetObjectSchema(schema schema.Collection, params *specs.ParameterMap) (sche
 
 // DefineCall defineds the types for the specs call
 func DefineCall(ctx context.Context, schema schema.Collection, manifest *specs.Manifest, node *specs.Node, call *specs.Call, flow specs.FlowManager) (err error) {
+	if call.GetService() == specs.FlowService {
+		return DefineFlowCall(ctx, schema, manifest, node, call, flow)
+	}
+
 	if call.GetMethod() == "" {
 		return nil
 	}
@@ -151,6 +238,11 @@ func DefineCall(ctx context.Context, schema schema.Collection, manifest *specs.M
 
 	call.SetDescriptor(method)
 
+	err = DefineError(schema, service, method, call, flow)
+	if err != nil {
+		return err
+	}
+
 	if call.GetRequest() != nil {
 		err = DefineParameterMap(ctx, node, call.GetRequest(), flow)
 		if err != nil {
@@ -176,6 +268,137 @@ func DefineCall(ctx context.Context, schema schema.Collection, manifest *specs.M
 	return nil
 }
 
+// DefineError defines the call error parameter map using the error schema defined inside the service or method options.
+// Method options take precedence over service options.
+func DefineError(collection schema.Collection, service schema.Service, method schema.Method, call *specs.Call, flow specs.FlowManager) error {
+	name := service.GetOptions()[specs.ErrorSchemaOption]
+
+	value, has := method.GetOptions()[specs.ErrorSchemaOption]
+	if has {
+		name = value
+	}
+
+	if name == "" {
+		return nil
+	}
+
+	message := collection.GetMessage(name)
+	if message == nil {
+		return trace.New(trace.WithMessage("undefined error schema '%s' in flow '%s'", name, flow.GetName()))
+	}
+
+	call.Error = specs.ToParameterMap(nil, "", message)
+	return nil
+}
+
+// DefineForeach defines the type of the given node foreach and checks whether it references a repeated property
+func DefineForeach(ctx context.Context, node *specs.Node, foreach *specs.Foreach, flow specs.FlowManager) (err error) {
+	logger.FromCtx(ctx, logger.Core).WithFields(logrus.Fields{
+		"call":      node.GetName(),
+		"reference": foreach.Property.Reference,
+	}).Info("Defining foreach types")
+
+	err = DefineProperty(ctx, node, foreach.Property, flow)
+	if err != nil {
+		return err
+	}
+
+	if foreach.Property.Label != types.LabelRepeated {
+		return trace.New(trace.WithMessage("cannot use (%s) label as foreach in '%s.%s', expected (%s)", foreach.Property.Label, flow.GetName(), node.GetName(), types.LabelRepeated))
+	}
+
+	return nil
+}
+
+// DefineCondition defines and checks the operand types of the given node condition
+func DefineCondition(ctx context.Context, node *specs.Node, condition *specs.Condition, flow specs.FlowManager) (err error) {
+	logger.FromCtx(ctx, logger.Core).WithFields(logrus.Fields{
+		"call":      node.GetName(),
+		"condition": condition.Expression,
+	}).Info("Defining condition types")
+
+	err = DefineProperty(ctx, node, condition.Left, flow)
+	if err != nil {
+		return err
+	}
+
+	if condition.Right != nil {
+		err = DefineProperty(ctx, node, condition.Right, flow)
+		if err != nil {
+			return err
+		}
+	}
+
+	return CheckCondition(node, condition, flow)
+}
+
+// DefineCache defines the types of the given node cache key properties
+func DefineCache(ctx context.Context, node *specs.Node, cache *specs.Cache, flow specs.FlowManager) (err error) {
+	logger.FromCtx(ctx, logger.Core).WithField("call", node.GetName()).Info("Defining cache key types")
+
+	for _, property := range cache.Key {
+		err = DefineProperty(ctx, node, property, flow)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DefineFlowCall defines the types for the given flow call.
+// The call request is checked against the called flow input and the called flow output is used as call response.
+func DefineFlowCall(ctx context.Context, schema schema.Collection, manifest *specs.Manifest, node *specs.Node, call *specs.Call, flow specs.FlowManager) (err error) {
+	logger.FromCtx(ctx, logger.Core).WithFields(logrus.Fields{
+		"call": node.GetName(),
+		"flow": call.GetMethod(),
+	}).Info("Defining flow call types")
+
+	target := manifest.Flows.Get(call.GetMethod())
+	if target == nil {
+		return trace.New(trace.WithMessage("undefined flow '%s' in flow '%s'", call.GetMethod(), flow.GetName()))
+	}
+
+	if call.GetRequest() != nil {
+		err = DefineParameterMap(ctx, node, call.GetRequest(), flow)
+		if err != nil {
+			return err
+		}
+
+		err = CheckHeader(call.GetRequest().Header, flow)
+		if err != nil {
+			return err
+		}
+
+		if target.Input == nil {
+			if len(call.GetRequest().Property.Nested) > 0 {
+				return trace.New(trace.WithMessage("flow '%s' called in '%s.%s' does not define a input", target.GetName(), flow.GetName(), node.GetName()))
+			}
+		} else {
+			message, err := GetObjectSchema(schema, target.Input)
+			if err != nil {
+				return err
+			}
+
+			err = CheckTypes(call.GetRequest().Property, message, flow)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if target.Output != nil {
+		message, err := GetObjectSchema(schema, target.Output)
+		if err != nil {
+			return err
+		}
+
+		call.Response = specs.ToParameterMap(nil, "", message)
+	}
+
+	return nil
+}
+
 // DefineCaller defineds the types for the given transport caller
 func DefineCaller(ctx context.Context, node *specs.Node, manifest *specs.Manifest, call transport.Call, flow specs.FlowManager) (err error) {
 	logger.FromCtx(ctx, logger.Core).Info("Defining caller references")
@@ -223,18 +446,37 @@ func DefineProperty(ctx context.Context, node *specs.Node, property *specs.Prope
 		}
 	}
 
+	if property.Expression != nil {
+		return DefineExpression(ctx, node, property, flow)
+	}
+
+	for _, argument := range property.Arguments {
+		err := DefineProperty(ctx, node, argument, flow)
+		if err != nil {
+			return err
+		}
+	}
+
+	if property.Prepare != nil {
+		err := CheckFunction(node, property, flow)
+		if err != nil {
+			return err
+		}
+	}
+
 	if property.Reference == nil {
 		return nil
 	}
 
+	rollback := false
 	breakpoint := specs.OutputResource
 	if node != nil {
 		breakpoint = node.GetName()
 
 		if node.Rollback != nil {
-			rollback := node.Rollback.GetRequest().Property
-			if InsideProperty(rollback, property) {
+			if InsideProperty(node.Rollback.GetRequest().Property, property) {
 				breakpoint = lookup.GetNextResource(flow, breakpoint)
+				rollback = true
 			}
 		}
 	}
@@ -244,12 +486,29 @@ func DefineProperty(ctx context.Context, node *specs.Node, property *specs.Prope
 		"reference":  property.Reference,
 	}).Debug("Lookup references until breakpoint")
 
+	if lookup.HasSelectors(property.Reference.Path) {
+		_, _, err := lookup.ParseSelectors(property.Reference.Path)
+		if err != nil {
+			return trace.New(trace.WithExpression(property.Expr), trace.WithMessage("%s in '%s.%s.%s'", err, flow.GetName(), breakpoint, property.Path))
+		}
+	}
+
 	references := lookup.GetAvailableResources(flow, breakpoint)
+
+	// Foreach rollbacks are executed for each iteration and are able to reference the iteration response
+	if rollback && node.Foreach != nil && node.Call != nil && node.Call.Response != nil {
+		references[node.Name][specs.ResourceResponse] = lookup.ParameterMapLookup(node.Call.Response.Property)
+	}
 	reference := lookup.GetResourceReference(property.Reference, references, breakpoint)
 	if reference == nil {
 		return trace.New(trace.WithExpression(property.Expr), trace.WithMessage("undefined resource '%s' in '%s.%s.%s'", property.Reference, flow.GetName(), breakpoint, property.Path))
 	}
 
+	err := CheckAsync(property, flow, breakpoint)
+	if err != nil {
+		return err
+	}
+
 	property.Type = reference.Type
 	property.Label = reference.Label
 	property.Default = reference.Default
@@ -260,6 +519,180 @@ func DefineProperty(ctx context.Context, node *specs.Node, property *specs.Prope
 	return nil
 }
 
+// CheckFunction checks whether the defined argument types are accepted by the given function property.
+// The types of referenced arguments are unknown while parsing and are checked once the arguments have been defined.
+func CheckFunction(node *specs.Node, property *specs.Property, flow specs.FlowManager) error {
+	_, err := property.Prepare(property.Path, property.Arguments...)
+	if err == nil {
+		return nil
+	}
+
+	breakpoint := specs.OutputResource
+	if node != nil {
+		breakpoint = node.GetName()
+	}
+
+	return trace.New(trace.WithExpression(property.Expr), trace.WithMessage("%s in '%s.%s.%s'", err, flow.GetName(), breakpoint, property.Path))
+}
+
+// DefineExpression defines the types of the given expression operands and checks whether the expression operators could be applied to them
+func DefineExpression(ctx context.Context, node *specs.Node, property *specs.Property, flow specs.FlowManager) error {
+	logger.FromCtx(ctx, logger.Core).WithField("expression", property.Expression.Raw).Debug("Defining expression types")
+
+	for _, operand := range property.Expression.Properties() {
+		err := DefineProperty(ctx, node, operand, flow)
+		if err != nil {
+			return err
+		}
+	}
+
+	err := CheckExpression(property.Expression)
+	if err != nil {
+		breakpoint := specs.OutputResource
+		if node != nil {
+			breakpoint = node.GetName()
+		}
+
+		return trace.New(trace.WithExpression(property.Expr), trace.WithMessage("%s in '%s.%s.%s'", err, flow.GetName(), breakpoint, property.Path))
+	}
+
+	property.Type = property.Expression.Type
+	property.Label = types.LabelOptional
+
+	return nil
+}
+
+// CheckExpression checks whether the operators inside the given expression could be applied to their operands.
+// The resulting type of each (nested) expression is set once checked.
+func CheckExpression(expression *specs.Expression) error {
+	if expression.Property != nil {
+		if expression.Property.Label == types.LabelRepeated {
+			return trace.New(trace.WithMessage("cannot use repeated property '%s' in expression", expression.Raw))
+		}
+
+		if expression.Property.Type == types.TypeMessage {
+			return trace.New(trace.WithMessage("cannot use (%s) type '%s' in expression", types.TypeMessage, expression.Raw))
+		}
+
+		expression.Type = expression.Property.Type
+		return nil
+	}
+
+	for _, operand := range expression.Operands {
+		err := CheckExpression(operand)
+		if err != nil {
+			return err
+		}
+	}
+
+	if expression.Operator == specs.OperatorInterpolate {
+		expression.Type = types.TypeString
+		return nil
+	}
+
+	left := expression.Operands[0]
+	operator := expression.Operator
+
+	if len(expression.Operands) == 1 {
+		switch {
+		case operator == specs.OperatorNot && left.Type == types.TypeBool:
+		case operator == specs.OperatorSubtract && IsNumeric(left.Type):
+		default:
+			return trace.New(trace.WithMessage("cannot use operator '%s' on (%s) in expression '%s'", operator, left.Type, expression.Raw))
+		}
+
+		expression.Type = left.Type
+		return nil
+	}
+
+	if operator == specs.OperatorTernary {
+		if left.Type != types.TypeBool {
+			return trace.New(trace.WithMessage("cannot use (%s) type as ternary condition in expression '%s', expected (%s)", left.Type, expression.Raw, types.TypeBool))
+		}
+
+		left = expression.Operands[1]
+	}
+
+	right := expression.Operands[len(expression.Operands)-1]
+
+	switch {
+	case operator == specs.OperatorAnd || operator == specs.OperatorOr:
+		if left.Type != types.TypeBool || right.Type != types.TypeBool {
+			return trace.New(trace.WithMessage("cannot use operator '%s' on (%s) and (%s) in expression '%s'", operator, left.Type, right.Type, expression.Raw))
+		}
+
+		expression.Type = types.TypeBool
+	case specs.IsComparison(operator):
+		comparable := UnifyOperands(left, right) || (IsNumeric(left.Type) && IsNumeric(right.Type))
+		if comparable && left.Type == types.TypeBool && operator != specs.OperatorEqual && operator != specs.OperatorNotEqual {
+			comparable = false
+		}
+
+		if !comparable {
+			return trace.New(trace.WithMessage("cannot compare (%s) with (%s) using operator '%s' in expression '%s'", left.Type, right.Type, operator, expression.Raw))
+		}
+
+		expression.Type = types.TypeBool
+	case operator == specs.OperatorCoalesce || operator == specs.OperatorTernary:
+		if !UnifyOperands(left, right) {
+			return trace.New(trace.WithMessage("cannot use operator '%s' on (%s) and (%s) in expression '%s', expected equal types", operator, left.Type, right.Type, expression.Raw))
+		}
+
+		expression.Type = left.Type
+	case operator == specs.OperatorAdd && left.Type == types.TypeString && right.Type == types.TypeString:
+		expression.Type = types.TypeString
+	default:
+		if !IsNumeric(left.Type) || !UnifyOperands(left, right) {
+			return trace.New(trace.WithMessage("cannot use operator '%s' on (%s) and (%s) in expression '%s'", operator, left.Type, right.Type, expression.Raw))
+		}
+
+		expression.Type = left.Type
+	}
+
+	return nil
+}
+
+// UnifyOperands checks whether the given operands are of the same type.
+// Numeric constants are converted to the type of the other operand.
+func UnifyOperands(left *specs.Expression, right *specs.Expression) bool {
+	if left.Type == right.Type {
+		return true
+	}
+
+	if !IsNumeric(left.Type) || !IsNumeric(right.Type) {
+		return false
+	}
+
+	if IsConstant(left) && ConvertConstant(left, right.Type) {
+		return true
+	}
+
+	if IsConstant(right) && ConvertConstant(right, left.Type) {
+		return true
+	}
+
+	return false
+}
+
+// IsConstant checks whether the given expression represents a constant value
+func IsConstant(expression *specs.Expression) bool {
+	return expression.Property != nil && expression.Property.Reference == nil && expression.Property.Expression == nil && expression.Property.Function == nil
+}
+
+// ConvertConstant converts the given numeric constant to the given type.
+// Floating point constants are only converted to floating point types.
+func ConvertConstant(expression *specs.Expression, typed types.Type) bool {
+	if expression.Type == types.TypeDouble && typed != types.TypeFloat {
+		return false
+	}
+
+	expression.Type = typed
+	expression.Property.Type = typed
+	expression.Property.Default = specs.ConvertNumeric(expression.Property.Default, typed)
+
+	return true
+}
+
 // InsideProperty checks whether the given property is insde the source property
 func InsideProperty(source *specs.Property, target *specs.Property) bool {
 	if source == target {
@@ -275,9 +708,40 @@ func InsideProperty(source *specs.Property, target *specs.Property) bool {
 		}
 	}
 
+	for _, operand := range source.Expression.Properties() {
+		if InsideProperty(operand, target) {
+			return true
+		}
+	}
+
+	for _, argument := range source.Arguments {
+		if InsideProperty(argument, target) {
+			return true
+		}
+	}
+
 	return false
 }
 
+// CheckAsync checks whether the given property references a async resource.
+// Async resources are dispatched without blocking the flow and could not be referenced by other resources.
+func CheckAsync(property *specs.Property, flow specs.FlowManager, breakpoint string) error {
+	target, _ := lookup.ParseResource(property.Reference.Resource)
+	if target == "" || target == breakpoint {
+		return nil
+	}
+
+	for _, node := range flow.GetNodes() {
+		if node.GetName() != target || !node.IsAsync() {
+			continue
+		}
+
+		return trace.New(trace.WithExpression(property.Expr), trace.WithMessage("cannot reference async resource '%s' in '%s.%s.%s'", property.Reference, flow.GetName(), breakpoint, property.Path))
+	}
+
+	return nil
+}
+
 // CheckHeader checks the given header types
 func CheckHeader(header specs.Header, flow specs.FlowManager) error {
 	for _, header := range header {
@@ -335,6 +799,58 @@ func CheckTypes(property *specs.Property, schema schema.Property, flow specs.Flo
 	return nil
 }
 
+// CheckCondition checks whether the given condition operands could be compared with one another.
+// Comparisons are checked as expressions, conditions without a comparison have to result in a boolean.
+func CheckCondition(node *specs.Node, condition *specs.Condition, flow specs.FlowManager) error {
+	expression := ConditionOperand(condition, condition.Left)
+
+	if condition.Right != nil {
+		expression = &specs.Expression{
+			Raw:      condition.Expression,
+			Operator: condition.Operator,
+			Operands: []*specs.Expression{
+				ConditionOperand(condition, condition.Left),
+				ConditionOperand(condition, condition.Right),
+			},
+		}
+	}
+
+	err := CheckExpression(expression)
+	if err != nil {
+		return trace.New(trace.WithMessage("%s in condition of '%s.%s'", err, flow.GetName(), node.GetName()))
+	}
+
+	if expression.Type != types.TypeBool {
+		return trace.New(trace.WithMessage("cannot use (%s) type as condition '%s' in '%s.%s', expected (%s)", expression.Type, condition.Expression, flow.GetName(), node.GetName(), types.TypeBool))
+	}
+
+	return nil
+}
+
+// ConditionOperand wraps the given condition operand inside a expression.
+// Referencing operands are represented by their reference.
+func ConditionOperand(condition *specs.Condition, property *specs.Property) *specs.Expression {
+	raw := condition.Expression
+	if property.Reference != nil {
+		raw = property.Reference.String()
+	}
+
+	return &specs.Expression{
+		Raw:      raw,
+		Property: property,
+	}
+}
+
+// IsNumeric checks whether the given type represents a numeric value
+func IsNumeric(typed types.Type) bool {
+	switch typed {
+	case types.TypeDouble, types.TypeFloat, types.TypeInt64, types.TypeUint64, types.TypeInt32, types.TypeFixed64, types.TypeFixed32, types.TypeUint32, types.TypeSfixed32, types.TypeSfixed64, types.TypeSint32, types.TypeSint64:
+		return true
+	}
+
+	return false
+}
+
 // ResolvePropertyReferences moves any property reference into the correct data structure
 func ResolvePropertyReferences(property *specs.Property) {
 	if len(property.Nested) > 0 {
@@ -356,6 +872,22 @@ func ResolvePropertyReferences(property *specs.Property) {
 	clone := property.Reference.Property.Clone(property.Reference, property.Name, property.Path)
 	property.Reference = clone.Reference
 	property.Nested = clone.Nested
+
+	if lookup.HasSelectors(property.Reference.Path) {
+		StripNestedSelectors(property, property.Label == types.LabelRepeated)
+	}
+}
+
+// StripNestedSelectors removes the index and wildcard selectors from the references of the nested properties of repeated properties.
+// Repeated items are resolved against their own reference stores which hold the item values without selectors.
+func StripNestedSelectors(property *specs.Property, repeated bool) {
+	for _, nested := range property.Nested {
+		if repeated && nested.Reference != nil {
+			nested.Reference.Path = lookup.StripSelectors(nested.Reference.Path)
+		}
+
+		StripNestedSelectors(nested, repeated || nested.Label == types.LabelRepeated)
+	}
 }
 
 // SchemaToProperty parses the given schema property to a specs property
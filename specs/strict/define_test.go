@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/jexia/maestro/definitions/hcl"
+	"github.com/jexia/maestro/functions"
 	"github.com/jexia/maestro/logger"
 	"github.com/jexia/maestro/schema/mock"
 	"github.com/jexia/maestro/utils"
@@ -44,7 +45,7 @@ func TestUnmarshalFile(t *testing.T) {
 				t.Fatal(err)
 			}
 
-			manifest, err := hcl.ParseSpecs(ctx, definition, nil)
+			manifest, err := hcl.ParseSpecs(ctx, definition, functions.Default)
 			if err != nil {
 				t.Fatal(err)
 			}
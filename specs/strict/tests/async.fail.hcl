@@ -0,0 +1,22 @@
+service "com.maestro" "caller" "http" "json" {
+	host = ""
+}
+
+flow "echo" {
+	input "input" {
+	}
+
+	resource "audit" {
+		type = "async"
+
+		request "caller" "Open" {
+			message = "{{ input:message }}"
+		}
+	}
+
+	resource "product" {
+		request "caller" "Open" {
+			message = "{{ audit:message }}"
+		}
+	}
+}
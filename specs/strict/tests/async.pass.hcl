@@ -0,0 +1,28 @@
+service "com.maestro" "caller" "http" "json" {
+	host = ""
+}
+
+flow "echo" {
+	input "input" {
+	}
+
+	resource "audit" {
+		type = "async"
+
+		request "caller" "Open" {
+			message = "{{ input:message }}"
+		}
+	}
+
+	resource "product" {
+		depends_on = ["audit"]
+
+		request "caller" "Open" {
+			message = "{{ input:message }}"
+		}
+	}
+
+	output "input" {
+		message = "{{ product:message }}"
+	}
+}
@@ -0,0 +1,19 @@
+service "com.maestro" "caller" "http" "json" {
+	host = ""
+}
+
+flow "echo" {
+	input "input" {
+	}
+
+	resource "product" {
+		cache {
+			ttl = "30s"
+			key = ["{{ input:unknown }}"]
+		}
+
+		request "caller" "Open" {
+			message = "{{ input:message }}"
+		}
+	}
+}
@@ -0,0 +1,20 @@
+service "com.maestro" "caller" "http" "json" {
+	host = ""
+}
+
+flow "echo" {
+	input "input" {
+	}
+
+	resource "product" {
+		cache {
+			ttl = "30s"
+			key = ["{{ input:message }}"]
+			max_entries = 100
+		}
+
+		request "caller" "Open" {
+			message = "{{ input:message }}"
+		}
+	}
+}
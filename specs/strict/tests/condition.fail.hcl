@@ -0,0 +1,16 @@
+service "com.maestro" "caller" "http" "json" {
+	host = ""
+}
+
+flow "echo" {
+	input "input" {
+	}
+
+	resource "opening" {
+		if = "{{ input:message > 1000 }}"
+
+		request "caller" "Open" {
+			message = "{{ input:message }}"
+		}
+	}
+}
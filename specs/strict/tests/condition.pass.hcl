@@ -0,0 +1,32 @@
+service "com.maestro" "caller" "http" "json" {
+	host = ""
+}
+
+flow "echo" {
+	input "input" {
+	}
+
+	resource "opening" {
+		if = "{{ input:amount > 1000 }}"
+
+		request "caller" "Open" {
+			message = "{{ input:message }}"
+		}
+	}
+
+	resource "closing" {
+		if = "{{ opening:message == 'open' }}"
+
+		request "caller" "Open" {
+			message = "{{ input:message }}"
+		}
+	}
+
+	resource "verifying" {
+		if = "{{ input:verified }}"
+
+		request "caller" "Open" {
+			message = "{{ input:message }}"
+		}
+	}
+}
@@ -0,0 +1,22 @@
+service "com.maestro" "caller" "http" "json" {
+	host = ""
+}
+
+flow "echo" {
+	input "input" {
+	}
+
+	resource "opening" {
+		if = "{{ input:amount * 2 > 1000 && !input:verified }}"
+
+		request "caller" "Open" {
+			message = "{{ input:nickname ?? input:name }}"
+			amount = "{{ (input:amount + 10) % 100 }}"
+		}
+	}
+
+	output "output" {
+		message = "{{ input:verified ? 'hello ' + opening:message : 'unverified' }}"
+		amount = "{{ -opening:amount }}"
+	}
+}
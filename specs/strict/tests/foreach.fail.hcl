@@ -0,0 +1,16 @@
+service "com.maestro" "caller" "http" "json" {
+	host = ""
+}
+
+flow "echo" {
+	input "input" {
+	}
+
+	resource "charge" {
+		foreach = "{{ input:message }}"
+
+		request "caller" "Open" {
+			message = "{{ input:message }}"
+		}
+	}
+}
@@ -0,0 +1,25 @@
+service "com.maestro" "caller" "http" "json" {
+	host = ""
+}
+
+flow "echo" {
+	input "input" {
+	}
+
+	resource "charge" {
+		foreach = "{{ input:items }}"
+		parallel = 5
+
+		request "caller" "Open" {
+			message = "{{ input:items.id }}"
+		}
+
+		rollback "caller" "Open" {
+			message = "{{ charge:message }}"
+		}
+	}
+
+	output "output" {
+		charges = "{{ charge:. }}"
+	}
+}
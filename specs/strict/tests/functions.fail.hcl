@@ -0,0 +1,13 @@
+service "com.maestro" "caller" "http" "json" {
+	host = ""
+}
+
+flow "echo" {
+	input "input" {}
+
+	resource "opening" {
+		request "caller" "Open" {
+			message = "{{ upper(input:age) }}"
+		}
+	}
+}
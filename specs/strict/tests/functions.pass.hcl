@@ -0,0 +1,17 @@
+service "com.maestro" "caller" "http" "json" {
+	host = ""
+}
+
+flow "echo" {
+	input "input" {}
+
+	resource "opening" {
+		request "caller" "Open" {
+			message = "{{ upper(input:name) }}"
+		}
+	}
+
+	output "output" {
+		message = "{{ lower(opening:message) }} ({{ sprintf('%d', input:age) }})"
+	}
+}
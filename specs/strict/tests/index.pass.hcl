@@ -0,0 +1,19 @@
+service "com.maestro" "caller" "http" "json" {
+	host = ""
+}
+
+flow "echo" {
+	input "input" {
+	}
+
+	resource "opening" {
+		request "caller" "Open" {
+			message = "{{ input:items[-1].name }}"
+		}
+	}
+
+	output "output" {
+		id = "{{ input:items[0].id }}"
+		names = "{{ input:items[*].name }}"
+	}
+}
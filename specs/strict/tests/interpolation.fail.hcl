@@ -0,0 +1,14 @@
+service "com.maestro" "caller" "http" "json" {
+	host = ""
+}
+
+flow "echo" {
+	input "input" {
+	}
+
+	resource "opening" {
+		request "caller" "Open" {
+			message = "/users/{{ input:unknown }}/orders"
+		}
+	}
+}
@@ -0,0 +1,23 @@
+service "com.maestro" "caller" "http" "json" {
+	host = ""
+}
+
+flow "echo" {
+	input "input" {
+		header = ["Authorization"]
+	}
+
+	resource "opening" {
+		request "caller" "Open" {
+			header {
+				Authorization = "Bearer {{ input.header:Authorization }}"
+			}
+
+			message = "/users/{{ input:id }}/orders?verified={{ input:verified }}"
+		}
+	}
+
+	output "output" {
+		message = "{{ opening:message }} ({{ input:id * 2 }})"
+	}
+}
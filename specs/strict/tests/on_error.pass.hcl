@@ -0,0 +1,34 @@
+service "com.maestro" "caller" "http" "json" {
+	host = ""
+}
+
+flow "echo" {
+	input "input" {
+	}
+
+	resource "product" {
+		request "caller" "Open" {
+			message = "{{ input:message }}"
+		}
+	}
+
+	output "output" {
+		message = "{{ product:message }}"
+	}
+
+	on_error "error" {
+		status_codes = {
+			upstream = 502
+		}
+
+		header {
+			Node = "{{ error:node }}"
+		}
+
+		code = "{{ error:code }}"
+		message = "{{ error:message }}"
+		status = "{{ error:status }}"
+		retryable = "{{ error:retryable }}"
+		product = "{{ product:message }}"
+	}
+}
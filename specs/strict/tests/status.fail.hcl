@@ -0,0 +1,20 @@
+service "com.maestro" "caller" "http" "json" {
+	host = ""
+}
+
+flow "echo" {
+	input "input" {
+	}
+
+	resource "product" {
+		request "caller" "Open" {
+			message = "{{ input:message }}"
+		}
+	}
+
+	output "output" {
+		message = "{{ product:message }}"
+		status = "{{ product.status }}"
+		reason = "{{ product.error:reason }}"
+	}
+}
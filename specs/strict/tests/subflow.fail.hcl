@@ -0,0 +1,14 @@
+service "com.maestro" "caller" "http" "json" {
+	host = ""
+}
+
+flow "echo" {
+	input "input" {
+	}
+
+	resource "greeting" {
+		request "flow" "greeter" {
+			message = "{{ input:message }}"
+		}
+	}
+}
@@ -0,0 +1,33 @@
+service "com.maestro" "caller" "http" "json" {
+	host = ""
+}
+
+flow "greeter" {
+	input "input" {
+	}
+
+	resource "opening" {
+		request "caller" "Open" {
+			message = "{{ input:message }}"
+		}
+	}
+
+	output "output" {
+		message = "{{ opening:message }}"
+	}
+}
+
+flow "echo" {
+	input "input" {
+	}
+
+	resource "greeting" {
+		request "flow" "greeter" {
+			message = "{{ input:message }}"
+		}
+	}
+
+	output "output" {
+		message = "{{ greeting:message }}"
+	}
+}
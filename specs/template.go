@@ -7,6 +7,7 @@ import (
 
 	"github.com/jexia/maestro/logger"
 	"github.com/jexia/maestro/specs/trace"
+	"github.com/jexia/maestro/specs/types"
 	"github.com/sirupsen/logrus"
 )
 
@@ -32,12 +33,22 @@ const (
 	InputResource = "input"
 	// OutputResource key
 	OutputResource = "output"
+	// ErrorResource key
+	ErrorResource = "error"
+	// EnvResource key, references to environment variables are resolved on startup
+	EnvResource = "env"
+	// SecretResource key, references to secrets are resolved on startup through the configured secret provider
+	SecretResource = "secret"
 	// ResourceRequest property
 	ResourceRequest = "request"
 	// ResourceHeader property
 	ResourceHeader = "header"
 	// ResourceResponse property
 	ResourceResponse = "response"
+	// ResourceStatus property
+	ResourceStatus = "status"
+	// ResourceError property
+	ResourceError = "error"
 
 	// DefaultInputProperty represents the default input property on resource select
 	DefaultInputProperty = ResourceRequest
@@ -45,15 +56,57 @@ const (
 	DefaultCallProperty = ResourceResponse
 )
 
-// IsTemplate checks whether the given value is a template
+// IsTemplate checks whether the given value is a single template
 func IsTemplate(value string) bool {
-	return strings.HasPrefix(value, TemplateOpen) && strings.HasSuffix(value, TemplateClose)
+	return strings.HasPrefix(value, TemplateOpen) && IndexTemplateClose(value) == len(value)-len(TemplateClose)
+}
+
+// ContainsTemplate checks whether the given value contains one or multiple templates.
+// Opening tags which are not followed by a closing tag are treated as literal text.
+func ContainsTemplate(value string) bool {
+	start := strings.Index(value, TemplateOpen)
+	if start < 0 {
+		return false
+	}
+
+	return IndexTemplateClose(value[start:]) >= 0
+}
+
+// IndexTemplateClose returns the index of the closing tag of the template at the start of the given value.
+// Closing tags defined inside quoted strings are ignored. -1 is returned if the template is not closed.
+func IndexTemplateClose(value string) int {
+	var quote rune
+
+	for index, char := range value {
+		if index < len(TemplateOpen) {
+			continue
+		}
+
+		if quote != 0 {
+			if char == quote {
+				quote = 0
+			}
+
+			continue
+		}
+
+		if char == '"' || char == '\'' {
+			quote = char
+			continue
+		}
+
+		if strings.HasPrefix(value[index:], TemplateClose) {
+			return index
+		}
+	}
+
+	return -1
 }
 
 // GetTemplateContent trims the opening and closing tags from the given template value
 func GetTemplateContent(value string) string {
-	value = strings.Replace(value, TemplateOpen, "", 1)
-	value = strings.Replace(value, TemplateClose, "", 1)
+	value = strings.TrimPrefix(value, TemplateOpen)
+	value = strings.TrimSuffix(value, TemplateClose)
 	value = strings.TrimSpace(value)
 	return value
 }
@@ -87,7 +140,7 @@ func ParseReference(path string, value string) *Property {
 func ParseFunction(path string, functions CustomDefinedFunctions, content string) (*Property, error) {
 	pattern := FunctionPattern.FindStringSubmatch(content)
 	fn := pattern[1]
-	args := strings.Split(pattern[2], FunctionArgumentDelimiter)
+	args := SplitArguments(pattern[2])
 
 	if functions[fn] == nil {
 		return nil, trace.New(trace.WithMessage("undefined custom function '%s' in '%s'", fn, content))
@@ -109,21 +162,70 @@ func ParseFunction(path string, functions CustomDefinedFunctions, content string
 		return nil, err
 	}
 
+	if property.Function != nil {
+		property.Prepare = functions[fn]
+		property.Arguments = arguments
+	}
+
 	return property, nil
 }
 
-// ParseTemplateContent parses the given template function
+// SplitArguments splits the given function arguments.
+// Delimiters defined inside quoted strings or nested function calls are ignored.
+func SplitArguments(content string) []string {
+	if strings.TrimSpace(content) == "" {
+		return nil
+	}
+
+	result := []string{}
+	depth := 0
+	start := 0
+
+	var quote rune
+
+	for index, char := range content {
+		if quote != 0 {
+			if char == quote {
+				quote = 0
+			}
+
+			continue
+		}
+
+		switch {
+		case char == '"' || char == '\'':
+			quote = char
+		case char == '(':
+			depth++
+		case char == ')':
+			depth--
+		case depth == 0 && string(char) == FunctionArgumentDelimiter:
+			result = append(result, content[start:index])
+			start = index + len(FunctionArgumentDelimiter)
+		}
+	}
+
+	return append(result, content[start:])
+}
+
+// ParseTemplateContent parses the given template content.
+// Templates containing a single operand result in a constant, reference or function property.
+// All other templates result in a expression property.
 func ParseTemplateContent(path string, functions CustomDefinedFunctions, content string) (*Property, error) {
-	if FunctionPattern.MatchString(content) {
-		return ParseFunction(path, functions, content)
+	expression, err := ParseExpression(path, functions, content)
+	if err != nil {
+		return nil, err
 	}
 
-	// TODO: handle constant
-	return ParseReference(path, content), nil
+	return ExpressionProperty(path, expression), nil
 }
 
 // ParseTemplate parses the given value template and sets the resource and path
 func ParseTemplate(ctx context.Context, path string, functions CustomDefinedFunctions, value string) (*Property, error) {
+	if !IsTemplate(value) {
+		return ParseInterpolation(ctx, path, functions, value)
+	}
+
 	content := GetTemplateContent(value)
 	logger.FromCtx(ctx, logger.Core).WithField("path", path).WithField("template", content).Debug("Parsing property template")
 
@@ -133,15 +235,76 @@ func ParseTemplate(ctx context.Context, path string, functions CustomDefinedFunc
 	}
 
 	logger.FromCtx(ctx, logger.Core).WithFields(logrus.Fields{
-		"path":      path,
-		"type":      result.Type,
-		"default":   result.Default,
-		"reference": result.Reference,
+		"path":       path,
+		"type":       result.Type,
+		"default":    result.Default,
+		"reference":  result.Reference,
+		"expression": result.Expression != nil,
 	}).Debug("Template results in property with type")
 
 	return result, nil
 }
 
+// ParseInterpolation parses the given value containing literal text and templates.
+// The literal text and template values are concatenated into a single string once marshalled.
+// Opening tags which are not followed by a closing tag are treated as literal text.
+func ParseInterpolation(ctx context.Context, path string, functions CustomDefinedFunctions, value string) (*Property, error) {
+	logger.FromCtx(ctx, logger.Core).WithField("path", path).WithField("value", value).Debug("Parsing interpolated value")
+
+	expression := &Expression{
+		Raw:      value,
+		Operator: OperatorInterpolate,
+		Type:     types.TypeString,
+	}
+
+	for remaining := value; remaining != ""; {
+		start := strings.Index(remaining, TemplateOpen)
+		if start < 0 {
+			start = len(remaining)
+		}
+
+		if start > 0 {
+			literal := remaining[:start]
+			expression.Operands = append(expression.Operands, &Expression{
+				Raw:      literal,
+				Property: &Property{Path: path, Type: types.TypeString, Default: literal},
+				Type:     types.TypeString,
+			})
+		}
+
+		remaining = remaining[start:]
+		if remaining == "" {
+			break
+		}
+
+		end := IndexTemplateClose(remaining)
+		if end < 0 {
+			expression.Operands = append(expression.Operands, &Expression{
+				Raw:      remaining,
+				Property: &Property{Path: path, Type: types.TypeString, Default: remaining},
+				Type:     types.TypeString,
+			})
+
+			break
+		}
+
+		operand, err := ParseExpression(path, functions, strings.TrimSpace(remaining[len(TemplateOpen):end]))
+		if err != nil {
+			return nil, err
+		}
+
+		expression.Operands = append(expression.Operands, operand)
+		remaining = remaining[end+len(TemplateClose):]
+	}
+
+	return &Property{
+		Path:       path,
+		Type:       types.TypeString,
+		Label:      types.LabelOptional,
+		Expression: expression,
+	}, nil
+}
+
 // JoinPath joins the given flow paths
 func JoinPath(values ...string) (result string) {
 	for _, value := range values {
@@ -96,6 +96,42 @@ func TestGetTemplateContent(t *testing.T) {
 	}
 }
 
+func TestIsTemplate(t *testing.T) {
+	tests := map[string]bool{
+		"{{ input:message }}":                  true,
+		"{{ 'a}}b' }}":                         true,
+		"{{ input:message }} {{ input:name }}": false,
+		"prefix {{ input:message }}":           false,
+		"{{ input:message }} suffix":           false,
+		"{{ input:message":                     false,
+	}
+
+	for input, expected := range tests {
+		result := IsTemplate(input)
+		if result != expected {
+			t.Errorf("unexpected result %t for '%s', expected %t", result, input, expected)
+		}
+	}
+}
+
+func TestContainsTemplate(t *testing.T) {
+	tests := map[string]bool{
+		"{{ input:message }}":         true,
+		"Bearer {{ input:token }}":    true,
+		"{{not a template":            false,
+		"{{ 'unterminated }}":         false,
+		"literal }} {{ input:name }}": true,
+		"literal text":                false,
+	}
+
+	for input, expected := range tests {
+		result := ContainsTemplate(input)
+		if result != expected {
+			t.Errorf("unexpected result %t for '%s', expected %t", result, input, expected)
+		}
+	}
+}
+
 func TestParseReference(t *testing.T) {
 	path := "message"
 	tests := map[string]Property{
@@ -155,6 +191,63 @@ func TestParseFunction(t *testing.T) {
 	}
 }
 
+func TestParseFunctionArguments(t *testing.T) {
+	functions := CustomDefinedFunctions{
+		"fn": func(path string, args ...*Property) (*Property, error) {
+			return &Property{
+				Path: path,
+				Type: types.TypeString,
+				Function: func(values ...interface{}) interface{} {
+					return nil
+				},
+			}, nil
+		},
+	}
+
+	tests := map[string]int{
+		"fn()":                             0,
+		"fn(input:message)":                1,
+		"fn('a, b', input:message)":        2,
+		"fn(fn(input:a, input:b), 'c')":    2,
+		"fn(input:amount + 1, input:name)": 2,
+	}
+
+	for input, expected := range tests {
+		t.Run(input, func(t *testing.T) {
+			property, err := ParseFunction("message", functions, input)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(property.Arguments) != expected {
+				t.Fatalf("unexpected arguments %d, expected %d", len(property.Arguments), expected)
+			}
+		})
+	}
+}
+
+func TestSplitArguments(t *testing.T) {
+	tests := map[string][]string{
+		"":                        nil,
+		"input:message":           {"input:message"},
+		"'%s, %s', input:a":       {"'%s, %s'", " input:a"},
+		"fn(input:a, input:b), 1": {"fn(input:a, input:b)", " 1"},
+	}
+
+	for input, expected := range tests {
+		result := SplitArguments(input)
+		if len(result) != len(expected) {
+			t.Fatalf("unexpected arguments %v, expected %v", result, expected)
+		}
+
+		for index := range expected {
+			if result[index] != expected[index] {
+				t.Errorf("unexpected argument '%s', expected '%s'", result[index], expected[index])
+			}
+		}
+	}
+}
+
 func TestParseUnavailableFunction(t *testing.T) {
 	path := "message"
 	functions := CustomDefinedFunctions{}
@@ -208,3 +301,95 @@ func TestParseTemplate(t *testing.T) {
 		CompareProperties(t, *property, expected)
 	}
 }
+
+func TestParseTemplateExpression(t *testing.T) {
+	ctx := context.Background()
+	ctx = logger.WithValue(ctx)
+
+	property, err := ParseTemplate(ctx, "name", nil, "{{ input:nickname ?? input:name }}")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if property.Path != "name" {
+		t.Errorf("unexpected path %s, expected %s", property.Path, "name")
+	}
+
+	if property.Expression == nil {
+		t.Fatal("expression not set but expected")
+	}
+
+	if property.Expression.Operator != OperatorCoalesce {
+		t.Errorf("unexpected operator %s, expected %s", property.Expression.Operator, OperatorCoalesce)
+	}
+}
+
+func TestParseInterpolation(t *testing.T) {
+	ctx := context.Background()
+	ctx = logger.WithValue(ctx)
+
+	values := map[string]interface{}{
+		"id":    int32(42),
+		"token": "secret",
+	}
+
+	resolve := func(property *Property) interface{} {
+		if property.Reference == nil {
+			return property.Default
+		}
+
+		return values[property.Reference.Path]
+	}
+
+	tests := map[string]string{
+		"Bearer {{ input.header:token }}":            "Bearer secret",
+		"/users/{{ input:id }}/orders":               "/users/42/orders",
+		"{{ input:id }}{{ input:token }}":            "42secret",
+		"{{ input:id + 1 }} items":                   "43 items",
+		"unknown: {{ input:unknown }}":               "unknown: ",
+		"{{ input:token }} and {{ 'literal' }} text": "secret and literal text",
+		"{{ 'a}}b' }} text":                          "a}}b text",
+		"{{ input:token }} {{not closed":             "secret {{not closed",
+	}
+
+	for input, expected := range tests {
+		t.Run(input, func(t *testing.T) {
+			property, err := ParseTemplate(ctx, "path", nil, input)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if property.Type != types.TypeString {
+				t.Fatalf("unexpected type %s, expected %s", property.Type, types.TypeString)
+			}
+
+			if property.Expression == nil || property.Expression.Operator != OperatorInterpolate {
+				t.Fatal("interpolation expression not set but expected")
+			}
+
+			result := property.Expression.Evaluate(resolve)
+			if result != expected {
+				t.Fatalf("unexpected result '%v', expected '%s'", result, expected)
+			}
+		})
+	}
+}
+
+func TestParseInterpolationFail(t *testing.T) {
+	ctx := context.Background()
+	ctx = logger.WithValue(ctx)
+
+	tests := []string{
+		"/users/{{ input:id + }}/orders",
+		"{{ input:token }} {{ upper( }}",
+	}
+
+	for _, input := range tests {
+		t.Run(input, func(t *testing.T) {
+			_, err := ParseTemplate(ctx, "path", nil, input)
+			if err == nil {
+				t.Fatal("expected interpolation to fail")
+			}
+		})
+	}
+}
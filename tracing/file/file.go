@@ -0,0 +1,56 @@
+package file
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"sync"
+
+	"github.com/jexia/maestro/tracing"
+)
+
+// Open opens or creates the trace file at the given path.
+// Finished spans are appended to the file as JSON lines.
+func Open(path string) (*Exporter, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Exporter{
+		file: file,
+	}
+
+	return result, nil
+}
+
+// Exporter represents a span exporter writing spans to a local file
+type Exporter struct {
+	file  *os.File
+	mutex sync.Mutex
+}
+
+// Context sets the given context as the active management context
+func (exporter *Exporter) Context(ctx context.Context) {}
+
+// Export encodes and appends the given span to the trace file
+func (exporter *Exporter) Export(span *tracing.Span) error {
+	bb, err := json.Marshal(span)
+	if err != nil {
+		return err
+	}
+
+	exporter.mutex.Lock()
+	defer exporter.mutex.Unlock()
+
+	_, err = exporter.file.Write(append(bb, '\n'))
+	return err
+}
+
+// Close closes the trace file
+func (exporter *Exporter) Close() error {
+	exporter.mutex.Lock()
+	defer exporter.mutex.Unlock()
+
+	return exporter.file.Close()
+}
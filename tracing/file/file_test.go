@@ -0,0 +1,78 @@
+package file
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jexia/maestro/tracing"
+)
+
+func TestExport(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tracing")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "spans.jsonl")
+	exporter, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tracer := tracing.NewTracer(exporter)
+	ctx, root := tracer.Start(context.Background(), "root", tracing.KindServer)
+	_, child := tracing.StartSpan(ctx, "child", tracing.KindInternal)
+
+	child.Finish()
+	root.Finish()
+
+	err = tracer.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer file.Close()
+
+	expected := []*tracing.Span{child, root}
+	scanner := bufio.NewScanner(file)
+	index := 0
+
+	for scanner.Scan() {
+		result := map[string]interface{}{}
+		err := json.Unmarshal(scanner.Bytes(), &result)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if index >= len(expected) {
+			t.Fatalf("unexpected span %s", scanner.Text())
+		}
+
+		if result["name"] != expected[index].Name {
+			t.Fatalf("unexpected span name %+v, expected %s", result["name"], expected[index].Name)
+		}
+
+		context := result["context"].(map[string]interface{})
+		if context["span_id"] != expected[index].Context.SpanID.String() {
+			t.Fatalf("unexpected span id %+v, expected %s", context["span_id"], expected[index].Context.SpanID)
+		}
+
+		index++
+	}
+
+	if index != len(expected) {
+		t.Fatalf("unexpected amount of spans %d, expected %d", index, len(expected))
+	}
+}
@@ -0,0 +1,283 @@
+package otlp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/jexia/maestro/logger"
+	"github.com/jexia/maestro/specs/trace"
+	"github.com/jexia/maestro/tracing"
+	"github.com/sirupsen/logrus"
+)
+
+// New constructs a new OTLP/HTTP exporter sending JSON encoded spans to the given endpoint (ex: http://localhost:4318/v1/traces).
+// Spans are send in batches once the batch size has been reached or the flush interval has passed.
+func New(endpoint string, service string) *Exporter {
+	exporter := &Exporter{
+		logger:    logger.FromCtx(logger.WithValue(context.Background()), logger.Core),
+		client:    &http.Client{Timeout: 10 * time.Second},
+		flush:     make(chan struct{}, 1),
+		close:     make(chan struct{}),
+		done:      make(chan struct{}),
+		Endpoint:  endpoint,
+		Service:   service,
+		BatchSize: 512,
+		Interval:  5 * time.Second,
+	}
+
+	go exporter.Loop()
+	return exporter
+}
+
+// Exporter represents a OTLP/HTTP span exporter
+type Exporter struct {
+	logger    *logrus.Logger
+	client    *http.Client
+	mutex     sync.Mutex
+	spans     []*tracing.Span
+	flush     chan struct{}
+	close     chan struct{}
+	done      chan struct{}
+	Endpoint  string
+	Service   string
+	BatchSize int
+	Interval  time.Duration
+}
+
+// Context sets the given context as the active management context
+func (exporter *Exporter) Context(ctx context.Context) {
+	exporter.mutex.Lock()
+	defer exporter.mutex.Unlock()
+
+	exporter.logger = logger.FromCtx(ctx, logger.Core)
+}
+
+// Export appends the given span to the pending batch
+func (exporter *Exporter) Export(span *tracing.Span) error {
+	exporter.mutex.Lock()
+	exporter.spans = append(exporter.spans, span)
+	full := len(exporter.spans) >= exporter.BatchSize
+	exporter.mutex.Unlock()
+
+	if full {
+		select {
+		case exporter.flush <- struct{}{}:
+		default:
+		}
+	}
+
+	return nil
+}
+
+// Loop sends the pending spans once the flush interval has passed or the batch is full
+func (exporter *Exporter) Loop() {
+	defer close(exporter.done)
+
+	ticker := time.NewTicker(exporter.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-exporter.flush:
+		case <-exporter.close:
+			exporter.Flush()
+			return
+		}
+
+		exporter.Flush()
+	}
+}
+
+// Flush sends all pending spans to the configured endpoint
+func (exporter *Exporter) Flush() error {
+	exporter.mutex.Lock()
+	spans := exporter.spans
+	logger := exporter.logger
+	exporter.spans = nil
+	exporter.mutex.Unlock()
+
+	if len(spans) == 0 {
+		return nil
+	}
+
+	err := exporter.Send(spans)
+	if err != nil {
+		logger.WithFields(logrus.Fields{
+			"endpoint": exporter.Endpoint,
+			"spans":    len(spans),
+			"err":      err,
+		}).Error("Unable to export spans")
+	}
+
+	return err
+}
+
+// Send encodes and sends the given spans to the configured endpoint
+func (exporter *Exporter) Send(spans []*tracing.Span) error {
+	bb, err := json.Marshal(NewRequest(exporter.Service, spans))
+	if err != nil {
+		return err
+	}
+
+	res, err := exporter.client.Post(exporter.Endpoint, "application/json", bytes.NewReader(bb))
+	if err != nil {
+		return err
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		return trace.New(trace.WithMessage("unexpected OTLP response status '%d'", res.StatusCode))
+	}
+
+	return nil
+}
+
+// Close sends all pending spans and stops the exporter
+func (exporter *Exporter) Close() error {
+	close(exporter.close)
+	<-exporter.done
+	return nil
+}
+
+// Span kinds and status codes as defined inside the OTLP specification
+const (
+	KindInternal = 1
+	KindServer   = 2
+	KindClient   = 3
+
+	StatusUnset = 0
+	StatusError = 2
+)
+
+// Request represents a OTLP trace export request
+type Request struct {
+	ResourceSpans []ResourceSpans `json:"resourceSpans"`
+}
+
+// ResourceSpans represents a collection of spans produced by a single resource
+type ResourceSpans struct {
+	Resource   Resource     `json:"resource"`
+	ScopeSpans []ScopeSpans `json:"scopeSpans"`
+}
+
+// Resource represents the entity producing the spans
+type Resource struct {
+	Attributes []Attribute `json:"attributes"`
+}
+
+// ScopeSpans represents a collection of spans produced by a single instrumentation scope
+type ScopeSpans struct {
+	Scope Scope  `json:"scope"`
+	Spans []Span `json:"spans"`
+}
+
+// Scope represents a instrumentation scope
+type Scope struct {
+	Name string `json:"name"`
+}
+
+// Span represents a OTLP span
+type Span struct {
+	TraceID           string      `json:"traceId"`
+	SpanID            string      `json:"spanId"`
+	ParentSpanID      string      `json:"parentSpanId,omitempty"`
+	Name              string      `json:"name"`
+	Kind              int         `json:"kind"`
+	StartTimeUnixNano string      `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string      `json:"endTimeUnixNano"`
+	Attributes        []Attribute `json:"attributes,omitempty"`
+	Status            Status      `json:"status"`
+}
+
+// Attribute represents a OTLP key value attribute
+type Attribute struct {
+	Key   string `json:"key"`
+	Value Value  `json:"value"`
+}
+
+// Value represents a OTLP attribute value
+type Value struct {
+	StringValue string `json:"stringValue"`
+}
+
+// Status represents a OTLP span status
+type Status struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+// NewRequest constructs a new OTLP trace export request for the given service and spans
+func NewRequest(service string, spans []*tracing.Span) *Request {
+	result := make([]Span, len(spans))
+
+	for index, span := range spans {
+		result[index] = NewSpan(span)
+	}
+
+	return &Request{
+		ResourceSpans: []ResourceSpans{
+			{
+				Resource: Resource{
+					Attributes: []Attribute{
+						{Key: "service.name", Value: Value{StringValue: service}},
+					},
+				},
+				ScopeSpans: []ScopeSpans{
+					{
+						Scope: Scope{Name: "maestro"},
+						Spans: result,
+					},
+				},
+			},
+		},
+	}
+}
+
+// NewSpan converts the given span into a OTLP span
+func NewSpan(span *tracing.Span) Span {
+	result := Span{
+		TraceID:           span.Context.TraceID.String(),
+		SpanID:            span.Context.SpanID.String(),
+		Name:              span.Name,
+		Kind:              KindInternal,
+		StartTimeUnixNano: strconv.FormatInt(span.Start.UnixNano(), 10),
+		EndTimeUnixNano:   strconv.FormatInt(span.End.UnixNano(), 10),
+		Status:            Status{Code: StatusUnset},
+	}
+
+	if span.Parent.IsValid() {
+		result.ParentSpanID = span.Parent.String()
+	}
+
+	switch span.Kind {
+	case tracing.KindServer:
+		result.Kind = KindServer
+	case tracing.KindClient:
+		result.Kind = KindClient
+	}
+
+	if span.Error != "" {
+		result.Status = Status{Code: StatusError, Message: span.Error}
+	}
+
+	keys := make([]string, 0, len(span.Attributes))
+	for key := range span.Attributes {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		result.Attributes = append(result.Attributes, Attribute{Key: key, Value: Value{StringValue: span.Attributes[key]}})
+	}
+
+	return result
+}
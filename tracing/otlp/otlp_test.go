@@ -0,0 +1,97 @@
+package otlp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/jexia/maestro/tracing"
+)
+
+func TestClose(t *testing.T) {
+	mutex := sync.Mutex{}
+	requests := []*Request{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request := &Request{}
+		err := json.NewDecoder(r.Body).Decode(request)
+		if err != nil {
+			t.Error(err)
+		}
+
+		mutex.Lock()
+		requests = append(requests, request)
+		mutex.Unlock()
+	}))
+
+	defer server.Close()
+
+	exporter := New(server.URL, "maestro")
+	tracer := tracing.NewTracer(exporter)
+
+	ctx, root := tracer.Start(context.Background(), "checkout", tracing.KindServer)
+	_, child := tracing.StartSpan(ctx, "payment", tracing.KindClient)
+	child.SetAttribute("method", "Charge")
+	child.SetError(errors.New("unexpected err"))
+
+	child.Finish()
+	root.Finish()
+
+	err := tracer.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(requests) != 1 {
+		t.Fatalf("unexpected amount of requests %d, expected 1", len(requests))
+	}
+
+	resource := requests[0].ResourceSpans[0]
+	if resource.Resource.Attributes[0].Value.StringValue != "maestro" {
+		t.Fatalf("unexpected service name %+v", resource.Resource.Attributes)
+	}
+
+	spans := resource.ScopeSpans[0].Spans
+	if len(spans) != 2 {
+		t.Fatalf("unexpected amount of spans %d, expected 2", len(spans))
+	}
+
+	if spans[0].ParentSpanID != spans[1].SpanID || spans[0].TraceID != spans[1].TraceID {
+		t.Fatalf("unexpected child span %+v", spans[0])
+	}
+
+	if spans[0].Kind != KindClient || spans[1].Kind != KindServer {
+		t.Fatalf("unexpected span kinds %d and %d", spans[0].Kind, spans[1].Kind)
+	}
+
+	if spans[0].Status.Code != StatusError || spans[0].Status.Message != "unexpected err" {
+		t.Fatalf("unexpected span status %+v", spans[0].Status)
+	}
+
+	if len(spans[0].Attributes) != 1 || spans[0].Attributes[0].Key != "method" {
+		t.Fatalf("unexpected span attributes %+v", spans[0].Attributes)
+	}
+}
+
+func TestSendUnexpectedStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+
+	defer server.Close()
+
+	exporter := New(server.URL, "maestro")
+	defer exporter.Close()
+
+	_, span := tracing.NewTracer(exporter).Start(context.Background(), "checkout", tracing.KindServer)
+	span.Finish()
+
+	err := exporter.Send([]*tracing.Span{span})
+	if err == nil {
+		t.Fatal("unexpected pass")
+	}
+}
@@ -0,0 +1,319 @@
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jexia/maestro/metadata"
+	"github.com/jexia/maestro/specs/trace"
+)
+
+// TraceparentHeader represents the W3C trace context header key
+const TraceparentHeader = "traceparent"
+
+// Kind represents the span kind
+type Kind string
+
+// Available span kinds
+const (
+	// KindInternal represents a internal operation such as a node execution
+	KindInternal Kind = "internal"
+	// KindServer represents the handling of a incoming request
+	KindServer Kind = "server"
+	// KindClient represents a outgoing call to a service
+	KindClient Kind = "client"
+)
+
+// TraceID represents a W3C trace id
+type TraceID [16]byte
+
+// IsValid checks whether the given trace id is not empty
+func (id TraceID) IsValid() bool {
+	return id != TraceID{}
+}
+
+// String returns the hex encoded trace id
+func (id TraceID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// MarshalText encodes the trace id as hex
+func (id TraceID) MarshalText() ([]byte, error) {
+	return []byte(id.String()), nil
+}
+
+// SpanID represents a W3C span (parent) id
+type SpanID [8]byte
+
+// IsValid checks whether the given span id is not empty
+func (id SpanID) IsValid() bool {
+	return id != SpanID{}
+}
+
+// String returns the hex encoded span id
+func (id SpanID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// MarshalText encodes the span id as hex
+func (id SpanID) MarshalText() ([]byte, error) {
+	if !id.IsValid() {
+		return []byte{}, nil
+	}
+
+	return []byte(id.String()), nil
+}
+
+// SpanContext represents the propagated part of a span
+type SpanContext struct {
+	TraceID TraceID `json:"trace_id"`
+	SpanID  SpanID  `json:"span_id"`
+	Sampled bool    `json:"-"`
+}
+
+// IsValid checks whether the given span context contains a trace and span id
+func (span SpanContext) IsValid() bool {
+	return span.TraceID.IsValid() && span.SpanID.IsValid()
+}
+
+// Traceparent returns the W3C traceparent header value of the given span context
+func (span SpanContext) Traceparent() string {
+	flags := "00"
+	if span.Sampled {
+		flags = "01"
+	}
+
+	return "00-" + span.TraceID.String() + "-" + span.SpanID.String() + "-" + flags
+}
+
+// ParseTraceparent parses the given W3C traceparent header value
+func ParseTraceparent(value string) (SpanContext, error) {
+	result := SpanContext{}
+	parts := strings.Split(strings.TrimSpace(value), "-")
+
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" || len(parts[1]) != 32 || len(parts[2]) != 16 || len(parts[3]) != 2 {
+		return result, trace.New(trace.WithMessage("invalid traceparent '%s'", value))
+	}
+
+	_, err := hex.Decode(result.TraceID[:], []byte(parts[1]))
+	if err != nil {
+		return result, err
+	}
+
+	_, err = hex.Decode(result.SpanID[:], []byte(parts[2]))
+	if err != nil {
+		return result, err
+	}
+
+	flags, err := hex.DecodeString(parts[3])
+	if err != nil {
+		return result, err
+	}
+
+	result.Sampled = flags[0]&1 == 1
+
+	if !result.IsValid() {
+		return result, trace.New(trace.WithMessage("invalid traceparent '%s', trace and span id should not be empty", value))
+	}
+
+	return result, nil
+}
+
+// Exporter exports finished spans
+type Exporter interface {
+	// Context sets the given context as the active management context
+	Context(ctx context.Context)
+	// Export exports the given finished span
+	Export(span *Span) error
+	// Close flushes all pending spans and closes the exporter
+	Close() error
+}
+
+// NewTracer constructs a new tracer exporting spans to the given exporter
+func NewTracer(exporter Exporter) *Tracer {
+	return &Tracer{
+		exporter: exporter,
+	}
+}
+
+// Tracer constructs new spans.
+// All methods are no-ops when called on a nil tracer.
+type Tracer struct {
+	exporter Exporter
+}
+
+// Start starts a new span with the given name.
+// The span is a child of the span or remote span context found inside the given context.
+// A new trace is started when no parent is found.
+func (tracer *Tracer) Start(ctx context.Context, name string, kind Kind) (context.Context, *Span) {
+	if tracer == nil {
+		return ctx, nil
+	}
+
+	parent := SpanContextFromCtx(ctx)
+
+	span := &Span{
+		tracer:     tracer,
+		Name:       name,
+		Kind:       kind,
+		Start:      time.Now(),
+		Attributes: make(map[string]string),
+		Context: SpanContext{
+			TraceID: parent.TraceID,
+			Sampled: true,
+		},
+	}
+
+	if parent.IsValid() {
+		span.Parent = parent.SpanID
+		span.Context.Sampled = parent.Sampled
+	} else {
+		rand.Read(span.Context.TraceID[:])
+	}
+
+	rand.Read(span.Context.SpanID[:])
+
+	return WithSpan(ctx, span), span
+}
+
+// Close closes the tracer exporter
+func (tracer *Tracer) Close() error {
+	if tracer == nil || tracer.exporter == nil {
+		return nil
+	}
+
+	return tracer.exporter.Close()
+}
+
+// StartSpan starts a new child span of the span found inside the given context.
+// No span is started when the given context does not contain a span.
+func StartSpan(ctx context.Context, name string, kind Kind) (context.Context, *Span) {
+	parent := SpanFromCtx(ctx)
+	if parent == nil {
+		return ctx, nil
+	}
+
+	return parent.tracer.Start(ctx, name, kind)
+}
+
+// Span represents a single timed operation inside a trace.
+// All methods are no-ops when called on a nil span.
+type Span struct {
+	tracer     *Tracer
+	mutex      sync.Mutex
+	Name       string            `json:"name"`
+	Kind       Kind              `json:"kind"`
+	Context    SpanContext       `json:"context"`
+	Parent     SpanID            `json:"parent_id,omitempty"`
+	Start      time.Time         `json:"start"`
+	End        time.Time         `json:"end"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+	Error      string            `json:"error,omitempty"`
+}
+
+// SetAttribute sets the given attribute on the span
+func (span *Span) SetAttribute(key string, value string) {
+	if span == nil {
+		return
+	}
+
+	span.mutex.Lock()
+	defer span.mutex.Unlock()
+
+	span.Attributes[key] = value
+}
+
+// SetError marks the span as failed with the given error
+func (span *Span) SetError(err error) {
+	if span == nil || err == nil {
+		return
+	}
+
+	span.mutex.Lock()
+	defer span.mutex.Unlock()
+
+	span.Error = err.Error()
+}
+
+// Finish ends the span and exports it if sampled
+func (span *Span) Finish() {
+	if span == nil {
+		return
+	}
+
+	span.mutex.Lock()
+	span.End = time.Now()
+	span.mutex.Unlock()
+
+	if !span.Context.Sampled || span.tracer.exporter == nil {
+		return
+	}
+
+	span.tracer.exporter.Export(span)
+}
+
+type spanKey struct{}
+
+type remoteKey struct{}
+
+// WithSpan returns a copy of the given context containing the given span
+func WithSpan(ctx context.Context, span *Span) context.Context {
+	return context.WithValue(ctx, spanKey{}, span)
+}
+
+// SpanFromCtx returns the span stored inside the given context.
+// Nil is returned when no span has been stored.
+func SpanFromCtx(ctx context.Context) *Span {
+	span, _ := ctx.Value(spanKey{}).(*Span)
+	return span
+}
+
+// WithRemote returns a copy of the given context containing the given remote span context
+func WithRemote(ctx context.Context, remote SpanContext) context.Context {
+	return context.WithValue(ctx, remoteKey{}, remote)
+}
+
+// SpanContextFromCtx returns the span context of the active span inside the given context.
+// The remote span context is returned when no span has been started yet.
+func SpanContextFromCtx(ctx context.Context) SpanContext {
+	span := SpanFromCtx(ctx)
+	if span != nil {
+		return span.Context
+	}
+
+	remote, _ := ctx.Value(remoteKey{}).(SpanContext)
+	return remote
+}
+
+// Inject sets the traceparent header of the active span inside the given metadata
+func Inject(ctx context.Context, md metadata.MD) {
+	current := SpanContextFromCtx(ctx)
+	if !current.IsValid() {
+		return
+	}
+
+	md[TraceparentHeader] = current.Traceparent()
+}
+
+// Extract returns a copy of the given context containing the remote span context found inside the given metadata.
+// Header keys are matched case insensitive, invalid traceparent headers are ignored.
+func Extract(ctx context.Context, md metadata.MD) context.Context {
+	for key, value := range md {
+		if !strings.EqualFold(key, TraceparentHeader) {
+			continue
+		}
+
+		remote, err := ParseTraceparent(value)
+		if err != nil {
+			return ctx
+		}
+
+		return WithRemote(ctx, remote)
+	}
+
+	return ctx
+}
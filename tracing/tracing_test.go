@@ -0,0 +1,201 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/jexia/maestro/metadata"
+)
+
+type memory struct {
+	mutex sync.Mutex
+	spans []*Span
+}
+
+func (exporter *memory) Context(ctx context.Context) {}
+
+func (exporter *memory) Export(span *Span) error {
+	exporter.mutex.Lock()
+	defer exporter.mutex.Unlock()
+
+	exporter.spans = append(exporter.spans, span)
+	return nil
+}
+
+func (exporter *memory) Close() error {
+	return nil
+}
+
+func TestParseTraceparent(t *testing.T) {
+	value := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+	result, err := ParseTraceparent(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.TraceID.String() != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Fatalf("unexpected trace id %s", result.TraceID)
+	}
+
+	if result.SpanID.String() != "00f067aa0ba902b7" {
+		t.Fatalf("unexpected span id %s", result.SpanID)
+	}
+
+	if !result.Sampled {
+		t.Fatal("span context expected to be sampled")
+	}
+
+	if result.Traceparent() != value {
+		t.Fatalf("unexpected traceparent %s, expected %s", result.Traceparent(), value)
+	}
+}
+
+func TestParseInvalidTraceparent(t *testing.T) {
+	tests := map[string]string{
+		"empty":          "",
+		"version":        "ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"short trace id": "00-4bf92f3577b34da6-00f067aa0ba902b7-01",
+		"short span id":  "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa-01",
+		"hex":            "00-4bf92f3577b34da6a3ce929d0e0e47zz-00f067aa0ba902b7-01",
+		"empty trace id": "00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"empty span id":  "00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+	}
+
+	for name, value := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseTraceparent(value)
+			if err == nil {
+				t.Fatal("unexpected pass")
+			}
+		})
+	}
+}
+
+func TestStart(t *testing.T) {
+	exporter := &memory{}
+	tracer := NewTracer(exporter)
+
+	ctx, root := tracer.Start(context.Background(), "root", KindServer)
+	_, child := StartSpan(ctx, "child", KindInternal)
+
+	if !root.Context.IsValid() {
+		t.Fatal("root span context is not valid")
+	}
+
+	if root.Parent.IsValid() {
+		t.Fatal("unexpected root span parent")
+	}
+
+	if child.Context.TraceID != root.Context.TraceID {
+		t.Fatalf("unexpected child trace id %s, expected %s", child.Context.TraceID, root.Context.TraceID)
+	}
+
+	if child.Parent != root.Context.SpanID {
+		t.Fatalf("unexpected child parent %s, expected %s", child.Parent, root.Context.SpanID)
+	}
+
+	child.SetError(errors.New("unexpected err"))
+	child.Finish()
+	root.Finish()
+
+	if len(exporter.spans) != 2 {
+		t.Fatalf("unexpected amount of exported spans %d, expected 2", len(exporter.spans))
+	}
+
+	if exporter.spans[0].Error != "unexpected err" {
+		t.Fatalf("unexpected span error %s", exporter.spans[0].Error)
+	}
+}
+
+func TestStartRemote(t *testing.T) {
+	remote, err := ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exporter := &memory{}
+	tracer := NewTracer(exporter)
+
+	_, span := tracer.Start(WithRemote(context.Background(), remote), "root", KindServer)
+
+	if span.Context.TraceID != remote.TraceID {
+		t.Fatalf("unexpected trace id %s, expected %s", span.Context.TraceID, remote.TraceID)
+	}
+
+	if span.Parent != remote.SpanID {
+		t.Fatalf("unexpected parent %s, expected %s", span.Parent, remote.SpanID)
+	}
+
+	span.Finish()
+
+	if len(exporter.spans) != 0 {
+		t.Fatal("unexpected export of a unsampled span")
+	}
+}
+
+func TestStartSpanWithoutParent(t *testing.T) {
+	ctx := context.Background()
+	result, span := StartSpan(ctx, "child", KindInternal)
+
+	if span != nil {
+		t.Fatal("unexpected span")
+	}
+
+	if result != ctx {
+		t.Fatal("unexpected context")
+	}
+
+	span.SetAttribute("key", "value")
+	span.SetError(errors.New("unexpected err"))
+	span.Finish()
+}
+
+func TestNilTracer(t *testing.T) {
+	var tracer *Tracer
+
+	_, span := tracer.Start(context.Background(), "root", KindServer)
+	if span != nil {
+		t.Fatal("unexpected span")
+	}
+
+	err := tracer.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestInjectExtract(t *testing.T) {
+	tracer := NewTracer(&memory{})
+	ctx, span := tracer.Start(context.Background(), "root", KindClient)
+
+	md := metadata.MD{}
+	Inject(ctx, md)
+
+	if md[TraceparentHeader] != span.Context.Traceparent() {
+		t.Fatalf("unexpected traceparent %s, expected %s", md[TraceparentHeader], span.Context.Traceparent())
+	}
+
+	remote := SpanContextFromCtx(Extract(context.Background(), metadata.MD{"Traceparent": md[TraceparentHeader]}))
+	if remote != span.Context {
+		t.Fatalf("unexpected remote span context %+v, expected %+v", remote, span.Context)
+	}
+}
+
+func TestExtractInvalid(t *testing.T) {
+	ctx := Extract(context.Background(), metadata.MD{TraceparentHeader: "invalid"})
+	if SpanContextFromCtx(ctx).IsValid() {
+		t.Fatal("unexpected valid span context")
+	}
+}
+
+func TestInjectWithoutSpan(t *testing.T) {
+	md := metadata.MD{}
+	Inject(context.Background(), md)
+
+	if len(md) != 0 {
+		t.Fatalf("unexpected metadata %+v", md)
+	}
+}
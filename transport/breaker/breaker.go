@@ -0,0 +1,286 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/jexia/maestro/logger"
+	"github.com/jexia/maestro/refs"
+	"github.com/jexia/maestro/schema"
+	"github.com/jexia/maestro/transport"
+	"github.com/sirupsen/logrus"
+)
+
+// ErrOpen is returned when a call is rejected by a open circuit breaker
+var ErrOpen error = &transport.Error{Code: transport.CodeUnavailable, Message: "circuit breaker is open", Retryable: true}
+
+// State represents the state of a circuit breaker
+type State int
+
+const (
+	// Closed allows all calls and keeps track of the failure ratio
+	Closed State = iota
+	// Open rejects all calls until the cool-down has passed
+	Open
+	// HalfOpen allows a limited amount of probe calls to check whether the service has recovered
+	HalfOpen
+)
+
+func (state State) String() string {
+	switch state {
+	case Open:
+		return "open"
+	case HalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+// Breakers represents a collection of circuit breakers.
+// A single circuit breaker is shared by all calls to the same service.
+type Breakers map[string]*Breaker
+
+// Get returns the circuit breaker for the given service.
+// A new circuit breaker is constructed if the service has not been seen before.
+// Nil is returned when the circuit breaker has not been enabled inside the given options.
+func (breakers Breakers) Get(ctx context.Context, service string, options schema.Options) (*Breaker, error) {
+	if breakers[service] != nil {
+		return breakers[service], nil
+	}
+
+	opts, err := ParseOptions(options)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts == nil {
+		return nil, nil
+	}
+
+	breaker := New(ctx, service, opts)
+	breakers[service] = breaker
+
+	return breaker, nil
+}
+
+// New constructs a new closed circuit breaker for the given service
+func New(ctx context.Context, service string, options *Options) *Breaker {
+	return &Breaker{
+		logger:  logger.FromCtx(ctx, logger.Transport),
+		now:     time.Now,
+		Service: service,
+		Options: options,
+	}
+}
+
+// Breaker represents a circuit breaker.
+// The circuit breaker opens once the failure ratio within the configured window has been exceeded.
+// Once the cool-down has passed is the breaker half-open and are a limited amount of probe calls allowed.
+// The breaker is closed once all probe calls succeed and opened again once a probe call fails.
+type Breaker struct {
+	logger     *logrus.Logger
+	now        func() time.Time
+	mutex      sync.Mutex
+	Service    string
+	Options    *Options
+	state      State
+	generation int
+	expires    time.Time
+	requests   int
+	failures   int
+	probes     int
+	successes  int
+}
+
+// State returns the current circuit breaker state
+func (breaker *Breaker) State() State {
+	breaker.mutex.Lock()
+	defer breaker.mutex.Unlock()
+
+	breaker.Refresh()
+	return breaker.state
+}
+
+// Execute calls the given function if the circuit breaker allows it and reports the result.
+// ErrOpen is returned when the call has been rejected.
+func (breaker *Breaker) Execute(fn func() error) error {
+	generation, err := breaker.Allow()
+	if err != nil {
+		return err
+	}
+
+	err = fn()
+	breaker.Report(generation, err)
+
+	return err
+}
+
+// Allow checks whether a new call is allowed and returns the generation in which the call is made
+func (breaker *Breaker) Allow() (int, error) {
+	breaker.mutex.Lock()
+	defer breaker.mutex.Unlock()
+
+	breaker.Refresh()
+
+	switch breaker.state {
+	case Open:
+		return breaker.generation, ErrOpen
+	case HalfOpen:
+		if breaker.probes >= breaker.Options.HalfOpenRequests {
+			return breaker.generation, ErrOpen
+		}
+
+		breaker.probes++
+	}
+
+	return breaker.generation, nil
+}
+
+// Report reports the result of a call made in the given generation.
+// Results of calls made in a previous generation are ignored.
+func (breaker *Breaker) Report(generation int, err error) {
+	breaker.mutex.Lock()
+	defer breaker.mutex.Unlock()
+
+	breaker.Refresh()
+
+	if generation != breaker.generation {
+		return
+	}
+
+	failed := IsFailure(err)
+
+	switch breaker.state {
+	case Closed:
+		breaker.requests++
+		if failed {
+			breaker.failures++
+		}
+
+		if breaker.requests >= breaker.Options.MinRequests && float64(breaker.failures)/float64(breaker.requests) >= breaker.Options.FailureRatio {
+			breaker.Transition(Open)
+		}
+	case HalfOpen:
+		if failed {
+			breaker.Transition(Open)
+			return
+		}
+
+		breaker.successes++
+		if breaker.successes >= breaker.Options.HalfOpenRequests {
+			breaker.Transition(Closed)
+		}
+	}
+}
+
+// Refresh moves the circuit breaker into the half-open state once the cool-down has passed
+// and resets the closed counters once the window has passed.
+// The circuit breaker mutex should be locked when calling this method.
+func (breaker *Breaker) Refresh() {
+	now := breaker.now()
+
+	switch breaker.state {
+	case Open:
+		if !now.Before(breaker.expires) {
+			breaker.Transition(HalfOpen)
+		}
+	case Closed:
+		if !now.Before(breaker.expires) {
+			breaker.Reset(now.Add(breaker.Options.Window))
+		}
+	}
+}
+
+// Transition moves the circuit breaker into the given state and starts a new generation.
+// The circuit breaker mutex should be locked when calling this method.
+func (breaker *Breaker) Transition(state State) {
+	breaker.logger.WithFields(logrus.Fields{
+		"service": breaker.Service,
+		"from":    breaker.state,
+		"to":      state,
+	}).Warn("Circuit breaker state changed")
+
+	now := breaker.now()
+
+	breaker.state = state
+	breaker.generation++
+
+	switch state {
+	case Open:
+		breaker.Reset(now.Add(breaker.Options.Cooldown))
+	case Closed:
+		breaker.Reset(now.Add(breaker.Options.Window))
+	case HalfOpen:
+		breaker.Reset(time.Time{})
+	}
+}
+
+// Reset resets all counters and sets the given expiration time.
+// The circuit breaker mutex should be locked when calling this method.
+func (breaker *Breaker) Reset(expires time.Time) {
+	breaker.expires = expires
+	breaker.requests = 0
+	breaker.failures = 0
+	breaker.probes = 0
+	breaker.successes = 0
+}
+
+// IsFailure checks whether the given error should be counted as a failure.
+// Only timeouts, connection errors and server (5xx) or retryable upstream errors are counted as a failure.
+// Calls cancelled by the caller and client (4xx) upstream errors are not counted as a failure.
+func IsFailure(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	target := transport.AsError(err)
+
+	switch target.Code {
+	case transport.CodeTimeout, transport.CodeUnavailable:
+		return true
+	case transport.CodeUpstream:
+		return target.Status >= http.StatusInternalServerError || target.Retryable
+	}
+
+	return false
+}
+
+// NewCall wraps the given transport call, calls are rejected while the given circuit breaker is open
+func NewCall(call transport.Call, breaker *Breaker) transport.Call {
+	return &Call{
+		call:    call,
+		breaker: breaker,
+	}
+}
+
+// Call represents a transport call guarded by a circuit breaker
+type Call struct {
+	call    transport.Call
+	breaker *Breaker
+}
+
+// SendMsg calls the wrapped transport call if allowed by the circuit breaker
+func (call *Call) SendMsg(ctx context.Context, writer transport.ResponseWriter, request *transport.Request, refs *refs.Store) error {
+	return call.breaker.Execute(func() error {
+		return call.call.SendMsg(ctx, writer, request, refs)
+	})
+}
+
+// GetMethods returns the available methods within the wrapped transport call
+func (call *Call) GetMethods() []transport.Method {
+	return call.call.GetMethods()
+}
+
+// GetMethod attempts to return a method matching the given name
+func (call *Call) GetMethod(name string) transport.Method {
+	return call.call.GetMethod(name)
+}
+
+// Close closes the wrapped transport call
+func (call *Call) Close() error {
+	return call.call.Close()
+}
@@ -0,0 +1,322 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jexia/maestro/logger"
+	"github.com/jexia/maestro/refs"
+	"github.com/jexia/maestro/schema"
+	"github.com/jexia/maestro/transport"
+)
+
+type clock struct {
+	time time.Time
+}
+
+func (clock *clock) Now() time.Time {
+	return clock.time
+}
+
+func (clock *clock) Add(duration time.Duration) {
+	clock.time = clock.time.Add(duration)
+}
+
+func NewMockBreaker(options *Options) (*Breaker, *clock) {
+	ctx := logger.WithValue(context.Background())
+	clock := &clock{time: time.Now()}
+
+	breaker := New(ctx, "service", options)
+	breaker.now = clock.Now
+
+	return breaker, clock
+}
+
+func NewMockOptions() *Options {
+	return &Options{
+		FailureRatio:     0.5,
+		MinRequests:      4,
+		Window:           time.Minute,
+		Cooldown:         time.Second,
+		HalfOpenRequests: 1,
+	}
+}
+
+func TestBreakerOpens(t *testing.T) {
+	breaker, _ := NewMockBreaker(NewMockOptions())
+	expected := errors.New("unexpected err")
+
+	results := []error{nil, expected, nil, expected}
+	for _, result := range results {
+		breaker.Execute(func() error { return result })
+	}
+
+	if breaker.State() != Open {
+		t.Fatalf("unexpected state %s, expected %s", breaker.State(), Open)
+	}
+
+	called := false
+	err := breaker.Execute(func() error {
+		called = true
+		return nil
+	})
+
+	if err != ErrOpen {
+		t.Fatalf("unexpected err %v, expected %s", err, ErrOpen)
+	}
+
+	if called {
+		t.Fatal("call has been executed while the breaker is open")
+	}
+}
+
+func TestBreakerMinRequests(t *testing.T) {
+	breaker, _ := NewMockBreaker(NewMockOptions())
+	expected := errors.New("unexpected err")
+
+	for index := 0; index < 3; index++ {
+		breaker.Execute(func() error { return expected })
+	}
+
+	if breaker.State() != Closed {
+		t.Fatalf("unexpected state %s, expected %s", breaker.State(), Closed)
+	}
+}
+
+func TestBreakerWindow(t *testing.T) {
+	options := NewMockOptions()
+	breaker, clock := NewMockBreaker(options)
+	expected := errors.New("unexpected err")
+
+	for index := 0; index < 3; index++ {
+		breaker.Execute(func() error { return expected })
+	}
+
+	clock.Add(options.Window)
+	breaker.Execute(func() error { return expected })
+
+	if breaker.State() != Closed {
+		t.Fatalf("unexpected state %s, expected %s", breaker.State(), Closed)
+	}
+}
+
+func TestBreakerIgnoresCancelled(t *testing.T) {
+	breaker, _ := NewMockBreaker(NewMockOptions())
+
+	for index := 0; index < 4; index++ {
+		breaker.Execute(func() error { return context.Canceled })
+	}
+
+	if breaker.State() != Closed {
+		t.Fatalf("unexpected state %s, expected %s", breaker.State(), Closed)
+	}
+}
+
+func TestBreakerIgnoresClientErrors(t *testing.T) {
+	breaker, _ := NewMockBreaker(NewMockOptions())
+
+	for index := 0; index < 4; index++ {
+		breaker.Execute(func() error {
+			return &transport.Error{Code: transport.CodeUpstream, Status: http.StatusNotFound}
+		})
+	}
+
+	if breaker.State() != Closed {
+		t.Fatalf("unexpected state %s, expected %s", breaker.State(), Closed)
+	}
+}
+
+func TestIsFailure(t *testing.T) {
+	tests := map[string]struct {
+		err      error
+		expected bool
+	}{
+		"nil": {
+			err: nil,
+		},
+		"cancelled": {
+			err: context.Canceled,
+		},
+		"deadline exceeded": {
+			err:      context.DeadlineExceeded,
+			expected: true,
+		},
+		"connection": {
+			err:      errors.New("connection refused"),
+			expected: true,
+		},
+		"server error": {
+			err:      &transport.Error{Code: transport.CodeUpstream, Status: http.StatusBadGateway},
+			expected: true,
+		},
+		"retryable upstream": {
+			err:      &transport.Error{Code: transport.CodeUpstream, Status: http.StatusTooManyRequests, Retryable: true},
+			expected: true,
+		},
+		"client error": {
+			err: &transport.Error{Code: transport.CodeUpstream, Status: http.StatusBadRequest},
+		},
+		"overloaded": {
+			err: &transport.Error{Code: transport.CodeOverloaded},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			result := IsFailure(test.err)
+			if result != test.expected {
+				t.Fatalf("unexpected result %t, expected %t", result, test.expected)
+			}
+		})
+	}
+}
+
+func TestBreakerHalfOpen(t *testing.T) {
+	options := NewMockOptions()
+	breaker, clock := NewMockBreaker(options)
+	expected := errors.New("unexpected err")
+
+	for index := 0; index < 4; index++ {
+		breaker.Execute(func() error { return expected })
+	}
+
+	clock.Add(options.Cooldown)
+
+	if breaker.State() != HalfOpen {
+		t.Fatalf("unexpected state %s, expected %s", breaker.State(), HalfOpen)
+	}
+
+	generation, err := breaker.Allow()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = breaker.Allow()
+	if err != ErrOpen {
+		t.Fatalf("unexpected err %v, expected the second probe to be rejected", err)
+	}
+
+	breaker.Report(generation, nil)
+
+	if breaker.State() != Closed {
+		t.Fatalf("unexpected state %s, expected %s", breaker.State(), Closed)
+	}
+}
+
+func TestBreakerHalfOpenFailure(t *testing.T) {
+	options := NewMockOptions()
+	breaker, clock := NewMockBreaker(options)
+	expected := errors.New("unexpected err")
+
+	for index := 0; index < 4; index++ {
+		breaker.Execute(func() error { return expected })
+	}
+
+	clock.Add(options.Cooldown)
+	breaker.Execute(func() error { return expected })
+
+	if breaker.State() != Open {
+		t.Fatalf("unexpected state %s, expected %s", breaker.State(), Open)
+	}
+}
+
+func TestBreakerPreviousGeneration(t *testing.T) {
+	options := NewMockOptions()
+	breaker, clock := NewMockBreaker(options)
+	expected := errors.New("unexpected err")
+
+	generation, err := breaker.Allow()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for index := 0; index < 4; index++ {
+		breaker.Execute(func() error { return expected })
+	}
+
+	clock.Add(options.Cooldown)
+
+	breaker.Report(generation, nil)
+
+	if breaker.State() != HalfOpen {
+		t.Fatalf("unexpected state %s, expected the previous generation to be ignored", breaker.State())
+	}
+}
+
+func TestBreakers(t *testing.T) {
+	ctx := logger.WithValue(context.Background())
+	breakers := make(Breakers)
+
+	result, err := breakers.Get(ctx, "disabled", schema.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result != nil {
+		t.Fatal("unexpected circuit breaker for a service without circuit breaker")
+	}
+
+	options := schema.Options{EnabledOption: "true"}
+
+	first, err := breakers.Get(ctx, "service", options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	second, err := breakers.Get(ctx, "service", options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if first == nil || first != second {
+		t.Fatal("expected the circuit breaker to be shared")
+	}
+}
+
+type call struct {
+	Counter int
+	Err     error
+}
+
+func (call *call) SendMsg(context.Context, transport.ResponseWriter, *transport.Request, *refs.Store) error {
+	call.Counter++
+	return call.Err
+}
+
+func (call *call) GetMethods() []transport.Method {
+	return nil
+}
+
+func (call *call) GetMethod(string) transport.Method {
+	return nil
+}
+
+func (call *call) Close() error {
+	return nil
+}
+
+func TestCall(t *testing.T) {
+	options := NewMockOptions()
+	options.MinRequests = 1
+
+	breaker, _ := NewMockBreaker(options)
+	upstream := &call{Err: errors.New("unexpected err")}
+	guarded := NewCall(upstream, breaker)
+
+	err := guarded.SendMsg(context.Background(), nil, nil, nil)
+	if err != upstream.Err {
+		t.Fatalf("unexpected err %v, expected %s", err, upstream.Err)
+	}
+
+	err = guarded.SendMsg(context.Background(), nil, nil, nil)
+	if err != ErrOpen {
+		t.Fatalf("unexpected err %v, expected %s", err, ErrOpen)
+	}
+
+	if upstream.Counter != 1 {
+		t.Fatalf("unexpected upstream calls %d, expected %d", upstream.Counter, 1)
+	}
+}
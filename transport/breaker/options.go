@@ -0,0 +1,110 @@
+package breaker
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/jexia/maestro/schema"
+	"github.com/jexia/maestro/specs/trace"
+)
+
+const (
+	// EnabledOption represents the circuit breaker option key enabling the circuit breaker
+	EnabledOption = "circuit_breaker"
+	// FailureRatioOption represents the failure ratio option key
+	FailureRatioOption = "breaker_failure_ratio"
+	// MinRequestsOption represents the minimum requests option key
+	MinRequestsOption = "breaker_min_requests"
+	// WindowOption represents the failure window option key
+	WindowOption = "breaker_window"
+	// CooldownOption represents the cool-down option key
+	CooldownOption = "breaker_cooldown"
+	// HalfOpenRequestsOption represents the half-open requests option key
+	HalfOpenRequestsOption = "breaker_half_open_requests"
+)
+
+// Options represents the available circuit breaker options
+type Options struct {
+	FailureRatio     float64
+	MinRequests      int
+	Window           time.Duration
+	Cooldown         time.Duration
+	HalfOpenRequests int
+}
+
+// ParseOptions parses the given schema options into circuit breaker options.
+// Nil is returned when the circuit breaker has not been enabled.
+func ParseOptions(options schema.Options) (*Options, error) {
+	enabled, err := strconv.ParseBool(options[EnabledOption])
+	if err != nil || !enabled {
+		return nil, nil
+	}
+
+	result := &Options{
+		FailureRatio:     0.5,
+		MinRequests:      10,
+		Window:           60 * time.Second,
+		Cooldown:         30 * time.Second,
+		HalfOpenRequests: 1,
+	}
+
+	ratio, has := options[FailureRatioOption]
+	if has {
+		value, err := strconv.ParseFloat(ratio, 64)
+		if err != nil {
+			return nil, err
+		}
+
+		if value <= 0 || value > 1 {
+			return nil, trace.New(trace.WithMessage("invalid circuit breaker failure ratio '%s', expected a value between 0 and 1", ratio))
+		}
+
+		result.FailureRatio = value
+	}
+
+	minimum, has := options[MinRequestsOption]
+	if has {
+		value, err := strconv.Atoi(minimum)
+		if err != nil {
+			return nil, err
+		}
+
+		result.MinRequests = value
+	}
+
+	window, has := options[WindowOption]
+	if has {
+		duration, err := time.ParseDuration(window)
+		if err != nil {
+			return nil, err
+		}
+
+		result.Window = duration
+	}
+
+	cooldown, has := options[CooldownOption]
+	if has {
+		duration, err := time.ParseDuration(cooldown)
+		if err != nil {
+			return nil, err
+		}
+
+		result.Cooldown = duration
+	}
+
+	probes, has := options[HalfOpenRequestsOption]
+	if has {
+		value, err := strconv.Atoi(probes)
+		if err != nil {
+			return nil, err
+		}
+
+		if value < 1 {
+			return nil, trace.New(trace.WithMessage("invalid circuit breaker half-open requests '%s', expected at least one request", probes))
+		}
+
+		result.HalfOpenRequests = value
+	}
+
+	return result, nil
+}
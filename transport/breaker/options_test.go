@@ -0,0 +1,74 @@
+package breaker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jexia/maestro/schema"
+)
+
+func TestParseOptions(t *testing.T) {
+	options := schema.Options{
+		EnabledOption:          "true",
+		FailureRatioOption:     "0.25",
+		MinRequestsOption:      "20",
+		WindowOption:           "10s",
+		CooldownOption:         "5s",
+		HalfOpenRequestsOption: "3",
+	}
+
+	result, err := ParseOptions(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.FailureRatio != 0.25 {
+		t.Fatalf("unexpected failure ratio %f, expected %f", result.FailureRatio, 0.25)
+	}
+
+	if result.MinRequests != 20 {
+		t.Fatalf("unexpected min requests %d, expected %d", result.MinRequests, 20)
+	}
+
+	if result.Window != 10*time.Second {
+		t.Fatalf("unexpected window %s, expected %s", result.Window, 10*time.Second)
+	}
+
+	if result.Cooldown != 5*time.Second {
+		t.Fatalf("unexpected cooldown %s, expected %s", result.Cooldown, 5*time.Second)
+	}
+
+	if result.HalfOpenRequests != 3 {
+		t.Fatalf("unexpected half-open requests %d, expected %d", result.HalfOpenRequests, 3)
+	}
+}
+
+func TestParseOptionsDisabled(t *testing.T) {
+	result, err := ParseOptions(schema.Options{FailureRatioOption: "0.25"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result != nil {
+		t.Fatal("unexpected options for a disabled circuit breaker")
+	}
+}
+
+func TestParseOptionsInvalid(t *testing.T) {
+	tests := map[string]schema.Options{
+		"ratio":    {EnabledOption: "true", FailureRatioOption: "2"},
+		"minimum":  {EnabledOption: "true", MinRequestsOption: "unknown"},
+		"window":   {EnabledOption: "true", WindowOption: "unknown"},
+		"cooldown": {EnabledOption: "true", CooldownOption: "unknown"},
+		"probes":   {EnabledOption: "true", HalfOpenRequestsOption: "0"},
+	}
+
+	for name, options := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseOptions(options)
+			if err == nil {
+				t.Fatal("expected a error to be returned")
+			}
+		})
+	}
+}
@@ -0,0 +1,173 @@
+package bulkhead
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/jexia/maestro/refs"
+	"github.com/jexia/maestro/schema"
+	"github.com/jexia/maestro/transport"
+)
+
+var (
+	// ErrQueueFull is returned when a call is rejected because all slots are in use and the queue is full
+	ErrQueueFull error = &transport.Error{Code: transport.CodeOverloaded, Message: "bulkhead queue is full", Retryable: true}
+	// ErrQueueTimeout is returned when a queued call did not receive a slot before the queue timeout has been exceeded
+	ErrQueueTimeout error = &transport.Error{Code: transport.CodeOverloadTimeout, Message: "bulkhead queue timeout exceeded", Retryable: true}
+)
+
+// IsOverload checks whether the given error is caused by a overloaded bulkhead
+func IsOverload(err error) bool {
+	return errors.Is(err, ErrQueueFull) || errors.Is(err, ErrQueueTimeout)
+}
+
+// Bulkheads represents a collection of bulkheads.
+// A single bulkhead is shared by all calls to the same service.
+type Bulkheads map[string]*Bulkhead
+
+// Get returns the bulkhead for the given service.
+// A new bulkhead is constructed if the service has not been seen before.
+// Nil is returned when no concurrency limit has been defined inside the given options.
+func (bulkheads Bulkheads) Get(service string, options schema.Options) (*Bulkhead, error) {
+	if bulkheads[service] != nil {
+		return bulkheads[service], nil
+	}
+
+	opts, err := ParseOptions(options)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts == nil {
+		return nil, nil
+	}
+
+	bulkhead := New(service, opts)
+	bulkheads[service] = bulkhead
+
+	return bulkhead, nil
+}
+
+// New constructs a new bulkhead with the given name and options
+func New(name string, options *Options) *Bulkhead {
+	return &Bulkhead{
+		slots:   make(chan struct{}, options.MaxConcurrency),
+		Name:    name,
+		Options: options,
+	}
+}
+
+// Bulkhead limits the amount of concurrent calls.
+// Calls exceeding the maximum concurrency are queued until a slot is released or the queue timeout has been exceeded.
+// All methods are no-ops when called on a nil bulkhead.
+type Bulkhead struct {
+	slots   chan struct{}
+	mutex   sync.Mutex
+	queued  int
+	Name    string
+	Options *Options
+}
+
+// Acquire acquires a slot. The call is queued when all slots are in use.
+// ErrQueueFull is returned when the queue is full and ErrQueueTimeout once the queue timeout has been exceeded.
+// Acquired slots have to be released once the call has been completed.
+func (bulkhead *Bulkhead) Acquire(ctx context.Context) error {
+	if bulkhead == nil {
+		return nil
+	}
+
+	select {
+	case bulkhead.slots <- struct{}{}:
+		return nil
+	default:
+	}
+
+	bulkhead.mutex.Lock()
+	if bulkhead.queued >= bulkhead.Options.MaxQueue {
+		bulkhead.mutex.Unlock()
+		return ErrQueueFull
+	}
+
+	bulkhead.queued++
+	bulkhead.mutex.Unlock()
+
+	defer func() {
+		bulkhead.mutex.Lock()
+		bulkhead.queued--
+		bulkhead.mutex.Unlock()
+	}()
+
+	var timeout <-chan time.Time
+	if bulkhead.Options.QueueTimeout > 0 {
+		timer := time.NewTimer(bulkhead.Options.QueueTimeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case bulkhead.slots <- struct{}{}:
+		return nil
+	case <-timeout:
+		return ErrQueueTimeout
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Release releases a previously acquired slot
+func (bulkhead *Bulkhead) Release() {
+	if bulkhead == nil {
+		return
+	}
+
+	<-bulkhead.slots
+}
+
+// Execute calls the given function once a slot has been acquired
+func (bulkhead *Bulkhead) Execute(ctx context.Context, fn func() error) error {
+	err := bulkhead.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer bulkhead.Release()
+	return fn()
+}
+
+// NewCall wraps the given transport call, the amount of concurrent calls is limited by the given bulkhead
+func NewCall(call transport.Call, bulkhead *Bulkhead) transport.Call {
+	return &Call{
+		call:     call,
+		bulkhead: bulkhead,
+	}
+}
+
+// Call represents a transport call guarded by a bulkhead
+type Call struct {
+	call     transport.Call
+	bulkhead *Bulkhead
+}
+
+// SendMsg calls the wrapped transport call once a slot has been acquired
+func (call *Call) SendMsg(ctx context.Context, writer transport.ResponseWriter, request *transport.Request, refs *refs.Store) error {
+	return call.bulkhead.Execute(ctx, func() error {
+		return call.call.SendMsg(ctx, writer, request, refs)
+	})
+}
+
+// GetMethods returns the available methods within the wrapped transport call
+func (call *Call) GetMethods() []transport.Method {
+	return call.call.GetMethods()
+}
+
+// GetMethod attempts to return the method with the given name
+func (call *Call) GetMethod(name string) transport.Method {
+	return call.call.GetMethod(name)
+}
+
+// Close closes the wrapped transport call
+func (call *Call) Close() error {
+	return call.call.Close()
+}
@@ -0,0 +1,175 @@
+package bulkhead
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jexia/maestro/schema"
+)
+
+func TestAcquire(t *testing.T) {
+	bulkhead := New("service", &Options{MaxConcurrency: 2})
+	ctx := context.Background()
+
+	for index := 0; index < 2; index++ {
+		err := bulkhead.Acquire(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err := bulkhead.Acquire(ctx)
+	if err != ErrQueueFull {
+		t.Fatalf("unexpected err %v, expected %s", err, ErrQueueFull)
+	}
+
+	bulkhead.Release()
+
+	err = bulkhead.Acquire(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAcquireQueued(t *testing.T) {
+	bulkhead := New("service", &Options{MaxConcurrency: 1, MaxQueue: 1})
+	ctx := context.Background()
+
+	err := bulkhead.Acquire(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result := make(chan error, 1)
+	go func() {
+		result <- bulkhead.Acquire(ctx)
+	}()
+
+	// await till the call has been queued
+	for {
+		bulkhead.mutex.Lock()
+		queued := bulkhead.queued
+		bulkhead.mutex.Unlock()
+
+		if queued == 1 {
+			break
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+
+	err = bulkhead.Acquire(ctx)
+	if err != ErrQueueFull {
+		t.Fatalf("unexpected err %v, expected %s", err, ErrQueueFull)
+	}
+
+	bulkhead.Release()
+
+	err = <-result
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAcquireQueueTimeout(t *testing.T) {
+	bulkhead := New("service", &Options{MaxConcurrency: 1, MaxQueue: 1, QueueTimeout: 10 * time.Millisecond})
+	ctx := context.Background()
+
+	err := bulkhead.Acquire(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = bulkhead.Acquire(ctx)
+	if err != ErrQueueTimeout {
+		t.Fatalf("unexpected err %v, expected %s", err, ErrQueueTimeout)
+	}
+
+	if !IsOverload(err) {
+		t.Fatal("expected queue timeout to be a overload error")
+	}
+}
+
+func TestAcquireCancelled(t *testing.T) {
+	bulkhead := New("service", &Options{MaxConcurrency: 1, MaxQueue: 1})
+
+	err := bulkhead.Acquire(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err = bulkhead.Acquire(ctx)
+	if err != context.Canceled {
+		t.Fatalf("unexpected err %v, expected %s", err, context.Canceled)
+	}
+
+	if IsOverload(err) {
+		t.Fatal("unexpected overload error")
+	}
+}
+
+func TestNilBulkhead(t *testing.T) {
+	var bulkhead *Bulkhead
+
+	err := bulkhead.Execute(context.Background(), func() error {
+		return nil
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestExecute(t *testing.T) {
+	expected := errors.New("unexpected err")
+	bulkhead := New("service", &Options{MaxConcurrency: 1})
+
+	err := bulkhead.Execute(context.Background(), func() error {
+		return expected
+	})
+
+	if err != expected {
+		t.Fatalf("unexpected err %v, expected %s", err, expected)
+	}
+
+	// the slot should have been released
+	err = bulkhead.Execute(context.Background(), func() error {
+		return nil
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBulkheadsGet(t *testing.T) {
+	bulkheads := make(Bulkheads)
+
+	result, err := bulkheads.Get("service", schema.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result != nil {
+		t.Fatal("unexpected bulkhead")
+	}
+
+	first, err := bulkheads.Get("service", schema.Options{MaxConcurrencyOption: "1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	second, err := bulkheads.Get("service", schema.Options{MaxConcurrencyOption: "1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if first == nil || first != second {
+		t.Fatal("expected the same bulkhead to be returned for the same service")
+	}
+}
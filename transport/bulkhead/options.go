@@ -0,0 +1,70 @@
+package bulkhead
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/jexia/maestro/schema"
+	"github.com/jexia/maestro/specs/trace"
+)
+
+const (
+	// MaxConcurrencyOption represents the maximum amount of concurrent calls option key enabling the bulkhead
+	MaxConcurrencyOption = "max_concurrency"
+	// MaxQueueOption represents the maximum amount of queued calls option key
+	MaxQueueOption = "max_queue"
+	// QueueTimeoutOption represents the maximum queue duration option key
+	QueueTimeoutOption = "queue_timeout"
+)
+
+// Options represents the available bulkhead options
+type Options struct {
+	MaxConcurrency int
+	MaxQueue       int
+	QueueTimeout   time.Duration
+}
+
+// ParseOptions parses the given schema options into bulkhead options.
+// Nil is returned when no maximum concurrency has been defined.
+// A queue timeout could only be defined together with a max queue.
+func ParseOptions(options schema.Options) (*Options, error) {
+	concurrency, has := options[MaxConcurrencyOption]
+	if !has {
+		return nil, nil
+	}
+
+	result := &Options{}
+
+	value, err := strconv.Atoi(concurrency)
+	if err != nil || value < 1 {
+		return nil, trace.New(trace.WithMessage("invalid bulkhead max concurrency '%s', expected at least one concurrent call", concurrency))
+	}
+
+	result.MaxConcurrency = value
+
+	queue, has := options[MaxQueueOption]
+	if has {
+		value, err := strconv.Atoi(queue)
+		if err != nil || value < 0 {
+			return nil, trace.New(trace.WithMessage("invalid bulkhead max queue '%s', expected a positive number", queue))
+		}
+
+		result.MaxQueue = value
+	}
+
+	timeout, has := options[QueueTimeoutOption]
+	if has {
+		value, err := time.ParseDuration(timeout)
+		if err != nil {
+			return nil, err
+		}
+
+		result.QueueTimeout = value
+	}
+
+	if result.QueueTimeout > 0 && result.MaxQueue == 0 {
+		return nil, trace.New(trace.WithMessage("invalid bulkhead queue timeout '%s', a max queue is required to queue calls", timeout))
+	}
+
+	return result, nil
+}
@@ -0,0 +1,63 @@
+package bulkhead
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jexia/maestro/schema"
+)
+
+func TestParseOptions(t *testing.T) {
+	options := schema.Options{
+		MaxConcurrencyOption: "10",
+		MaxQueueOption:       "20",
+		QueueTimeoutOption:   "500ms",
+	}
+
+	result, err := ParseOptions(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.MaxConcurrency != 10 {
+		t.Fatalf("unexpected max concurrency %d, expected %d", result.MaxConcurrency, 10)
+	}
+
+	if result.MaxQueue != 20 {
+		t.Fatalf("unexpected max queue %d, expected %d", result.MaxQueue, 20)
+	}
+
+	if result.QueueTimeout != 500*time.Millisecond {
+		t.Fatalf("unexpected queue timeout %s, expected %s", result.QueueTimeout, 500*time.Millisecond)
+	}
+}
+
+func TestParseOptionsDisabled(t *testing.T) {
+	result, err := ParseOptions(schema.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result != nil {
+		t.Fatalf("unexpected options %+v", result)
+	}
+}
+
+func TestParseInvalidOptions(t *testing.T) {
+	tests := map[string]schema.Options{
+		"concurrency": {MaxConcurrencyOption: "none"},
+		"zero":        {MaxConcurrencyOption: "0"},
+		"queue":       {MaxConcurrencyOption: "1", MaxQueueOption: "-1"},
+		"timeout":     {MaxConcurrencyOption: "1", QueueTimeoutOption: "second"},
+		"no queue":    {MaxConcurrencyOption: "1", QueueTimeoutOption: "1s"},
+	}
+
+	for name, options := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseOptions(options)
+			if err == nil {
+				t.Fatal("unexpected pass")
+			}
+		})
+	}
+}
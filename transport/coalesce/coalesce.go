@@ -0,0 +1,169 @@
+package coalesce
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/jexia/maestro/metadata"
+	"github.com/jexia/maestro/schema"
+	"github.com/jexia/maestro/specs/trace"
+	"github.com/jexia/maestro/transport"
+)
+
+// ErrAborted is returned to the awaiting callers when the shared call has been aborted before a response was received
+var ErrAborted error = &transport.Error{Code: transport.CodeUnavailable, Message: "coalesced call aborted", Retryable: true}
+
+const (
+	// EnabledOption represents the option key enabling request coalescing
+	EnabledOption = "coalesce"
+	// TimeoutOption represents the option key defining the maximum duration of a shared call
+	TimeoutOption = "coalesce_timeout"
+)
+
+// DefaultTimeout represents the default maximum duration of a shared call
+const DefaultTimeout = 30 * time.Second
+
+// Enabled checks whether request coalescing has been enabled inside the given service or method options.
+// Method options take precedence over service options.
+func Enabled(service schema.Options, method schema.Options) (bool, error) {
+	value, has := method[EnabledOption]
+	if !has {
+		value, has = service[EnabledOption]
+	}
+
+	if !has {
+		return false, nil
+	}
+
+	enabled, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, trace.New(trace.WithMessage("invalid coalesce option '%s', expected a boolean", value))
+	}
+
+	return enabled, nil
+}
+
+// Timeout returns the maximum duration of a shared call defined inside the given service or method options.
+// Method options take precedence over service options.
+func Timeout(service schema.Options, method schema.Options) (time.Duration, error) {
+	value, has := method[TimeoutOption]
+	if !has {
+		value, has = service[TimeoutOption]
+	}
+
+	if !has {
+		return DefaultTimeout, nil
+	}
+
+	timeout, err := time.ParseDuration(value)
+	if err != nil || timeout <= 0 {
+		return 0, trace.New(trace.WithMessage("invalid coalesce timeout option '%s', expected a positive duration", value))
+	}
+
+	return timeout, nil
+}
+
+// Groups represents a collection of coalescing groups identified by their service method
+type Groups map[string]*Group
+
+// Get returns the coalescing group for the given service method.
+// A new group is constructed if the service method has not been seen before.
+// Nil is returned when request coalescing has not been enabled inside the given options.
+func (groups Groups) Get(service string, method string, serviceOptions schema.Options, methodOptions schema.Options) (*Group, error) {
+	key := service + "." + method
+	if groups[key] != nil {
+		return groups[key], nil
+	}
+
+	enabled, err := Enabled(serviceOptions, methodOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	if !enabled {
+		return nil, nil
+	}
+
+	timeout, err := Timeout(serviceOptions, methodOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	group := NewGroup()
+	group.Timeout = timeout
+	groups[key] = group
+
+	return group, nil
+}
+
+// Response represents a buffered service response shared with all coalesced callers
+type Response struct {
+	Header metadata.MD
+	Status int
+	Body   []byte
+}
+
+// NewGroup constructs a new coalescing group
+func NewGroup() *Group {
+	return &Group{
+		calls:   make(map[string]*call),
+		Timeout: DefaultTimeout,
+	}
+}
+
+// Group deduplicates concurrent calls sharing the same key.
+// The call is executed once on a context detached from the callers, all callers await and receive its response.
+// Callers stop awaiting once their own context is done without affecting the shared call or the other callers.
+type Group struct {
+	mutex   sync.Mutex
+	calls   map[string]*call
+	Timeout time.Duration
+}
+
+type call struct {
+	done     chan struct{}
+	response *Response
+	err      error
+}
+
+// Do executes the given function once for all concurrent callers sharing the given key.
+// The function is called with a context holding the values of the given context which is not cancelled
+// when the given context is done, the shared call is bounded by the group timeout instead.
+// The returned boolean reports whether the response has been received from another caller.
+func (group *Group) Do(ctx context.Context, key string, fn func(context.Context) (*Response, error)) (*Response, bool, error) {
+	group.mutex.Lock()
+
+	pending, shared := group.calls[key]
+	if !shared {
+		pending = &call{done: make(chan struct{}), err: ErrAborted}
+		group.calls[key] = pending
+		go group.execute(ctx, key, pending, fn)
+	}
+
+	group.mutex.Unlock()
+
+	select {
+	case <-pending.done:
+		return pending.response, shared, pending.err
+	case <-ctx.Done():
+		return nil, shared, ctx.Err()
+	}
+}
+
+func (group *Group) execute(ctx context.Context, key string, pending *call, fn func(context.Context) (*Response, error)) {
+	ctx, cancel := context.WithTimeout(transport.Detach(ctx), group.Timeout)
+
+	defer func() {
+		cancel()
+
+		group.mutex.Lock()
+		delete(group.calls, key)
+		group.mutex.Unlock()
+
+		close(pending.done)
+	}()
+
+	pending.response, pending.err = fn(ctx)
+}
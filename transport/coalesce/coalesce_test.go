@@ -0,0 +1,277 @@
+package coalesce
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jexia/maestro/schema"
+)
+
+func TestEnabled(t *testing.T) {
+	tests := map[string]struct {
+		service  schema.Options
+		method   schema.Options
+		expected bool
+	}{
+		"undefined": {},
+		"service": {
+			service:  schema.Options{EnabledOption: "true"},
+			expected: true,
+		},
+		"method": {
+			method:   schema.Options{EnabledOption: "true"},
+			expected: true,
+		},
+		"method override": {
+			service:  schema.Options{EnabledOption: "true"},
+			method:   schema.Options{EnabledOption: "false"},
+			expected: false,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			result, err := Enabled(test.service, test.method)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if result != test.expected {
+				t.Fatalf("unexpected result %t, expected %t", result, test.expected)
+			}
+		})
+	}
+}
+
+func TestEnabledInvalid(t *testing.T) {
+	_, err := Enabled(schema.Options{EnabledOption: "yes please"}, nil)
+	if err == nil {
+		t.Fatal("unexpected pass")
+	}
+}
+
+func TestGroupsGet(t *testing.T) {
+	groups := make(Groups)
+
+	group, err := groups.Get("users", "Get", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if group != nil {
+		t.Fatal("unexpected group")
+	}
+
+	first, err := groups.Get("users", "Get", schema.Options{EnabledOption: "true"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	second, err := groups.Get("users", "Get", schema.Options{EnabledOption: "true"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if first == nil || first != second {
+		t.Fatal("expected the same group to be returned for the same service method")
+	}
+
+	third, err := groups.Get("users", "List", schema.Options{EnabledOption: "true"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if third == first {
+		t.Fatal("unexpected shared group in between methods")
+	}
+}
+
+func TestGroupDo(t *testing.T) {
+	group := NewGroup()
+	release := make(chan struct{})
+	started := make(chan struct{})
+
+	calls := int32(0)
+	shared := int32(0)
+	concurrent := 10
+	expected := &Response{Body: []byte("ok")}
+
+	wg := sync.WaitGroup{}
+	wg.Add(concurrent)
+
+	do := func() {
+		defer wg.Done()
+
+		response, received, err := group.Do(context.Background(), "key", func(context.Context) (*Response, error) {
+			atomic.AddInt32(&calls, 1)
+			close(started)
+			<-release
+			return expected, nil
+		})
+
+		if err != nil {
+			t.Error(err)
+		}
+
+		if response != expected {
+			t.Errorf("unexpected response %+v", response)
+		}
+
+		if received {
+			atomic.AddInt32(&shared, 1)
+		}
+	}
+
+	go do()
+	<-started
+
+	for index := 1; index < concurrent; index++ {
+		go do()
+	}
+
+	// await till all callers are waiting for the in-flight call
+	time.Sleep(50 * time.Millisecond)
+
+	close(release)
+	wg.Wait()
+
+	if calls != 1 {
+		t.Fatalf("unexpected calls %d, expected 1", calls)
+	}
+
+	if int(shared) != concurrent-1 {
+		t.Fatalf("unexpected shared responses %d, expected %d", shared, concurrent-1)
+	}
+}
+
+func TestGroupDoError(t *testing.T) {
+	group := NewGroup()
+	expected := errors.New("unexpected err")
+
+	_, _, err := group.Do(context.Background(), "key", func(context.Context) (*Response, error) {
+		return nil, expected
+	})
+
+	if err != expected {
+		t.Fatalf("unexpected err %s, expected %s", err, expected)
+	}
+
+	if len(group.calls) != 0 {
+		t.Fatal("expected the in-flight call to be removed")
+	}
+}
+
+func TestGroupDoCancelled(t *testing.T) {
+	group := NewGroup()
+	release := make(chan struct{})
+	started := make(chan struct{})
+	expected := &Response{Body: []byte("ok")}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error, 1)
+	fn := func(ctx context.Context) (*Response, error) {
+		close(started)
+
+		select {
+		case <-release:
+			return expected, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	go func() {
+		_, _, err := group.Do(ctx, "key", fn)
+		first <- err
+	}()
+
+	<-started
+
+	second := make(chan *Response, 1)
+	go func() {
+		response, received, err := group.Do(context.Background(), "key", fn)
+		if err != nil {
+			t.Error(err)
+		}
+
+		if !received {
+			t.Error("expected the response to be received from the shared call")
+		}
+
+		second <- response
+	}()
+
+	// await till the second caller is waiting for the in-flight call
+	time.Sleep(50 * time.Millisecond)
+
+	cancel()
+
+	err := <-first
+	if err != context.Canceled {
+		t.Fatalf("unexpected err %v, expected %v", err, context.Canceled)
+	}
+
+	close(release)
+
+	response := <-second
+	if response != expected {
+		t.Fatalf("unexpected response %+v, expected %+v", response, expected)
+	}
+}
+
+func TestGroupDoTimeout(t *testing.T) {
+	group := NewGroup()
+	group.Timeout = 10 * time.Millisecond
+
+	_, _, err := group.Do(context.Background(), "key", func(ctx context.Context) (*Response, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+
+	if err != context.DeadlineExceeded {
+		t.Fatalf("unexpected err %v, expected %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	tests := map[string]struct {
+		service  schema.Options
+		method   schema.Options
+		expected time.Duration
+	}{
+		"default": {
+			expected: DefaultTimeout,
+		},
+		"service": {
+			service:  schema.Options{TimeoutOption: "5s"},
+			expected: 5 * time.Second,
+		},
+		"method override": {
+			service:  schema.Options{TimeoutOption: "5s"},
+			method:   schema.Options{TimeoutOption: "1s"},
+			expected: time.Second,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			result, err := Timeout(test.service, test.method)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if result != test.expected {
+				t.Fatalf("unexpected result %s, expected %s", result, test.expected)
+			}
+		})
+	}
+
+	_, err := Timeout(schema.Options{TimeoutOption: "soon"}, nil)
+	if err == nil {
+		t.Fatal("unexpected pass")
+	}
+}
@@ -0,0 +1,29 @@
+package transport
+
+import (
+	"context"
+	"time"
+)
+
+// Detach returns a copy of the given context which is not cancelled once the given context is done.
+// Values stored inside the given context, such as the active span, remain available.
+func Detach(ctx context.Context) context.Context {
+	return detached{ctx}
+}
+
+// detached represents a context holding the values of the wrapped context which is never cancelled
+type detached struct {
+	context.Context
+}
+
+func (detached) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (detached) Done() <-chan struct{} {
+	return nil
+}
+
+func (detached) Err() error {
+	return nil
+}
@@ -0,0 +1,26 @@
+package transport
+
+import (
+	"context"
+	"testing"
+)
+
+type key struct{}
+
+func TestDetach(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), key{}, "value"))
+	detached := Detach(ctx)
+	cancel()
+
+	if detached.Err() != nil {
+		t.Fatalf("unexpected err %s, expected the detached context to not be cancelled", detached.Err())
+	}
+
+	if _, has := detached.Deadline(); has {
+		t.Fatal("unexpected deadline")
+	}
+
+	if detached.Value(key{}) != "value" {
+		t.Fatalf("unexpected value %v", detached.Value(key{}))
+	}
+}
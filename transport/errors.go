@@ -0,0 +1,170 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/jexia/maestro/refs"
+	"github.com/jexia/maestro/specs"
+)
+
+// Available error codes
+const (
+	// CodeInvalidArgument is returned when the incoming request could not be decoded
+	CodeInvalidArgument = "invalid_argument"
+	// CodeOverloaded is returned when a call has been rejected to protect a service from overload
+	CodeOverloaded = "overloaded"
+	// CodeOverloadTimeout is returned when a queued call has been rejected because it did not receive a slot in time
+	CodeOverloadTimeout = "overload_timeout"
+	// CodeInternal is returned when a unexpected error occurred inside maestro
+	CodeInternal = "internal"
+	// CodeUpstream is returned when a service responded with a error
+	CodeUpstream = "upstream"
+	// CodeUnavailable is returned when a service could not be reached or when a call has been aborted
+	CodeUnavailable = "unavailable"
+	// CodeTimeout is returned when a deadline or timeout has been exceeded
+	CodeTimeout = "timeout"
+)
+
+// Generic messages exposed to clients instead of the error details
+const (
+	// MessageInternal is exposed to clients instead of the details of internal errors
+	MessageInternal = "internal error"
+	// MessageUnavailable is exposed to clients instead of the details of unavailable errors
+	MessageUnavailable = "service unavailable"
+)
+
+// Codes represents all available error codes
+var Codes = []string{
+	CodeInvalidArgument,
+	CodeOverloaded,
+	CodeOverloadTimeout,
+	CodeInternal,
+	CodeUpstream,
+	CodeUnavailable,
+	CodeTimeout,
+}
+
+// IsCode checks whether the given value is a available error code
+func IsCode(value string) bool {
+	for _, code := range Codes {
+		if code == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Error represents a structured error produced by transports and the flow engine.
+// Listeners render structured errors to the caller.
+// The body returned by the service is included when the service responded with a error.
+type Error struct {
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	Node      string `json:"node,omitempty"`
+	Status    int    `json:"status,omitempty"`
+	Retryable bool   `json:"retryable"`
+	Body      []byte `json:"-"`
+	Err       error  `json:"-"`
+}
+
+// Error returns the error message prefixed with the origin node if set
+func (err *Error) Error() string {
+	if err.Node == "" {
+		return err.Message
+	}
+
+	return fmt.Sprintf("%s: %s", err.Node, err.Message)
+}
+
+// Unwrap returns the underlying error
+func (err *Error) Unwrap() error {
+	return err.Err
+}
+
+// Public returns a copy of the given error which could be exposed to clients.
+// The messages of internal and unavailable errors are replaced with a generic message, the error details are kept inside Err.
+func (err *Error) Public() *Error {
+	result := *err
+
+	switch err.Code {
+	case CodeInternal:
+		result.Message = MessageInternal
+	case CodeUnavailable:
+		result.Message = MessageUnavailable
+	}
+
+	return &result
+}
+
+// AsError returns the structured error found inside the given error chain.
+// Errors which do not contain a structured error are classified by their cause.
+// Nil is returned if the given error is nil.
+func AsError(err error) *Error {
+	if err == nil {
+		return nil
+	}
+
+	var target *Error
+	if errors.As(err, &target) {
+		return target
+	}
+
+	result := &Error{
+		Code:    CodeUnavailable,
+		Message: err.Error(),
+		Err:     err,
+	}
+
+	var network net.Error
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		result.Code = CodeTimeout
+		result.Retryable = true
+	case errors.As(err, &network):
+		if network.Timeout() {
+			result.Code = CodeTimeout
+		}
+
+		result.Retryable = true
+	}
+
+	return result
+}
+
+// WrapError wraps the given error into a structured error originating from the given node.
+// The origin node of structured errors found inside the given error chain is preserved.
+// The given error could still be unwrapped from the returned error.
+func WrapError(err error, node string) error {
+	if err == nil {
+		return nil
+	}
+
+	result := *AsError(err)
+	result.Err = err
+
+	if result.Node == "" {
+		result.Node = node
+	}
+
+	return &result
+}
+
+// StoreError stores the properties of the given structured error inside the error resource of the given store.
+// Stored error properties could be referenced inside flow error responses and are therefore exposed to clients.
+func StoreError(store *refs.Store, err *Error) {
+	if store == nil || err == nil {
+		return
+	}
+
+	err = err.Public()
+
+	store.StoreValue(specs.ErrorResource, "code", err.Code)
+	store.StoreValue(specs.ErrorResource, "message", err.Message)
+	store.StoreValue(specs.ErrorResource, "node", err.Node)
+	store.StoreValue(specs.ErrorResource, "status", int32(err.Status))
+	store.StoreValue(specs.ErrorResource, "retryable", err.Retryable)
+}
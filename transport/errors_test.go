@@ -0,0 +1,113 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jexia/maestro/refs"
+	"github.com/jexia/maestro/specs"
+)
+
+func TestAsError(t *testing.T) {
+	structured := &Error{Code: CodeOverloaded, Message: "overloaded"}
+
+	tests := map[error]string{
+		errors.New("unexpected error"): CodeUnavailable,
+		context.DeadlineExceeded:       CodeTimeout,
+		structured:                     CodeOverloaded,
+	}
+
+	for err, expected := range tests {
+		t.Run(err.Error(), func(t *testing.T) {
+			result := AsError(err)
+			if result.Code != expected {
+				t.Fatalf("unexpected code %s, expected %s", result.Code, expected)
+			}
+		})
+	}
+
+	if AsError(nil) != nil {
+		t.Fatal("unexpected structured error for nil error")
+	}
+}
+
+func TestWrapError(t *testing.T) {
+	expected := errors.New("unexpected error")
+
+	err := WrapError(WrapError(expected, "first"), "second")
+	if !errors.Is(err, expected) {
+		t.Fatalf("unexpected error %s, expected %s to be wrapped", err, expected)
+	}
+
+	result := AsError(err)
+	if result.Node != "first" {
+		t.Fatalf("unexpected origin node %s, expected %s", result.Node, "first")
+	}
+
+	if result.Error() != "first: unexpected error" {
+		t.Fatalf("unexpected message %s", result.Error())
+	}
+
+	if WrapError(nil, "first") != nil {
+		t.Fatal("unexpected error for nil error")
+	}
+}
+
+func TestPublic(t *testing.T) {
+	tests := map[*Error]string{
+		AsError(errors.New("dial tcp 10.0.0.1:5432: connection refused")):          MessageUnavailable,
+		{Code: CodeInternal, Message: "unable to encode 'secret' field"}:           MessageInternal,
+		{Code: CodeUpstream, Message: "service 'users' responded with status 404"}: "service 'users' responded with status 404",
+		{Code: CodeOverloadTimeout, Message: "bulkhead queue timeout exceeded"}:    "bulkhead queue timeout exceeded",
+	}
+
+	for input, expected := range tests {
+		t.Run(input.Message, func(t *testing.T) {
+			result := input.Public()
+			if result.Message != expected {
+				t.Fatalf("unexpected message %s, expected %s", result.Message, expected)
+			}
+
+			if result.Err != input.Err {
+				t.Fatalf("unexpected err %v, expected the error details to be kept", result.Err)
+			}
+		})
+	}
+}
+
+func TestIsCode(t *testing.T) {
+	for _, code := range Codes {
+		if !IsCode(code) {
+			t.Fatalf("unexpected result, expected %s to be a available code", code)
+		}
+	}
+
+	if IsCode("unknown") {
+		t.Fatal("unexpected result, expected unknown to be a unavailable code")
+	}
+}
+
+func TestStoreError(t *testing.T) {
+	store := refs.NewStore(5)
+	StoreError(store, &Error{Code: CodeUpstream, Message: "bad gateway", Node: "first", Status: 502, Retryable: true})
+
+	expected := map[string]interface{}{
+		"code":      CodeUpstream,
+		"message":   "bad gateway",
+		"node":      "first",
+		"status":    int32(502),
+		"retryable": true,
+	}
+
+	for path, value := range expected {
+		ref := store.Load(specs.ErrorResource, path)
+		if ref == nil {
+			t.Fatalf("expected error property %s to be stored", path)
+		}
+
+		if ref.Value != value {
+			t.Fatalf("unexpected value %v for %s, expected %v", ref.Value, path, value)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package graphql
+
+import (
+	"github.com/jexia/maestro/refs"
+	"github.com/jexia/maestro/specs"
+	"github.com/jexia/maestro/transport"
+)
+
+// NewError constructs a new GraphQL error for the given flow error.
+// The structured error properties are included as error extensions.
+// The details of internal and unavailable errors are not exposed to the client.
+func NewError(err error) *Error {
+	return &Error{
+		err: transport.AsError(err).Public(),
+	}
+}
+
+// NewErrorResponse constructs a new GraphQL error for the given flow error.
+// The properties of the given flow error response are included as error extensions when defined.
+func NewErrorResponse(err error, onError *specs.OnError, store *refs.Store) *Error {
+	result := NewError(err)
+	if onError == nil || onError.Response == nil {
+		return result
+	}
+
+	transport.StoreError(store, result.err)
+
+	value, err := ResponseValue(onError.Response.Property, store)
+	if err != nil {
+		return result
+	}
+
+	result.response, _ = value.(map[string]interface{})
+	return result
+}
+
+// Error represents a structured error which exposes its properties as GraphQL error extensions
+type Error struct {
+	err      *transport.Error
+	response map[string]interface{}
+}
+
+// Error returns the error message
+func (err *Error) Error() string {
+	return err.err.Error()
+}
+
+// Unwrap returns the structured error
+func (err *Error) Unwrap() error {
+	return err.err
+}
+
+// Extensions returns the error extensions included inside the GraphQL response.
+// The properties of the flow error response are returned when defined.
+func (err *Error) Extensions() map[string]interface{} {
+	if err.response != nil {
+		return err.response
+	}
+
+	result := map[string]interface{}{
+		"code":      err.err.Code,
+		"retryable": err.err.Retryable,
+	}
+
+	if err.err.Node != "" {
+		result["node"] = err.err.Node
+	}
+
+	if err.err.Status != 0 {
+		result["status"] = err.err.Status
+	}
+
+	return result
+}
@@ -9,7 +9,9 @@ import (
 	"github.com/graphql-go/graphql"
 	"github.com/jexia/maestro/codec"
 	"github.com/jexia/maestro/logger"
+	"github.com/jexia/maestro/metadata"
 	"github.com/jexia/maestro/specs"
+	"github.com/jexia/maestro/tracing"
 	"github.com/jexia/maestro/transport"
 )
 
@@ -61,6 +63,7 @@ func (listener *Listener) Serve() error {
 		defer r.Body.Close()
 
 		result := graphql.Do(graphql.Params{
+			Context:       tracing.Extract(r.Context(), metadata.MD{tracing.TraceparentHeader: r.Header.Get(tracing.TraceparentHeader)}),
 			Schema:        listener.schema,
 			RequestString: req.Query,
 		})
@@ -94,13 +97,11 @@ func (listener *Listener) Handle(endpoints []*transport.Endpoint, constructors m
 		resolve := func(endpoint *transport.Endpoint) graphql.FieldResolveFn {
 			return func(p graphql.ResolveParams) (interface{}, error) {
 				store := endpoint.Flow.NewStore()
-				ctx := context.Background()
-
 				store.StoreValues(specs.InputResource, "", p.Args)
 
-				err = endpoint.Flow.Call(ctx, store)
+				err := endpoint.Flow.Call(p.Context, store)
 				if err != nil {
-					return nil, err
+					return nil, NewErrorResponse(err, endpoint.OnError, store)
 				}
 
 				result, err := ResponseValue(endpoint.Response.Property, store)
@@ -46,16 +46,16 @@ func ResponseValue(specs *specs.Property, refs *refs.Store) (interface{}, error)
 			continue
 		}
 
-		if nested.Reference == nil {
+		if nested.Reference == nil && nested.Expression == nil {
 			continue
 		}
 
-		ref := refs.Load(nested.Reference.Resource, nested.Reference.Path)
-		if ref == nil {
+		value := refs.Value(nested)
+		if value == nil {
 			continue
 		}
 
-		result[nested.Name] = ref.Value
+		result[nested.Name] = value
 	}
 
 	return result, nil
@@ -2,6 +2,7 @@ package http
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"net/http/httputil"
 	"net/url"
@@ -52,6 +53,11 @@ func (caller *Caller) Dial(schema schema.Service, functions specs.CustomDefinedF
 		return nil, err
 	}
 
+	status, err := ParseStatusOptions(opts, nil)
+	if err != nil {
+		return nil, err
+	}
+
 	methods := make(map[string]*Method, len(schema.GetMethods()))
 
 	for _, method := range schema.GetMethods() {
@@ -65,11 +71,17 @@ func (caller *Caller) Dial(schema schema.Service, functions specs.CustomDefinedF
 			return nil, err
 		}
 
+		status, err := ParseStatusOptions(opts, method.GetOptions())
+		if err != nil {
+			return nil, err
+		}
+
 		methods[method.GetName()] = &Method{
 			name:       method.GetName(),
 			request:    request,
 			endpoint:   endpoint,
 			references: references,
+			status:     status,
 		}
 	}
 
@@ -79,6 +91,7 @@ func (caller *Caller) Dial(schema schema.Service, functions specs.CustomDefinedF
 		service: schema.GetName(),
 		host:    schema.GetHost(),
 		proxy:   NewProxy(options),
+		status:  status,
 		methods: methods,
 	}
 
@@ -91,6 +104,7 @@ type Method struct {
 	request    string
 	endpoint   string
 	references []*specs.Property
+	status     *StatusOptions
 }
 
 // GetName returns the method name
@@ -115,6 +129,7 @@ type Call struct {
 	host    string
 	methods map[string]*Method
 	proxy   *httputil.ReverseProxy
+	status  *StatusOptions
 }
 
 // GetMethods returns the available methods within the HTTP caller
@@ -139,9 +154,11 @@ func (call *Call) GetMethod(name string) transport.Method {
 	return nil
 }
 
-// SendMsg calls the configured host and attempts to call the given endpoint with the given headers and stream
+// SendMsg calls the configured host and attempts to call the given endpoint with the given headers and stream.
+// Responses with a error status code are returned as upstream error including the response body.
 func (call *Call) SendMsg(ctx context.Context, rw transport.ResponseWriter, pr *transport.Request, refs *refs.Store) error {
 	request := http.MethodGet
+	status := call.status
 	url, err := url.Parse(call.host)
 	if err != nil {
 		return err
@@ -159,6 +176,7 @@ func (call *Call) SendMsg(ctx context.Context, rw transport.ResponseWriter, pr *
 		}
 
 		request = method.request
+		status = method.status
 	}
 
 	call.logger.WithFields(logrus.Fields{
@@ -173,9 +191,23 @@ func (call *Call) SendMsg(ctx context.Context, rw transport.ResponseWriter, pr *
 	}
 
 	req.Header = CopyMetadataHeader(pr.Header)
-	res := NewTransportResponseWriter(ctx, rw)
+	res := NewTransportResponseWriter(ctx, rw, status.Errors)
 
 	call.proxy.ServeHTTP(res, req)
+	if res.err != nil {
+		return transport.AsError(res.err)
+	}
+
+	if res.failed {
+		return &transport.Error{
+			Code:      transport.CodeUpstream,
+			Message:   fmt.Sprintf("service '%s' responded with status %d", call.service, res.status),
+			Status:    res.status,
+			Retryable: status.Retry.Has(res.status),
+			Body:      res.body.Bytes(),
+		}
+	}
+
 	rw.Header().Append(CopyHTTPHeader(res.Header()))
 
 	return nil
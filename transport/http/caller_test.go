@@ -1,17 +1,20 @@
 package http
 
 import (
+	"bytes"
 	"context"
 	"io"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/jexia/maestro/codec/json"
 	"github.com/jexia/maestro/logger"
 	"github.com/jexia/maestro/metadata"
 	"github.com/jexia/maestro/refs"
+	"github.com/jexia/maestro/schema"
 	"github.com/jexia/maestro/specs/types"
 	"github.com/jexia/maestro/transport"
 )
@@ -178,3 +181,121 @@ func TestCallerReferencesLookup(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestCallerContextTimeout(t *testing.T) {
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-done:
+		}
+	}))
+
+	defer server.Close()
+	defer close(done)
+
+	service := NewMockService(server.URL, "GET", "/")
+	caller, err := NewMockCaller().Dial(service, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer caller.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	req := transport.Request{
+		Method: caller.GetMethod("mock"),
+	}
+
+	rw := &MockResponseWriter{
+		header: metadata.MD{},
+		writer: ioutil.Discard,
+	}
+
+	err = caller.SendMsg(ctx, rw, &req, refs.NewStore(0))
+	if err == nil {
+		t.Fatal("expected a error to be returned when the context deadline has been exceeded")
+	}
+}
+
+func TestCallerErrorStatus(t *testing.T) {
+	body := `{"message":"unavailable"}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(body))
+	}))
+
+	defer server.Close()
+
+	service := NewMockService(server.URL, "GET", "/")
+	caller, err := NewMockCaller().Dial(service, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer caller.Close()
+
+	req := transport.Request{
+		Method: caller.GetMethod("mock"),
+	}
+
+	buffer := bytes.NewBuffer(nil)
+	rw := &MockResponseWriter{
+		header: metadata.MD{},
+		writer: buffer,
+	}
+
+	err = caller.SendMsg(context.Background(), rw, &req, refs.NewStore(0))
+	if err == nil {
+		t.Fatal("expected a error to be returned")
+	}
+
+	result := transport.AsError(err)
+	if result.Code != transport.CodeUpstream || result.Status != http.StatusServiceUnavailable || !result.Retryable {
+		t.Fatalf("unexpected error %+v", result)
+	}
+
+	if string(result.Body) != body {
+		t.Fatalf("unexpected error body %s, expected %s", result.Body, body)
+	}
+
+	if buffer.Len() > 0 {
+		t.Fatalf("unexpected response body %s, expected the error body not to be written", buffer.String())
+	}
+}
+
+func TestCallerSuccessStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+
+	defer server.Close()
+
+	service := NewMockService(server.URL, "GET", "/")
+	caller, err := NewMockCaller().Dial(service, nil, schema.Options{ErrorStatusOption: "500-599"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer caller.Close()
+
+	req := transport.Request{
+		Method: caller.GetMethod("mock"),
+	}
+
+	rw := &MockResponseWriter{
+		header: metadata.MD{},
+		writer: ioutil.Discard,
+	}
+
+	err = caller.SendMsg(context.Background(), rw, &req, refs.NewStore(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rw.status != http.StatusNotFound {
+		t.Fatalf("unexpected status %d, expected %d", rw.status, http.StatusNotFound)
+	}
+}
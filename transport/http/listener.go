@@ -11,7 +11,9 @@ import (
 	"github.com/jexia/maestro/codec"
 	"github.com/jexia/maestro/logger"
 	"github.com/jexia/maestro/metadata"
+	"github.com/jexia/maestro/refs"
 	"github.com/jexia/maestro/specs"
+	"github.com/jexia/maestro/tracing"
 	"github.com/jexia/maestro/transport"
 	"github.com/julienschmidt/httprouter"
 	"github.com/sirupsen/logrus"
@@ -147,6 +149,20 @@ func NewHandle(logger *logrus.Logger, endpoint *transport.Endpoint, options *End
 		}
 	}
 
+	if endpoint.OnError != nil && endpoint.OnError.Response != nil {
+		response, err := codec.New(specs.ErrorResource, endpoint.OnError.Response)
+		if err != nil {
+			// TODO log
+			return nil
+		}
+
+		header := metadata.NewManager(specs.ErrorResource, endpoint.OnError.Response)
+		handle.Error = &Request{
+			Header: header,
+			Codec:  response,
+		}
+	}
+
 	if endpoint.Forward != nil {
 		url, err := url.Parse(endpoint.Forward.GetHost())
 		if err != nil {
@@ -172,6 +188,7 @@ type Handle struct {
 	Options  *EndpointOptions
 	Request  *Request
 	Response *Request
+	Error    *Request
 	Proxy    *httputil.ReverseProxy
 }
 
@@ -200,15 +217,21 @@ func (handle *Handle) HTTPFunc(w http.ResponseWriter, r *http.Request, ps httpro
 			err = handle.Request.Codec.Unmarshal(r.Body, store)
 			if err != nil {
 				handle.logger.Error(err)
-				w.WriteHeader(http.StatusBadRequest)
+
+				err = &transport.Error{Code: transport.CodeInvalidArgument, Message: err.Error(), Err: err}
+				transport.Reject(r.Context(), handle.Endpoint.Flow, err)
+				handle.WriteError(w, store, err)
 				return
 			}
 		}
 	}
 
-	err = handle.Endpoint.Flow.Call(r.Context(), store)
+	ctx := tracing.Extract(r.Context(), CopyHTTPHeader(r.Header))
+
+	err = handle.Endpoint.Flow.Call(ctx, store)
 	if err != nil {
-		w.WriteHeader(http.StatusServiceUnavailable)
+		handle.logger.WithField("err", err).Debug("Flow call failed")
+		handle.WriteError(w, store, err)
 		return
 	}
 
@@ -220,7 +243,8 @@ func (handle *Handle) HTTPFunc(w http.ResponseWriter, r *http.Request, ps httpro
 		if handle.Response.Codec != nil {
 			reader, err := handle.Response.Codec.Marshal(store)
 			if err != nil {
-				w.WriteHeader(http.StatusInternalServerError)
+				handle.logger.Error(err)
+				WriteError(w, &transport.Error{Code: transport.CodeInternal, Message: transport.MessageInternal, Err: err})
 				return
 			}
 
@@ -239,3 +263,44 @@ func (handle *Handle) HTTPFunc(w http.ResponseWriter, r *http.Request, ps httpro
 		handle.Proxy.ServeHTTP(w, r)
 	}
 }
+
+// WriteError writes the given error to the given response writer.
+// The error response defined inside the flow is written if available.
+// The status code of the error response is mapped by its error code or defaults to the structured error status.
+func (handle *Handle) WriteError(w http.ResponseWriter, store *refs.Store, err error) {
+	if handle.Error == nil {
+		WriteError(w, err)
+		return
+	}
+
+	result := transport.AsError(err)
+	transport.StoreError(store, result)
+
+	status := handle.Endpoint.OnError.GetStatus(result.Code)
+	if status == 0 {
+		status = ErrorStatus(result)
+	}
+
+	if handle.Error.Header != nil {
+		SetHTTPHeader(w.Header(), handle.Error.Header.Marshal(store))
+	}
+
+	if handle.Error.Codec == nil {
+		w.WriteHeader(status)
+		return
+	}
+
+	reader, err := handle.Error.Codec.Marshal(store)
+	if err != nil {
+		handle.logger.Error(err)
+		WriteError(w, &transport.Error{Code: transport.CodeInternal, Message: transport.MessageInternal, Err: err})
+		return
+	}
+
+	w.WriteHeader(status)
+
+	_, err = io.Copy(w, reader)
+	if err != nil {
+		handle.logger.Error(err)
+	}
+}
@@ -2,8 +2,11 @@ package http
 
 import (
 	"context"
+	encoding "encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 	"time"
@@ -14,7 +17,10 @@ import (
 	"github.com/jexia/maestro/logger"
 	"github.com/jexia/maestro/refs"
 	"github.com/jexia/maestro/specs"
+	"github.com/jexia/maestro/specs/types"
 	"github.com/jexia/maestro/transport"
+	"github.com/jexia/maestro/transport/bulkhead"
+	"github.com/sirupsen/logrus"
 )
 
 func NewMockListener(t *testing.T, nodes flow.Nodes) (transport.Listener, int) {
@@ -171,3 +177,202 @@ func TestPathReferences(t *testing.T) {
 	endpoint := fmt.Sprintf("http://127.0.0.1:%d/"+message, port)
 	http.Get(endpoint)
 }
+
+func TestErrorStatus(t *testing.T) {
+	tests := map[error]int{
+		bulkhead.ErrQueueFull:          http.StatusTooManyRequests,
+		bulkhead.ErrQueueTimeout:       http.StatusServiceUnavailable,
+		errors.New("unexpected error"): http.StatusServiceUnavailable,
+		context.DeadlineExceeded:       http.StatusGatewayTimeout,
+		&transport.Error{Code: transport.CodeUpstream, Message: "upstream"}:       http.StatusBadGateway,
+		&transport.Error{Code: transport.CodeInvalidArgument, Message: "invalid"}: http.StatusBadRequest,
+	}
+
+	for err, expected := range tests {
+		t.Run(err.Error(), func(t *testing.T) {
+			result := ErrorStatus(err)
+			if result != expected {
+				t.Fatalf("unexpected status %d, expected %d", result, expected)
+			}
+		})
+	}
+}
+
+func TestWriteError(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	WriteError(recorder, transport.WrapError(bulkhead.ErrQueueFull, "first"))
+
+	if recorder.Code != http.StatusTooManyRequests {
+		t.Fatalf("unexpected status %d, expected %d", recorder.Code, http.StatusTooManyRequests)
+	}
+
+	if recorder.Header().Get("Content-Type") != "application/json" {
+		t.Fatalf("unexpected content type %s", recorder.Header().Get("Content-Type"))
+	}
+
+	result := ErrorMessage{}
+	err := encoding.NewDecoder(recorder.Body).Decode(&result)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Error.Code != transport.CodeOverloaded || result.Error.Node != "first" || !result.Error.Retryable {
+		t.Fatalf("unexpected error message %+v", result.Error)
+	}
+}
+
+func TestWriteErrorHidesDetails(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	WriteError(recorder, transport.WrapError(errors.New("dial tcp 10.0.0.1:5432: connection refused"), "first"))
+
+	if recorder.Code != http.StatusServiceUnavailable {
+		t.Fatalf("unexpected status %d, expected %d", recorder.Code, http.StatusServiceUnavailable)
+	}
+
+	if strings.Contains(recorder.Body.String(), "10.0.0.1") {
+		t.Fatalf("unexpected error details inside the response %s", recorder.Body.String())
+	}
+
+	result := ErrorMessage{}
+	err := encoding.NewDecoder(recorder.Body).Decode(&result)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Error.Message != transport.MessageUnavailable {
+		t.Fatalf("unexpected error message %s, expected %s", result.Error.Message, transport.MessageUnavailable)
+	}
+}
+
+func NewErrorReference(path string, typed types.Type) *specs.Property {
+	return &specs.Property{
+		Name:  path,
+		Path:  path,
+		Type:  typed,
+		Label: types.LabelOptional,
+		Reference: &specs.PropertyReference{
+			Resource: specs.ErrorResource,
+			Path:     path,
+		},
+	}
+}
+
+func TestHandleWriteError(t *testing.T) {
+	ctx := context.Background()
+	ctx = logger.WithValue(ctx)
+
+	json := json.NewConstructor()
+	constructors := map[string]codec.Constructor{
+		json.Name(): json,
+	}
+
+	endpoint := &transport.Endpoint{
+		Flow: flow.NewManager(ctx, "test", flow.Nodes{}),
+		OnError: &specs.OnError{
+			Status: map[string]int{
+				transport.CodeOverloaded: http.StatusServiceUnavailable,
+			},
+			Response: &specs.ParameterMap{
+				Header: specs.Header{
+					"Node": NewErrorReference("node", types.TypeString),
+				},
+				Property: &specs.Property{
+					Type:  types.TypeMessage,
+					Label: types.LabelOptional,
+					Nested: map[string]*specs.Property{
+						"code":    NewErrorReference("code", types.TypeString),
+						"message": NewErrorReference("message", types.TypeString),
+					},
+				},
+			},
+		},
+	}
+
+	handle := NewHandle(logrus.New(), endpoint, &EndpointOptions{Codec: json.Name()}, constructors)
+
+	tests := map[error]int{
+		transport.WrapError(bulkhead.ErrQueueFull, "first"):                                             http.StatusServiceUnavailable,
+		transport.WrapError(&transport.Error{Code: transport.CodeTimeout, Message: "timeout"}, "first"): http.StatusGatewayTimeout,
+	}
+
+	for input, status := range tests {
+		t.Run(input.Error(), func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			handle.WriteError(recorder, refs.NewStore(5), input)
+
+			if recorder.Code != status {
+				t.Fatalf("unexpected status %d, expected %d", recorder.Code, status)
+			}
+
+			if recorder.Header().Get("Node") != "first" {
+				t.Fatalf("unexpected node header %s, expected %s", recorder.Header().Get("Node"), "first")
+			}
+
+			result := map[string]string{}
+			err := encoding.NewDecoder(recorder.Body).Decode(&result)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expected := transport.AsError(input).Public()
+			if result["code"] != expected.Code || result["message"] != expected.Message {
+				t.Fatalf("unexpected error response %+v, expected %+v", result, expected)
+			}
+		})
+	}
+}
+
+type rejecting struct {
+	err error
+}
+
+func (flow *rejecting) NewStore() *refs.Store {
+	return refs.NewStore(0)
+}
+
+func (flow *rejecting) GetName() string {
+	return "rejecting"
+}
+
+func (flow *rejecting) Call(ctx context.Context, store *refs.Store) error {
+	return flow.err
+}
+
+func (flow *rejecting) Wait() {}
+
+func TestHandleOverload(t *testing.T) {
+	json := json.NewConstructor()
+	constructors := map[string]codec.Constructor{
+		json.Name(): json,
+	}
+
+	tests := map[error]int{
+		bulkhead.ErrQueueFull:    http.StatusTooManyRequests,
+		bulkhead.ErrQueueTimeout: http.StatusServiceUnavailable,
+	}
+
+	for input, status := range tests {
+		t.Run(input.Error(), func(t *testing.T) {
+			endpoint := &transport.Endpoint{Flow: &rejecting{err: input}}
+			handle := NewHandle(logrus.New(), endpoint, &EndpointOptions{Codec: json.Name()}, constructors)
+
+			recorder := httptest.NewRecorder()
+			handle.HTTPFunc(recorder, httptest.NewRequest(http.MethodGet, "/", nil), nil)
+
+			if recorder.Code != status {
+				t.Fatalf("unexpected status %d, expected %d", recorder.Code, status)
+			}
+
+			result := ErrorMessage{}
+			err := encoding.NewDecoder(recorder.Body).Decode(&result)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expected := transport.AsError(input)
+			if result.Error.Code != expected.Code || result.Error.Message != expected.Message || !result.Error.Retryable {
+				t.Fatalf("unexpected error %+v, expected %+v", result.Error, expected)
+			}
+		})
+	}
+}
@@ -2,10 +2,12 @@ package http
 
 import (
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/jexia/maestro/schema"
 	"github.com/jexia/maestro/specs"
+	"github.com/jexia/maestro/specs/trace"
 )
 
 const (
@@ -27,6 +29,17 @@ const (
 	KeepAliveOption = "keep_alive"
 	// MaxIdleConnsOption represents the max idle connections option key
 	MaxIdleConnsOption = "max_idle_conns"
+	// ErrorStatusOption represents the option key defining the upstream status codes treated as errors
+	ErrorStatusOption = "error_status"
+	// RetryStatusOption represents the option key defining the upstream error status codes which could be retried
+	RetryStatusOption = "retry_status"
+)
+
+const (
+	// DefaultErrorStatus represents the upstream status codes treated as errors by default
+	DefaultErrorStatus = "100-199,300-599"
+	// DefaultRetryStatus represents the upstream error status codes which could be retried by default
+	DefaultRetryStatus = "429,502,503,504"
 )
 
 // ListenerOptions represents the available HTTP options
@@ -171,3 +184,95 @@ func ParseCallerOptions(options schema.Options) (*CallerOptions, error) {
 
 	return result, nil
 }
+
+// StatusRange represents a inclusive range of status codes
+type StatusRange struct {
+	From int
+	To   int
+}
+
+// StatusCodes represents a collection of status code ranges
+type StatusCodes []StatusRange
+
+// Has checks whether the given status code is included inside one of the status code ranges
+func (codes StatusCodes) Has(status int) bool {
+	for _, code := range codes {
+		if status >= code.From && status <= code.To {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ParseStatusCodes parses the given comma separated status codes and status code ranges (ex: 404,500-599)
+func ParseStatusCodes(value string) (StatusCodes, error) {
+	result := StatusCodes{}
+
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		bounds := strings.SplitN(part, "-", 2)
+
+		from, err := strconv.Atoi(strings.TrimSpace(bounds[0]))
+		if err != nil {
+			return nil, trace.New(trace.WithMessage("invalid status code '%s'", part))
+		}
+
+		to := from
+		if len(bounds) > 1 {
+			to, err = strconv.Atoi(strings.TrimSpace(bounds[1]))
+			if err != nil || to < from {
+				return nil, trace.New(trace.WithMessage("invalid status code range '%s'", part))
+			}
+		}
+
+		result = append(result, StatusRange{From: from, To: to})
+	}
+
+	return result, nil
+}
+
+// StatusOptions represents the available upstream status options
+type StatusOptions struct {
+	Errors StatusCodes
+	Retry  StatusCodes
+}
+
+// ParseStatusOptions parses the given service and method options into status options.
+// Method options take precedence over service options.
+func ParseStatusOptions(service schema.Options, method schema.Options) (*StatusOptions, error) {
+	lookup := func(key string, fallback string) string {
+		value, has := method[key]
+		if has {
+			return value
+		}
+
+		value, has = service[key]
+		if has {
+			return value
+		}
+
+		return fallback
+	}
+
+	errors, err := ParseStatusCodes(lookup(ErrorStatusOption, DefaultErrorStatus))
+	if err != nil {
+		return nil, err
+	}
+
+	retry, err := ParseStatusCodes(lookup(RetryStatusOption, DefaultRetryStatus))
+	if err != nil {
+		return nil, err
+	}
+
+	result := &StatusOptions{
+		Errors: errors,
+		Retry:  retry,
+	}
+
+	return result, nil
+}
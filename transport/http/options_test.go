@@ -1,6 +1,7 @@
 package http
 
 import (
+	"net/http"
 	"strconv"
 	"testing"
 	"time"
@@ -96,3 +97,64 @@ func TestParseCallerOptions(t *testing.T) {
 		t.Fatalf("unexpected flush interval %+v, expected %+v", result.FlushInterval, duration)
 	}
 }
+
+func TestParseStatusCodes(t *testing.T) {
+	codes, err := ParseStatusCodes("404, 500-599")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[int]bool{
+		200: false,
+		404: true,
+		405: false,
+		500: true,
+		503: true,
+		599: true,
+	}
+
+	for status, expected := range tests {
+		if codes.Has(status) != expected {
+			t.Errorf("unexpected result for status %d, expected %t", status, expected)
+		}
+	}
+
+	invalid := []string{"error", "500-", "599-500"}
+	for _, input := range invalid {
+		_, err := ParseStatusCodes(input)
+		if err == nil {
+			t.Errorf("unexpected pass for '%s'", input)
+		}
+	}
+}
+
+func TestParseStatusOptions(t *testing.T) {
+	result, err := ParseStatusOptions(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Errors.Has(http.StatusOK) || !result.Errors.Has(http.StatusNotFound) || !result.Errors.Has(http.StatusFound) {
+		t.Fatalf("unexpected default error status codes %+v", result.Errors)
+	}
+
+	if !result.Retry.Has(http.StatusServiceUnavailable) || result.Retry.Has(http.StatusInternalServerError) {
+		t.Fatalf("unexpected default retry status codes %+v", result.Retry)
+	}
+
+	service := schema.Options{ErrorStatusOption: "500-599", RetryStatusOption: "500"}
+	method := schema.Options{RetryStatusOption: "502"}
+
+	result, err = ParseStatusOptions(service, method)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Errors.Has(http.StatusNotFound) || !result.Errors.Has(http.StatusInternalServerError) {
+		t.Fatalf("unexpected error status codes %+v", result.Errors)
+	}
+
+	if result.Retry.Has(http.StatusInternalServerError) || !result.Retry.Has(http.StatusBadGateway) {
+		t.Fatalf("unexpected retry status codes %+v, expected the method options to take precedence", result.Retry)
+	}
+}
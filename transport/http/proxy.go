@@ -11,6 +11,7 @@ import (
 func NewProxy(options *CallerOptions) *httputil.ReverseProxy {
 	return &httputil.ReverseProxy{
 		Director:      func(*http.Request) {},
+		ErrorHandler:  ProxyErrorHandler,
 		FlushInterval: options.FlushInterval,
 		Transport: &http.Transport{
 			Proxy: http.ProxyFromEnvironment,
@@ -26,3 +27,14 @@ func NewProxy(options *CallerOptions) *httputil.ReverseProxy {
 		},
 	}
 }
+
+// ProxyErrorHandler stores the thrown proxy error inside the given transport response writer.
+// This allows callers to return errors such as exceeded deadlines or failing connections.
+func ProxyErrorHandler(rw http.ResponseWriter, req *http.Request, err error) {
+	writer, is := rw.(*TransportResponseWriter)
+	if is {
+		writer.err = err
+	}
+
+	rw.WriteHeader(http.StatusBadGateway)
+}
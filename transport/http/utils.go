@@ -1,7 +1,9 @@
 package http
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"net/http"
 	"strings"
 
@@ -36,11 +38,13 @@ func CopyMetadataHeader(header metadata.MD) http.Header {
 	return result
 }
 
-// NewTransportResponseWriter constructs a new HTTP response writer of the given transport response writer
-func NewTransportResponseWriter(ctx context.Context, rw transport.ResponseWriter) *TransportResponseWriter {
+// NewTransportResponseWriter constructs a new HTTP response writer of the given transport response writer.
+// Responses with one of the given error status codes are buffered and not written to the transport response writer.
+func NewTransportResponseWriter(ctx context.Context, rw transport.ResponseWriter, errors StatusCodes) *TransportResponseWriter {
 	return &TransportResponseWriter{
 		header:    http.Header{},
 		transport: rw,
+		errors:    errors,
 	}
 }
 
@@ -49,7 +53,11 @@ func NewTransportResponseWriter(ctx context.Context, rw transport.ResponseWriter
 type TransportResponseWriter struct {
 	header    http.Header
 	transport transport.ResponseWriter
+	errors    StatusCodes
 	status    int
+	failed    bool
+	body      bytes.Buffer
+	err       error
 }
 
 // Header returns the header map that will be sent by
@@ -61,13 +69,24 @@ func (rw *TransportResponseWriter) Header() http.Header {
 
 // Write writes the data to the connection as part of an HTTP reply.
 func (rw *TransportResponseWriter) Write(bb []byte) (int, error) {
+	if rw.failed {
+		return rw.body.Write(bb)
+	}
+
 	return rw.transport.Write(bb)
 }
 
 // WriteHeader sends an HTTP response header with the provided
-// status code.
+// status code. Error status codes mark the response as failed.
 func (rw *TransportResponseWriter) WriteHeader(status int) {
 	rw.status = status
+
+	if rw.err == nil && rw.errors.Has(status) {
+		rw.failed = true
+		return
+	}
+
+	rw.transport.WriteHeader(status)
 }
 
 // NewRequest constructs a new transport request of the given http request
@@ -109,3 +128,41 @@ func (rw *ResponseWriter) Write(bb []byte) (int, error) {
 func (rw *ResponseWriter) WriteHeader(status int) {
 	rw.writer.WriteHeader(status)
 }
+
+// ErrorStatus returns the HTTP status code for the given flow error.
+// The status code is based on the code of the structured error found inside the given error.
+// Rejected flow executions caused by a full bulkhead queue are mapped to 429, exceeded bulkhead queue timeouts and unknown errors to 503.
+func ErrorStatus(err error) int {
+	switch transport.AsError(err).Code {
+	case transport.CodeInvalidArgument:
+		return http.StatusBadRequest
+	case transport.CodeOverloaded:
+		return http.StatusTooManyRequests
+	case transport.CodeOverloadTimeout:
+		return http.StatusServiceUnavailable
+	case transport.CodeInternal:
+		return http.StatusInternalServerError
+	case transport.CodeUpstream:
+		return http.StatusBadGateway
+	case transport.CodeTimeout:
+		return http.StatusGatewayTimeout
+	}
+
+	return http.StatusServiceUnavailable
+}
+
+// ErrorMessage represents the JSON body written to the client once a request failed
+type ErrorMessage struct {
+	Error *transport.Error `json:"error"`
+}
+
+// WriteError writes the given error as JSON body including the matching status code to the given response writer.
+// The details of internal and unavailable errors are not exposed to the client.
+func WriteError(w http.ResponseWriter, err error) {
+	result := transport.AsError(err).Public()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(ErrorStatus(result))
+
+	json.NewEncoder(w).Encode(ErrorMessage{Error: result})
+}
@@ -157,7 +157,7 @@ func (call *Call) SendMsg(ctx context.Context, rw transport.ResponseWriter, pr *
 
 	err = call.client.Call(ctx, req, res)
 	if err != nil {
-		return err
+		return AsError(call.service, err)
 	}
 
 	_, err = rw.Write(res.Data)
@@ -1,10 +1,18 @@
 package micro
 
 import (
+	"fmt"
+	"net/http"
+
 	"github.com/jexia/maestro/metadata"
+	"github.com/jexia/maestro/transport"
+	microerrors "github.com/micro/go-micro/errors"
 	micrometa "github.com/micro/go-micro/metadata"
 )
 
+// ClientID represents the id of errors produced by the go micro client itself (ex: connection errors)
+const ClientID = "go.micro.client"
+
 // CopyMetadataHeader copies the given metadata header to go micro metadata
 func CopyMetadataHeader(md metadata.MD) micrometa.Metadata {
 	result := make(micrometa.Metadata, len(md))
@@ -15,3 +23,41 @@ func CopyMetadataHeader(md metadata.MD) micrometa.Metadata {
 
 	return result
 }
+
+// AsError maps the given go micro error to a structured error.
+// Timeouts are mapped to timeout errors and errors produced by the client (ex: connection errors) to unavailable errors.
+// Errors returned by the service are mapped to upstream errors including the returned status code.
+// Errors which could not be parsed are classified by their cause.
+func AsError(service string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	parsed := microerrors.Parse(err.Error())
+
+	switch {
+	case parsed.Code == 0:
+		return transport.AsError(err)
+	case parsed.Code == http.StatusRequestTimeout:
+		return &transport.Error{
+			Code:      transport.CodeTimeout,
+			Message:   fmt.Sprintf("service '%s' did not respond in time", service),
+			Retryable: true,
+			Err:       err,
+		}
+	case parsed.Id == ClientID:
+		return &transport.Error{
+			Code:      transport.CodeUnavailable,
+			Message:   parsed.Detail,
+			Retryable: true,
+			Err:       err,
+		}
+	}
+
+	return &transport.Error{
+		Code:    transport.CodeUpstream,
+		Message: fmt.Sprintf("service '%s' responded with status %d", service, parsed.Code),
+		Status:  int(parsed.Code),
+		Err:     err,
+	}
+}
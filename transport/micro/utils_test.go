@@ -0,0 +1,61 @@
+package micro
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/jexia/maestro/transport"
+	microerrors "github.com/micro/go-micro/errors"
+)
+
+func TestAsError(t *testing.T) {
+	type test struct {
+		code   string
+		status int
+	}
+
+	tests := map[string]struct {
+		err      error
+		expected test
+	}{
+		"timeout": {
+			err:      microerrors.Timeout(ClientID, "request timeout"),
+			expected: test{code: transport.CodeTimeout},
+		},
+		"connection": {
+			err:      microerrors.InternalServerError(ClientID, "connection error"),
+			expected: test{code: transport.CodeUnavailable},
+		},
+		"not found": {
+			err:      microerrors.NotFound("com.maestro.users", "user not found"),
+			expected: test{code: transport.CodeUpstream, status: http.StatusNotFound},
+		},
+		"server error": {
+			err:      microerrors.InternalServerError("com.maestro.users", "unexpected error"),
+			expected: test{code: transport.CodeUpstream, status: http.StatusInternalServerError},
+		},
+		"deadline exceeded": {
+			err:      context.DeadlineExceeded,
+			expected: test{code: transport.CodeTimeout},
+		},
+	}
+
+	for name, input := range tests {
+		t.Run(name, func(t *testing.T) {
+			result := transport.AsError(AsError("users", input.err))
+			if result.Code != input.expected.code || result.Status != input.expected.status {
+				t.Fatalf("unexpected error %+v, expected %+v", result, input.expected)
+			}
+
+			if !errors.Is(result, input.err) {
+				t.Fatalf("unexpected error %+v, expected %s to be wrapped", result, input.err)
+			}
+		})
+	}
+
+	if AsError("users", nil) != nil {
+		t.Fatal("unexpected error for nil error")
+	}
+}
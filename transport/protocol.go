@@ -15,6 +15,7 @@ import (
 type ResponseWriter interface {
 	Header() metadata.MD
 	Write([]byte) (int, error)
+	WriteHeader(int)
 }
 
 // Request represents the request object given to a caller implementation used to make calls
@@ -81,12 +82,29 @@ type Flow interface {
 	Wait()
 }
 
+// Rejecter represents a flow which should be notified about requests rejected before the flow has been called
+type Rejecter interface {
+	Reject(ctx context.Context, err error)
+}
+
+// Reject notifies the given flow about a request rejected before the flow has been called.
+// Nothing happens if the given flow does not implement the rejecter interface.
+func Reject(ctx context.Context, flow Flow, err error) {
+	rejecter, is := flow.(Rejecter)
+	if !is {
+		return
+	}
+
+	rejecter.Reject(ctx, err)
+}
+
 // Endpoint represents a transport listener endpoint
 type Endpoint struct {
 	Listener string
 	Flow     Flow
 	Request  *specs.ParameterMap
 	Response *specs.ParameterMap
+	OnError  *specs.OnError
 	Forward  schema.Service
 	Options  specs.Options
 }
@@ -18,6 +18,7 @@ func NewResponseWriter(writer io.Writer) *Writer {
 type Writer struct {
 	writer io.Writer
 	header metadata.MD
+	status int
 }
 
 // Header returns the response header
@@ -29,3 +30,13 @@ func (rw *Writer) Header() metadata.MD {
 func (rw *Writer) Write(bb []byte) (int, error) {
 	return rw.writer.Write(bb)
 }
+
+// WriteHeader sets the response status code
+func (rw *Writer) WriteHeader(status int) {
+	rw.status = status
+}
+
+// Status returns the response status code, zero is returned if no status code has been written
+func (rw *Writer) Status() int {
+	return rw.status
+}